@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/octoberswimmer/p2-github-scheduler/ghscheduler"
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+	"github.com/octoberswimmer/p2/github"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	clearDryRun    bool
+	clearEstimates bool
+)
+
+var clearCmd = &cobra.Command{
+	Use:   "clear <github-url>",
+	Short: "Remove the dates and comments the scheduler wrote",
+	Long: `Clears Expected Start, Expected Completion, and 98% Completion from
+every issue in the project and deletes the scheduler's comments, for
+removing the scheduler from a project. With --estimates, Low Estimate and
+High Estimate are cleared too. Nothing is scheduled.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClear,
+}
+
+func init() {
+	clearCmd.Flags().BoolVar(&clearDryRun, "dry-run", false, "Show the fields and comments that would be removed without changing anything")
+	clearCmd.Flags().BoolVar(&clearEstimates, "estimates", false, "Also clear Low Estimate and High Estimate (on-hold issues keep theirs)")
+	clearCmd.Flags().StringSliceVar(&onHoldStatuses, "on-hold-status", p2.DefaultOnHoldStatuses, "Scheduling Status values that mean on hold (e.g. Parked,Paused)")
+	rootCmd.AddCommand(clearCmd)
+}
+
+func runClear(cmd *cobra.Command, args []string) error {
+	if err := applyConfigFile(cmd); err != nil {
+		return err
+	}
+	if err := configureLogging(); err != nil {
+		return err
+	}
+
+	fieldNames, err := loadFieldNames()
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := authenticate()
+	if err != nil {
+		return err
+	}
+
+	urlInfo, err := github.ParseGitHubURL(ghscheduler.NormalizeURL(args[0]))
+	if err != nil {
+		return fmt.Errorf("invalid GitHub URL: %w", err)
+	}
+	issues, err := fetchIssues(accessToken, urlInfo)
+	if err != nil {
+		return err
+	}
+	if err := readRenamedFields(accessToken, issues, fieldNames); err != nil {
+		return err
+	}
+	if err := markPrivateIssues(accessToken, issues); err != nil {
+		return err
+	}
+
+	currentRepo := os.Getenv("GITHUB_REPOSITORY")
+	if currentRepo == "" && urlInfo.Repo != "" {
+		currentRepo = urlInfo.Owner + "/" + urlInfo.Repo
+	}
+	privacy := p2.NewPrivacyFilter(currentRepo, issues)
+
+	updates := p2.ClearDateUpdates(issues)
+	if clearEstimates {
+		updates = p2.ClearAllUpdatesWithOptions(issues, p2.ConvertOptions{OnHoldStatuses: onHoldStatuses})
+	}
+	if err := runClearAll(accessToken, updates, issues, privacy, fieldNames, clearDryRun); err != nil {
+		return err
+	}
+	deleteAllSchedulingComments(accessToken, issues, privacy, clearDryRun)
+	return nil
+}
+
+// deleteAllSchedulingComments removes the scheduling comment from every issue
+// that has one, or only lists them if noWrite is set
+func deleteAllSchedulingComments(accessToken string, issues map[string]p2.IssueWithProject, privacy *p2.PrivacyFilter, noWrite bool) {
+	fmt.Fprintln(progress, "\nRemoving scheduling comments...")
+	refs := make([]string, 0, len(issues))
+	for ref := range issues {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	found := 0
+	for _, ref := range refs {
+		iwp := issues[ref]
+		if iwp.IsDraft || iwp.Project == nil {
+			continue
+		}
+		client := github.NewClient(accessToken, &github.GitHubRepository{Owner: iwp.Owner, Name: iwp.Repo})
+		id, err := ghscheduler.FindSchedulingComment(client, iwp.IssueNum)
+		if err != nil {
+			logrus.WithFields(privacy.LogFields(iwp.Owner, iwp.Repo, iwp.IssueNum)).Warnf("Failed to check for comment: %v", err)
+			continue
+		}
+		if id == 0 {
+			continue
+		}
+		found++
+		if noWrite {
+			fmt.Fprintf(progress, "  Would delete comment on %s #%d\n", privacy.RedactRepo(iwp.Owner, iwp.Repo), iwp.IssueNum)
+			continue
+		}
+		if err := ghscheduler.DeleteComment(client, id); err != nil {
+			logrus.WithFields(privacy.LogFields(iwp.Owner, iwp.Repo, iwp.IssueNum)).Warnf("Failed to delete comment: %v", err)
+			continue
+		}
+		fmt.Fprintf(progress, "  Deleted comment on %s #%d\n", privacy.RedactRepo(iwp.Owner, iwp.Repo), iwp.IssueNum)
+	}
+	if found == 0 {
+		fmt.Fprintln(progress, "No scheduling comments to remove")
+	}
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -13,26 +16,30 @@ import (
 	"strings"
 )
 
+// githubAPI is the base URL of the GitHub REST API
+var githubAPI = "https://api.github.com"
+
 func main() {
 	var repo string
 	var version string
 	var runnerOS string
 	var runnerArch string
 	var dest string
+	var validateOnly bool
+	var token string
 
 	flag.StringVar(&repo, "repo", "", "repository that hosts the release assets")
 	flag.StringVar(&version, "version", "", "release tag to download")
 	flag.StringVar(&runnerOS, "runner-os", "", "runner operating system")
 	flag.StringVar(&runnerArch, "runner-arch", "", "runner architecture")
 	flag.StringVar(&dest, "dest", "", "destination directory for the binary")
+	flag.BoolVar(&validateOnly, "validate", false, "check inputs and exit without downloading")
+	flag.StringVar(&token, "token", os.Getenv("GITHUB_TOKEN"), "token for authenticated release downloads (defaults to GITHUB_TOKEN)")
 	flag.Parse()
 
-	if repo == "" || version == "" {
-		log.Fatal("both --repo and --version are required")
-	}
-
 	runnerOS = strings.TrimSpace(runnerOS)
 	runnerArch = strings.TrimSpace(runnerArch)
+	token = strings.TrimSpace(token)
 
 	if runnerOS == "" {
 		runnerOS = runtime.GOOS
@@ -41,6 +48,17 @@ func main() {
 		runnerArch = runtime.GOARCH
 	}
 
+	if problems := validateInputs(repo, version, runnerOS, runnerArch, dest); len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem)
+		}
+		os.Exit(1)
+	}
+	if validateOnly {
+		fmt.Println("Inputs OK")
+		return
+	}
+
 	platformKey, err := normalizeOS(runnerOS)
 	if err != nil {
 		log.Fatal(err)
@@ -68,17 +86,52 @@ func main() {
 		log.Fatalf("create dest directory: %v", err)
 	}
 
-	url := fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", repo, version, archiveName)
+	releaseURL := fmt.Sprintf("https://github.com/%s/releases/download/%s", repo, version)
+	sumsName := fmt.Sprintf("SHA256SUMS-%s", version)
+	url := fmt.Sprintf("%s/%s", releaseURL, archiveName)
+	sumsURL := fmt.Sprintf("%s/%s", releaseURL, sumsName)
+	if token != "" {
+		// A token doesn't authorize browser downloads of private release
+		// assets, so fetch them through the API instead
+		assets, err := releaseAssetURLs(githubAPI, repo, version, token)
+		if err != nil {
+			log.Fatalf("look up release assets: %v", err)
+		}
+		var ok bool
+		if url, ok = assets[archiveName]; !ok {
+			log.Fatalf("release %s has no asset %s", version, archiveName)
+		}
+		if sumsURL, ok = assets[sumsName]; !ok {
+			log.Fatalf("release %s has no asset %s", version, sumsName)
+		}
+	}
 	tmpDir, err := os.MkdirTemp("", "p2-scheduler-action-*")
 	if err != nil {
 		log.Fatalf("create temp directory: %v", err)
 	}
 	defer os.RemoveAll(tmpDir)
 
+	sumsPath := filepath.Join(tmpDir, sumsName)
+	if _, err := downloadFile(sumsURL, sumsPath, token); err != nil {
+		log.Fatalf("download checksums: %v", err)
+	}
+	sums, err := os.ReadFile(sumsPath)
+	if err != nil {
+		log.Fatalf("read checksums: %v", err)
+	}
+	expected, err := parseChecksum(string(sums), archiveName)
+	if err != nil {
+		log.Fatalf("read checksums: %v", err)
+	}
+
 	archivePath := filepath.Join(tmpDir, archiveName)
-	if err := downloadFile(url, archivePath); err != nil {
+	actual, err := downloadFile(url, archivePath, token)
+	if err != nil {
 		log.Fatalf("download archive: %v", err)
 	}
+	if err := verifyChecksum(archiveName, expected, actual); err != nil {
+		log.Fatal(err)
+	}
 
 	binaryPath, err := extractBinary(archivePath, binaryName, tmpDir)
 	if err != nil {
@@ -115,6 +168,37 @@ func main() {
 	fmt.Printf("Installed p2-github-scheduler to %s\n", finalPath)
 }
 
+// validateInputs returns a description of each missing or malformed input
+func validateInputs(repo, version, runnerOS, runnerArch, dest string) []string {
+	var problems []string
+	if repo == "" {
+		problems = append(problems, "--repo is required")
+	} else if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		problems = append(problems, fmt.Sprintf("--repo %q must be in owner/repo form", repo))
+	}
+	if version == "" {
+		problems = append(problems, "--version is required")
+	} else if strings.ContainsAny(version, " /") {
+		problems = append(problems, fmt.Sprintf("--version %q is not a valid release tag", version))
+	}
+	if dest == "" {
+		problems = append(problems, "--dest must point to a writable directory")
+	}
+	platformKey, err := normalizeOS(runnerOS)
+	if err != nil {
+		problems = append(problems, err.Error())
+	} else if _, err := normalizeArch(platformKey, runnerArch); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if os.Getenv("GITHUB_PATH") == "" {
+		problems = append(problems, "GITHUB_PATH is not set")
+	}
+	if os.Getenv("GITHUB_OUTPUT") == "" {
+		problems = append(problems, "GITHUB_OUTPUT is not set")
+	}
+	return problems
+}
+
 func normalizeOS(osName string) (string, error) {
 	switch strings.ToLower(osName) {
 	case "linux":
@@ -142,26 +226,96 @@ func normalizeArch(platformKey, arch string) (string, error) {
 	}
 }
 
-func downloadFile(url, dest string) error {
+// releaseAssetURLs returns the API URL of each asset of the release tagged
+// version in repo, keyed by asset name
+func releaseAssetURLs(apiBase, repo, version, token string) (map[string]string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/repos/%s/releases/tags/%s", apiBase, repo, version), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+
+	var release struct {
+		Assets []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"assets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("decode release: %w", err)
+	}
+	urls := make(map[string]string, len(release.Assets))
+	for _, asset := range release.Assets {
+		urls[asset.Name] = asset.URL
+	}
+	return urls, nil
+}
+
+// downloadFile saves url to dest and returns the hex SHA-256 of the downloaded bytes.
+// The request is authenticated when token is non-empty, and asks for the raw
+// bytes so that API asset URLs return the file rather than its metadata.
+func downloadFile(url, dest, token string) (string, error) {
 	fmt.Printf("Downloading %s\n", url)
-	resp, err := http.Get(url)
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return err
+		return "", err
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("unexpected HTTP status %s", resp.Status)
+		return "", fmt.Errorf("unexpected HTTP status %s", resp.Status)
 	}
 
 	out, err := os.Create(dest)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer out.Close()
 
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		return err
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hash), resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// parseChecksum finds the SHA-256 for name in a shasum-style checksums file
+func parseChecksum(sums, name string) (string, error) {
+	for _, line := range strings.Split(sums, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		// shasum marks binary-mode entries with a leading '*'
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// verifyChecksum returns an error if the downloaded hash doesn't match the published one
+func verifyChecksum(name, expected, actual string) error {
+	if !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", name, expected, actual)
 	}
 	return nil
 }
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateInputs_Valid(t *testing.T) {
+	t.Setenv("GITHUB_PATH", "/tmp/path")
+	t.Setenv("GITHUB_OUTPUT", "/tmp/output")
+
+	if problems := validateInputs("octoberswimmer/p2-github-scheduler", "v1.0.0", "Linux", "X64", "/tmp/dest"); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+}
+
+func TestValidateInputs_MissingInputs(t *testing.T) {
+	t.Setenv("GITHUB_PATH", "")
+	t.Setenv("GITHUB_OUTPUT", "")
+
+	problems := validateInputs("", "", "Linux", "X64", "")
+	joined := strings.Join(problems, "\n")
+	for _, want := range []string{"--repo", "--version", "--dest", "GITHUB_PATH", "GITHUB_OUTPUT"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %s to be reported, got %v", want, problems)
+		}
+	}
+}
+
+func TestValidateInputs_UnsupportedPlatform(t *testing.T) {
+	t.Setenv("GITHUB_PATH", "/tmp/path")
+	t.Setenv("GITHUB_OUTPUT", "/tmp/output")
+
+	problems := validateInputs("octoberswimmer/p2-github-scheduler", "v1.0.0", "Windows", "ARM64", "/tmp/dest")
+	if len(problems) != 1 || !strings.Contains(problems[0], "arm64") {
+		t.Errorf("expected unsupported windows arm64 to be reported, got %v", problems)
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	sums := "aaa111  p2-github-scheduler_linux_amd64_v1.0.0.zip\n" +
+		"BBB222 *p2-github-scheduler_darwin_arm64_v1.0.0.zip\n"
+
+	sum, err := parseChecksum(sums, "p2-github-scheduler_darwin_arm64_v1.0.0.zip")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sum != "bbb222" {
+		t.Errorf("expected bbb222, got %s", sum)
+	}
+
+	if _, err := parseChecksum(sums, "p2-github-scheduler_windows_amd64_v1.0.0.zip"); err == nil {
+		t.Error("expected error for archive missing from checksums")
+	}
+}
+
+func TestDownloadFile_VerifiesChecksum(t *testing.T) {
+	body := "archive contents"
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	actual, err := downloadFile(server.URL, filepath.Join(t.TempDir(), "archive.zip"), "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected bearer token to be sent, got %q", auth)
+	}
+
+	sum := sha256.Sum256([]byte(body))
+	if err := verifyChecksum("archive.zip", hex.EncodeToString(sum[:]), actual); err != nil {
+		t.Errorf("expected matching checksum, got %v", err)
+	}
+	err = verifyChecksum("archive.zip", strings.Repeat("0", 64), actual)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected checksum mismatch, got %v", err)
+	}
+}
+
+func TestReleaseAssetURLs_DownloadsPrivateAssetThroughAPI(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		switch r.URL.Path {
+		case "/repos/owner/repo/releases/tags/v1.0.0":
+			fmt.Fprintf(w, `{"assets": [{"name": "archive.zip", "url": "%s/repos/owner/repo/releases/assets/7", "browser_download_url": "https://github.com/owner/repo/releases/download/v1.0.0/archive.zip"}]}`, server.URL)
+		case "/repos/owner/repo/releases/assets/7":
+			if r.Header.Get("Accept") != "application/octet-stream" {
+				w.Write([]byte(`{"name": "archive.zip"}`))
+				return
+			}
+			w.Write([]byte("archive contents"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	assets, err := releaseAssetURLs(server.URL, "owner/repo", "v1.0.0", "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	url, ok := assets["archive.zip"]
+	if !ok {
+		t.Fatalf("expected archive.zip among the release assets, got %v", assets)
+	}
+
+	dest := filepath.Join(t.TempDir(), "archive.zip")
+	if _, err := downloadFile(url, dest, "secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "archive contents" {
+		t.Errorf("expected the asset's bytes, got %q (%v)", data, err)
+	}
+}
+
+func TestExtractBinary_WindowsZip(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "p2-github-scheduler_windows_amd64_v1.0.0.zip")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("p2-github-scheduler.exe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("MZ"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	extracted, err := extractBinary(archivePath, "p2-github-scheduler.exe", filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Base(extracted) != "p2-github-scheduler.exe" {
+		t.Errorf("expected p2-github-scheduler.exe, got %s", extracted)
+	}
+	if data, err := os.ReadFile(extracted); err != nil || string(data) != "MZ" {
+		t.Errorf("expected extracted contents MZ, got %q (%v)", data, err)
+	}
+}
@@ -7,29 +7,55 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// apiBaseURL is the GitHub REST API root; overridden in tests
+var apiBaseURL = "https://api.github.com"
+
+// retryBaseDelay is the wait before the first retry; it doubles on each attempt
+var retryBaseDelay = time.Second
+
 func main() {
 	var requested string
 	var repo string
 	var fallback string
+	var validateOnly bool
+	var attempts int
+	var token string
 
 	flag.StringVar(&requested, "requested", "", "requested release tag (use 'latest' to resolve dynamically)")
 	flag.StringVar(&repo, "repo", "", "value of github.action_repository")
 	flag.StringVar(&fallback, "fallback", "", "value of github.repository (fallback)")
+	flag.BoolVar(&validateOnly, "validate", false, "check inputs and exit without contacting GitHub")
+	flag.StringVar(&token, "token", os.Getenv("GITHUB_TOKEN"), "token for authenticated release lookups (defaults to GITHUB_TOKEN)")
+	flag.IntVar(&attempts, "attempts", 3, "number of attempts for each GitHub API request on 5xx or network errors")
 	flag.Parse()
 
 	if repo == "" {
 		repo = fallback
 	}
-	if repo == "" {
-		log.Fatal("unable to determine repository that hosts the action")
+
+	problems := validateInputs(repo, requested)
+	if attempts < 1 {
+		problems = append(problems, "--attempts must be at least 1")
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem)
+		}
+		os.Exit(1)
+	}
+	if validateOnly {
+		fmt.Println("Inputs OK")
+		return
 	}
 
 	version := strings.TrimSpace(requested)
 	if version == "" || version == "latest" {
-		resolved, err := resolveLatestTag(repo)
+		resolved, err := resolveLatestTag(repo, strings.TrimSpace(token), attempts)
 		if err != nil {
 			log.Fatalf("resolve latest release: %v", err)
 		}
@@ -54,23 +80,40 @@ func main() {
 	fmt.Printf("Resolved release %q in repository %q\n", version, repo)
 }
 
-func resolveLatestTag(repo string) (string, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", repo), nil)
-	if err != nil {
-		return "", err
+// validateInputs returns a description of each missing or malformed input
+func validateInputs(repo, requested string) []string {
+	var problems []string
+	if repo == "" {
+		problems = append(problems, "unable to determine repository that hosts the action (set --repo or --fallback)")
+	} else if !validRepo(repo) {
+		problems = append(problems, fmt.Sprintf("repository %q must be in owner/repo form", repo))
+	}
+	if strings.ContainsAny(strings.TrimSpace(requested), " /") {
+		problems = append(problems, fmt.Sprintf("requested version %q is not a valid release tag", requested))
 	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	resp, err := http.DefaultClient.Do(req)
+	if os.Getenv("GITHUB_OUTPUT") == "" {
+		problems = append(problems, "GITHUB_OUTPUT is not set")
+	}
+	return problems
+}
+
+func validRepo(repo string) bool {
+	owner, name, ok := strings.Cut(repo, "/")
+	return ok && owner != "" && name != "" && !strings.Contains(name, "/")
+}
+
+func resolveLatestTag(repo, token string, attempts int) (string, error) {
+	resp, err := getWithRetry(fmt.Sprintf("%s/repos/%s/releases/latest", apiBaseURL, repo), token, attempts)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return latestFromList(repo)
+		return latestFromList(repo, token, attempts)
 	}
 	if resp.StatusCode >= 400 {
-		return "", fmt.Errorf("unexpected HTTP status %s", resp.Status)
+		return "", statusError(resp)
 	}
 
 	var payload struct {
@@ -86,20 +129,15 @@ func resolveLatestTag(repo string) (string, error) {
 	return payload.Tag, nil
 }
 
-func latestFromList(repo string) (string, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://api.github.com/repos/%s/releases?per_page=1", repo), nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	resp, err := http.DefaultClient.Do(req)
+func latestFromList(repo, token string, attempts int) (string, error) {
+	resp, err := getWithRetry(fmt.Sprintf("%s/repos/%s/releases?per_page=1", apiBaseURL, repo), token, attempts)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return "", fmt.Errorf("unexpected HTTP status %s", resp.Status)
+		return "", statusError(resp)
 	}
 
 	var releases []struct {
@@ -119,3 +157,54 @@ func latestFromList(repo string) (string, error) {
 	}
 	return "", fmt.Errorf("no published releases found")
 }
+
+// getWithRetry issues a GitHub API GET, retrying network errors and 5xx responses
+// with exponential backoff. Other responses, including 404, are returned as-is.
+// The request is authenticated when token is non-empty.
+func getWithRetry(url, token string, attempts int) (*http.Response, error) {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if attempt >= attempts {
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Request failed (%v); retrying in %s\n", err, delay)
+		} else {
+			fmt.Fprintf(os.Stderr, "Request returned %s; retrying in %s\n", resp.Status, delay)
+			resp.Body.Close()
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// statusError describes an unsuccessful response, including GitHub's rate limit
+// hint when the request was rejected for exceeding it
+func statusError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+			return fmt.Errorf("unexpected HTTP status %s (rate limited; retry after %ss)", resp.Status, retryAfter)
+		}
+		if reset := resp.Header.Get("X-RateLimit-Reset"); reset != "" {
+			if epoch, err := strconv.ParseInt(reset, 10, 64); err == nil {
+				return fmt.Errorf("unexpected HTTP status %s (rate limit resets at %s)", resp.Status, time.Unix(epoch, 0).UTC().Format(time.RFC3339))
+			}
+		}
+	}
+	return fmt.Errorf("unexpected HTTP status %s", resp.Status)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateInputs_Valid(t *testing.T) {
+	t.Setenv("GITHUB_OUTPUT", "/tmp/output")
+
+	if problems := validateInputs("octoberswimmer/p2-github-scheduler", "latest"); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+}
+
+func TestValidateInputs_MissingInputs(t *testing.T) {
+	t.Setenv("GITHUB_OUTPUT", "")
+
+	problems := validateInputs("", "latest")
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %v", problems)
+	}
+	joined := strings.Join(problems, "\n")
+	if !strings.Contains(joined, "repository") {
+		t.Error("expected missing repository to be reported")
+	}
+	if !strings.Contains(joined, "GITHUB_OUTPUT") {
+		t.Error("expected missing GITHUB_OUTPUT to be reported")
+	}
+}
+
+func TestValidateInputs_MalformedRepo(t *testing.T) {
+	t.Setenv("GITHUB_OUTPUT", "/tmp/output")
+
+	problems := validateInputs("not-a-repo", "v1.0.0")
+	if len(problems) != 1 || !strings.Contains(problems[0], "owner/repo") {
+		t.Errorf("expected malformed repo to be reported, got %v", problems)
+	}
+}
+
+func TestResolveLatestTag_RetriesServerErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"tag_name": "v1.2.0"}`))
+	}))
+	defer server.Close()
+	apiBaseURL, retryBaseDelay = server.URL, 0
+	defer func() { apiBaseURL, retryBaseDelay = "https://api.github.com", time.Second }()
+
+	tag, err := resolveLatestTag("owner/repo", "", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag != "v1.2.0" || calls != 3 {
+		t.Errorf("expected v1.2.0 after 3 calls, got %q after %d", tag, calls)
+	}
+
+	calls = 0
+	if _, err := resolveLatestTag("owner/repo", "", 2); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("expected 502 after exhausting attempts, got %v", err)
+	}
+}
+
+func TestResolveLatestTag_NotFoundFallsBackToList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/latest") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[{"tag_name": "v0.9.0-rc1", "draft": false}]`))
+	}))
+	defer server.Close()
+	apiBaseURL, retryBaseDelay = server.URL, 0
+	defer func() { apiBaseURL, retryBaseDelay = "https://api.github.com", time.Second }()
+
+	tag, err := resolveLatestTag("owner/repo", "", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag != "v0.9.0-rc1" {
+		t.Errorf("expected v0.9.0-rc1, got %q", tag)
+	}
+}
+
+func TestResolveLatestTag_RateLimitHint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Reset", "1767225600")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	apiBaseURL, retryBaseDelay = server.URL, 0
+	defer func() { apiBaseURL, retryBaseDelay = "https://api.github.com", time.Second }()
+
+	_, err := resolveLatestTag("owner/repo", "", 3)
+	if err == nil || !strings.Contains(err.Error(), "rate limit resets at 2026-01-01T00:00:00Z") {
+		t.Errorf("expected rate limit reset hint, got %v", err)
+	}
+}
+
+func TestResolveLatestTag_SendsToken(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"tag_name": "v1.2.0"}`))
+	}))
+	defer server.Close()
+	apiBaseURL, retryBaseDelay = server.URL, 0
+	defer func() { apiBaseURL, retryBaseDelay = "https://api.github.com", time.Second }()
+
+	if _, err := resolveLatestTag("owner/repo", "secret", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := resolveLatestTag("owner/repo", "", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(auth) != 2 || auth[0] != "Bearer secret" || auth[1] != "" {
+		t.Errorf("expected Authorization only when a token is set, got %q", auth)
+	}
+}
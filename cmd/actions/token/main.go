@@ -2,34 +2,60 @@ package main
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 )
 
+// retryBaseDelay is the wait before the first retry; it doubles on each attempt
+var retryBaseDelay = time.Second
+
+// maxErrorBody is how much of a failed broker response is included in errors
+const maxErrorBody = 512
+
 func main() {
 	var brokerURL string
+	var validateOnly bool
+	var publicKeyFlag string
+	var audience string
+	var attempts int
 
 	flag.StringVar(&brokerURL, "broker-url", "", "URL of the p2-penny-pusher token broker")
+	flag.StringVar(&audience, "audience", "p2-penny-pusher", "audience to request the GitHub Actions OIDC token for (must match the token broker)")
+	flag.IntVar(&attempts, "attempts", 3, "number of attempts for the token exchange on 5xx or network errors")
+	flag.StringVar(&publicKeyFlag, "license-public-key", os.Getenv("P2_LICENSE_PUBLIC_KEY"), "base64 Ed25519 public key to verify the broker's license signature with (optional)")
+	flag.BoolVar(&validateOnly, "validate", false, "check inputs and exit without requesting tokens")
 	flag.Parse()
 
-	if brokerURL == "" {
-		log.Fatal("--broker-url is required")
+	if problems := validateInputs(brokerURL, audience, publicKeyFlag, attempts); len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintln(os.Stderr, problem)
+		}
+		os.Exit(1)
+	}
+	if validateOnly {
+		fmt.Println("Inputs OK")
+		return
 	}
 
 	// Get OIDC token from GitHub Actions environment
-	oidcToken, err := getOIDCToken()
+	oidcToken, err := getOIDCToken(audience)
 	if err != nil {
 		log.Fatalf("get OIDC token: %v", err)
 	}
 
 	// Exchange OIDC token for installation token + license
-	installToken, licenseKey, err := exchangeToken(brokerURL, oidcToken)
+	publicKey, _ := parsePublicKey(publicKeyFlag)
+	installToken, licenseKey, err := exchangeToken(brokerURL, oidcToken, publicKey, attempts)
 	if err != nil {
 		log.Fatalf("exchange token: %v", err)
 	}
@@ -64,7 +90,36 @@ func main() {
 	fmt.Println("Successfully obtained installation token")
 }
 
-func getOIDCToken() (string, error) {
+// validateInputs returns a description of each missing or malformed input
+func validateInputs(brokerURL, audience, publicKey string, attempts int) []string {
+	var problems []string
+	if brokerURL == "" {
+		problems = append(problems, "--broker-url is required")
+	} else if u, err := url.Parse(brokerURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("--broker-url %q must be an http(s) URL", brokerURL))
+	}
+	if strings.TrimSpace(audience) == "" {
+		problems = append(problems, "--audience must not be empty")
+	}
+	if attempts < 1 {
+		problems = append(problems, "--attempts must be at least 1")
+	}
+	if _, err := parsePublicKey(publicKey); err != nil {
+		problems = append(problems, fmt.Sprintf("--license-public-key: %v", err))
+	}
+	if os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL") == "" {
+		problems = append(problems, "ACTIONS_ID_TOKEN_REQUEST_URL is not set (ensure id-token: write permission)")
+	}
+	if os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN") == "" {
+		problems = append(problems, "ACTIONS_ID_TOKEN_REQUEST_TOKEN is not set (ensure id-token: write permission)")
+	}
+	if os.Getenv("GITHUB_OUTPUT") == "" {
+		problems = append(problems, "GITHUB_OUTPUT is not set")
+	}
+	return problems
+}
+
+func getOIDCToken(audience string) (string, error) {
 	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
 	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
 
@@ -73,7 +128,7 @@ func getOIDCToken() (string, error) {
 	}
 
 	// Add audience parameter
-	url := requestURL + "&audience=p2-penny-pusher"
+	url := requestURL + "&audience=" + url.QueryEscape(audience)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -106,7 +161,7 @@ func getOIDCToken() (string, error) {
 	return result.Value, nil
 }
 
-func exchangeToken(brokerURL, oidcToken string) (string, string, error) {
+func exchangeToken(brokerURL, oidcToken string, publicKey ed25519.PublicKey, attempts int) (string, string, error) {
 	// Normalize broker URL - remove trailing /token if present
 	brokerURL = strings.TrimSuffix(brokerURL, "/token")
 	brokerURL = strings.TrimSuffix(brokerURL, "/")
@@ -119,9 +174,9 @@ func exchangeToken(brokerURL, oidcToken string) (string, string, error) {
 		return "", "", err
 	}
 
-	resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	resp, err := postWithRetry(url, body, attempts)
 	if err != nil {
-		return "", "", err
+		return "", "", fmt.Errorf("broker request failed after %d attempts: %w", attempts, err)
 	}
 	defer resp.Body.Close()
 
@@ -131,7 +186,7 @@ func exchangeToken(brokerURL, oidcToken string) (string, string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("broker returned status %d", resp.StatusCode)
+		return "", "", brokerStatusError(resp.StatusCode, respBody, attempts)
 	}
 
 	var result struct {
@@ -148,7 +203,7 @@ func exchangeToken(brokerURL, oidcToken string) (string, string, error) {
 		return "", "", fmt.Errorf("broker response missing token")
 	}
 
-	licenseKey, err := buildLicenseKey(result.Token, result.MaxIssues, result.PublicOnly, result.Signature)
+	licenseKey, err := buildLicenseKey(result.Token, result.MaxIssues, result.PublicOnly, result.Signature, publicKey)
 	if err != nil {
 		return "", "", err
 	}
@@ -156,7 +211,65 @@ func exchangeToken(brokerURL, oidcToken string) (string, string, error) {
 	return result.Token, licenseKey, nil
 }
 
-func buildLicenseKey(token string, maxIssues *int64, publicOnly *bool, signature *string) (string, error) {
+// postWithRetry POSTs a JSON body, retrying network errors and 5xx responses
+// with exponential backoff. Other responses are returned as-is.
+func postWithRetry(url string, body []byte, attempts int) (*http.Response, error) {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+		if attempt >= attempts {
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Broker request failed (%v); retrying in %s\n", err, delay)
+		} else {
+			fmt.Fprintf(os.Stderr, "Broker returned %s; retrying in %s\n", resp.Status, delay)
+			resp.Body.Close()
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// brokerStatusError describes an unsuccessful broker response, telling
+// rejected credentials apart from broker outages and including the start of
+// the response body
+func brokerStatusError(status int, body []byte, attempts int) error {
+	detail := strings.TrimSpace(string(body))
+	if len(detail) > maxErrorBody {
+		detail = detail[:maxErrorBody] + "..."
+	}
+	if detail != "" {
+		detail = ": " + detail
+	}
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return fmt.Errorf("broker rejected the OIDC token with status %d (check the id-token: write permission, the --audience, and that the app is installed)%s", status, detail)
+	case status >= 500:
+		return fmt.Errorf("broker unavailable: status %d after %d attempts%s", status, attempts, detail)
+	default:
+		return fmt.Errorf("broker returned status %d%s", status, detail)
+	}
+}
+
+// licenseKey is the license passed to the scheduler as P2_LICENSE_KEY
+type licenseKey struct {
+	Token      string `json:"t"`
+	MaxIssues  int64  `json:"n"`
+	PublicOnly bool   `json:"p"`
+	Signature  string `json:"s,omitempty"`
+}
+
+// buildLicenseKey packs the broker's license fields into a license key. With a
+// publicKey, the signature must be a base64 Ed25519 signature that verifies
+// over the key's JSON without the signature.
+func buildLicenseKey(token string, maxIssues *int64, publicOnly *bool, signature *string, publicKey ed25519.PublicKey) (string, error) {
 	if strings.TrimSpace(token) == "" {
 		return "", fmt.Errorf("broker response missing token")
 	}
@@ -164,22 +277,33 @@ func buildLicenseKey(token string, maxIssues *int64, publicOnly *bool, signature
 		return "", fmt.Errorf("broker response missing license fields")
 	}
 
-	payload := struct {
-		Token      string `json:"t"`
-		MaxIssues  int64  `json:"n"`
-		PublicOnly bool   `json:"p"`
-		Signature  string `json:"s"`
-	}{
+	payload := licenseKey{
 		Token:      token,
 		MaxIssues:  *maxIssues,
 		PublicOnly: *publicOnly,
-		Signature:  strings.TrimSpace(*signature),
 	}
-
-	if payload.Signature == "" {
+	encodedSig := strings.TrimSpace(*signature)
+	if encodedSig == "" {
 		return "", fmt.Errorf("broker response missing license signature")
 	}
 
+	// The signature is only decoded when there's a key to check it against;
+	// otherwise it is passed through in whatever form the broker uses
+	if publicKey != nil {
+		sig, err := decodeSignature(encodedSig)
+		if err != nil {
+			return "", err
+		}
+		message, err := json.Marshal(payload)
+		if err != nil {
+			return "", fmt.Errorf("encode license key: %w", err)
+		}
+		if !ed25519.Verify(publicKey, message, sig) {
+			return "", fmt.Errorf("license signature does not verify with --license-public-key")
+		}
+	}
+
+	payload.Signature = encodedSig
 	key, err := json.Marshal(payload)
 	if err != nil {
 		return "", fmt.Errorf("encode license key: %w", err)
@@ -187,3 +311,36 @@ func buildLicenseKey(token string, maxIssues *int64, publicOnly *bool, signature
 
 	return string(key), nil
 }
+
+// decodeSignature decodes a base64 license signature, checking it is the
+// length of an Ed25519 signature
+func decodeSignature(encoded string) ([]byte, error) {
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		sig, err = base64.RawURLEncoding.DecodeString(encoded)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("license signature is not valid base64")
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("license signature is %d bytes, expected %d", len(sig), ed25519.SignatureSize)
+	}
+	return sig, nil
+}
+
+// parsePublicKey decodes a base64 Ed25519 public key. An empty key returns
+// nil, disabling signature verification.
+func parsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("not valid base64")
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%d bytes, expected a %d-byte Ed25519 public key", len(key), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
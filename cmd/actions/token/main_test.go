@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateInputs_Valid(t *testing.T) {
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "https://token.actions.example/?x=1")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+	t.Setenv("GITHUB_OUTPUT", "/tmp/output")
+
+	if problems := validateInputs("https://penny-pusher.octoberswimmer.com", "p2-penny-pusher", "", 3); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+}
+
+func TestValidateInputs_MissingInputs(t *testing.T) {
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+	t.Setenv("GITHUB_OUTPUT", "")
+
+	problems := validateInputs("", "", "", 0)
+	joined := strings.Join(problems, "\n")
+	for _, want := range []string{"--broker-url", "--audience", "--attempts", "ACTIONS_ID_TOKEN_REQUEST_URL", "ACTIONS_ID_TOKEN_REQUEST_TOKEN", "GITHUB_OUTPUT"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %s to be reported, got %v", want, problems)
+		}
+	}
+}
+
+func TestValidateInputs_MalformedBrokerURL(t *testing.T) {
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "https://token.actions.example/?x=1")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+	t.Setenv("GITHUB_OUTPUT", "/tmp/output")
+
+	problems := validateInputs("penny-pusher.octoberswimmer.com", "p2-penny-pusher", "", 3)
+	if len(problems) != 1 || !strings.Contains(problems[0], "http(s)") {
+		t.Errorf("expected malformed broker URL to be reported, got %v", problems)
+	}
+}
+
+func TestBuildLicenseKey_VerifiesSignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxIssues, publicOnly := int64(100), true
+	message, _ := json.Marshal(licenseKey{Token: "ghs_abc", MaxIssues: maxIssues, PublicOnly: publicOnly})
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, message))
+
+	key, err := buildLicenseKey("ghs_abc", &maxIssues, &publicOnly, &signature, publicKey)
+	if err != nil {
+		t.Fatalf("expected signed license to verify, got %v", err)
+	}
+	if !strings.Contains(key, `"s":"`+signature+`"`) {
+		t.Errorf("expected license key to carry the signature, got %s", key)
+	}
+
+	tampered := int64(100000)
+	if _, err := buildLicenseKey("ghs_abc", &tampered, &publicOnly, &signature, publicKey); err == nil {
+		t.Error("expected tampered license fields to fail verification")
+	}
+
+	// Without a public key the signature isn't checked
+	if _, err := buildLicenseKey("ghs_abc", &tampered, &publicOnly, &signature, nil); err != nil {
+		t.Errorf("expected signature to pass without a public key, got %v", err)
+	}
+}
+
+func TestBuildLicenseKey_MalformedSignature(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxIssues, publicOnly := int64(100), false
+	for _, signature := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := buildLicenseKey("ghs_abc", &maxIssues, &publicOnly, &signature, publicKey); err == nil {
+			t.Errorf("expected signature %q to be rejected", signature)
+		}
+	}
+}
+
+func TestBuildLicenseKey_PassesThroughSignatureWithoutPublicKey(t *testing.T) {
+	maxIssues, publicOnly := int64(100), false
+	// A broker signing with another scheme, e.g. an HMAC
+	signature := base64.StdEncoding.EncodeToString([]byte("32-byte-hmac-sha256-signature!!!"))
+	key, err := buildLicenseKey("ghs_abc", &maxIssues, &publicOnly, &signature, nil)
+	if err != nil {
+		t.Fatalf("expected signature to be passed through, got %v", err)
+	}
+	if !strings.Contains(key, `"s":"`+signature+`"`) {
+		t.Errorf("expected license key to carry the signature unchanged, got %s", key)
+	}
+}
+
+func TestValidateInputs_MalformedPublicKey(t *testing.T) {
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "https://token.actions.example/?x=1")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+	t.Setenv("GITHUB_OUTPUT", "/tmp/output")
+
+	problems := validateInputs("https://penny-pusher.octoberswimmer.com", "p2-penny-pusher", base64.StdEncoding.EncodeToString([]byte("too short")), 3)
+	if len(problems) != 1 || !strings.Contains(problems[0], "--license-public-key") {
+		t.Errorf("expected malformed public key to be reported, got %v", problems)
+	}
+}
+
+func TestGetOIDCToken_RequestsAudience(t *testing.T) {
+	var gotAudience, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAudience = r.URL.Query().Get("audience")
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"value":"oidc-token"}`))
+	}))
+	defer server.Close()
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"/?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+
+	token, err := getOIDCToken("https://broker.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "oidc-token" {
+		t.Errorf("expected oidc-token, got %q", token)
+	}
+	if gotAudience != "https://broker.example.com" {
+		t.Errorf("expected audience to be requested, got %q", gotAudience)
+	}
+	if gotAuth != "bearer request-token" {
+		t.Errorf("expected request token to be sent, got %q", gotAuth)
+	}
+}
+
+func TestExchangeToken_RetriesTransientErrors(t *testing.T) {
+	retryBaseDelay = 0
+	defer func() { retryBaseDelay = time.Second }()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, "upstream timeout", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"token":"ghs_abc","n":100,"p":false,"s":"` + base64.StdEncoding.EncodeToString(make([]byte, ed25519.SignatureSize)) + `"}`))
+	}))
+	defer server.Close()
+
+	token, license, err := exchangeToken(server.URL, "oidc-token", nil, 3)
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if calls != 2 || token != "ghs_abc" || license == "" {
+		t.Errorf("expected token and license after 2 calls, got %d calls, token %q, license %q", calls, token, license)
+	}
+}
+
+func TestExchangeToken_ReportsStatusKind(t *testing.T) {
+	retryBaseDelay = 0
+	defer func() { retryBaseDelay = time.Second }()
+
+	tests := []struct {
+		status    int
+		wantCalls int
+		want      string
+	}{
+		{http.StatusForbidden, 1, "rejected the OIDC token with status 403"},
+		{http.StatusServiceUnavailable, 2, "unavailable: status 503 after 2 attempts"},
+	}
+	for _, tt := range tests {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			http.Error(w, `{"error":"`+strings.Repeat("x", 1000)+`"}`, tt.status)
+		}))
+		_, _, err := exchangeToken(server.URL, "oidc-token", nil, 2)
+		server.Close()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("status %d: expected error containing %q, got %v", tt.status, tt.want, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), `{"error":"xxx`) || len(err.Error()) > maxErrorBody+300 {
+			t.Errorf("status %d: expected a truncated body in the error, got %d bytes", tt.status, len(err.Error()))
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("status %d: expected %d calls, got %d", tt.status, tt.wantCalls, calls)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/octoberswimmer/p2-github-scheduler/ghscheduler"
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+	"github.com/octoberswimmer/p2/github"
+	"github.com/spf13/cobra"
+)
+
+var setEstimatesCmd = &cobra.Command{
+	Use:   "set-estimates <github-url> <estimates.csv>",
+	Short: "Write Low/High Estimate fields from a CSV file",
+	Long: `Reads rows of "owner/repo#N,low,high" and writes each issue's
+Low Estimate and High Estimate project fields. Issues in the file that
+aren't in the project are reported and skipped.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSetEstimates,
+}
+
+func init() {
+	rootCmd.AddCommand(setEstimatesCmd)
+}
+
+func runSetEstimates(cmd *cobra.Command, args []string) error {
+	if err := applyConfigFile(cmd); err != nil {
+		return err
+	}
+	if err := configureLogging(); err != nil {
+		return err
+	}
+
+	fieldNames, err := loadFieldNames()
+	if err != nil {
+		return err
+	}
+
+	rows, err := p2.LoadEstimateCSV(args[1])
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := authenticate()
+	if err != nil {
+		return err
+	}
+
+	urlInfo, err := github.ParseGitHubURL(ghscheduler.NormalizeURL(args[0]))
+	if err != nil {
+		return fmt.Errorf("invalid GitHub URL: %w", err)
+	}
+	issues, err := fetchIssues(accessToken, urlInfo)
+	if err != nil {
+		return err
+	}
+
+	written, unknown, err := ghscheduler.SetEstimates(ghscheduler.GraphQLFieldWriter{Token: accessToken}, rows, issues, fieldNames)
+	for _, ref := range unknown {
+		fmt.Printf("  Not in project: %s\n", ref)
+	}
+	fmt.Printf("Set estimates on %d of %d issues\n", written, len(rows))
+	return err
+}
@@ -0,0 +1,67 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+)
+
+// ParseAnnotationLevels validates a map of scheduling issue reason to GitHub
+// Actions annotation level (error, warning, or notice)
+func ParseAnnotationLevels(m map[string]string) (map[string]string, error) {
+	levels := make(map[string]string, len(m))
+	for reason, level := range m {
+		switch l := strings.ToLower(strings.TrimSpace(level)); l {
+		case "error", "warning", "notice":
+			levels[reason] = l
+		default:
+			return nil, fmt.Errorf("invalid annotation level %q for %q (expected error, warning, or notice)", level, reason)
+		}
+	}
+	return levels, nil
+}
+
+// AnnotationLevel returns the annotation level for a scheduling issue: the
+// level configured for its reason, otherwise error for blocking reasons and
+// warning for the rest
+func AnnotationLevel(si p2.SchedulingIssue, levels map[string]string) string {
+	if l, ok := levels[si.Reason]; ok {
+		return l
+	}
+	if Severity(si) == SeverityBlocking {
+		return "error"
+	}
+	return "warning"
+}
+
+// WriteAnnotations writes a GitHub Actions workflow command (e.g. "::error
+// title=cycle::owner/repo#1: ...") for each scheduling issue, so problems show
+// up in the run summary. Issue references and details are redacted by privacy.
+func WriteAnnotations(w io.Writer, schedIssues []p2.SchedulingIssue, levels map[string]string, privacy *p2.PrivacyFilter) error {
+	for _, si := range schedIssues {
+		msg := privacy.RedactDepID(fmt.Sprintf("%s/%s#%d", si.Owner, si.Repo, si.IssueNum)) + ": " + si.Reason
+		if len(si.Details) > 0 {
+			details := make([]string, len(si.Details))
+			for i, d := range si.Details {
+				details[i] = privacy.RedactDepID(d)
+			}
+			msg += " (" + strings.Join(details, "; ") + ")"
+		}
+		if _, err := fmt.Fprintf(w, "::%s title=%s::%s\n", AnnotationLevel(si, levels), escapeProperty(si.Reason), escapeData(msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeData escapes a workflow command message
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a workflow command property value
+func escapeProperty(s string) string {
+	return strings.NewReplacer(":", "%3A", ",", "%2C").Replace(escapeData(s))
+}
@@ -0,0 +1,40 @@
+package ghscheduler
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+)
+
+func TestWriteAnnotations(t *testing.T) {
+	schedIssues := []p2.SchedulingIssue{
+		{Owner: "owner", Repo: "repo", IssueNum: 1, Reason: "cycle", Details: []string{"owner/repo#1", "owner/secret#2"}},
+		{Owner: "owner", Repo: "repo", IssueNum: 3, Reason: "at_risk", Details: []string{"Due: 2026-03-01\nExpected: 50%"}},
+		{Owner: "owner", Repo: "repo", IssueNum: 4, Reason: "missing_estimate"},
+	}
+	privacy := p2.NewPrivacyFilter("owner/repo", map[string]p2.IssueWithProject{
+		"github.com/owner/secret/issues/2": {Owner: "owner", Repo: "secret", IssueNum: 2, IsPrivate: true},
+	})
+	levels, err := ParseAnnotationLevels(map[string]string{"missing_estimate": "Notice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAnnotations(&buf, schedIssues, levels, privacy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "::error title=cycle::owner/repo#1: cycle (owner/repo#1; [private]#2)\n" +
+		"::warning title=at_risk::owner/repo#3: at_risk (Due: 2026-03-01%0AExpected: 50%25)\n" +
+		"::notice title=missing_estimate::owner/repo#4: missing_estimate\n"
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+func TestParseAnnotationLevels_RejectsUnknownLevel(t *testing.T) {
+	if _, err := ParseAnnotationLevels(map[string]string{"cycle": "fatal"}); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
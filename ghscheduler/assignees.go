@@ -0,0 +1,47 @@
+package ghscheduler
+
+// FetchAssignees returns the logins assigned to the issue behind each project
+// item ID. Items that aren't issues, or have no assignees, are omitted.
+func FetchAssignees(token string, itemIDs []string) (map[string][]string, error) {
+	query := `query($ids: [ID!]!) {
+  nodes(ids: $ids) {
+    ... on ProjectV2Item {
+      id
+      content {
+        ... on Issue {
+          assignees(first: 20) { nodes { login } }
+        }
+      }
+    }
+  }
+}`
+
+	assignees := make(map[string][]string)
+	for start := 0; start < len(itemIDs); start += fieldValueBatchSize {
+		end := min(start+fieldValueBatchSize, len(itemIDs))
+		var result struct {
+			Nodes []struct {
+				ID      string `json:"id"`
+				Content *struct {
+					Assignees struct {
+						Nodes []struct {
+							Login string `json:"login"`
+						} `json:"nodes"`
+					} `json:"assignees"`
+				} `json:"content"`
+			} `json:"nodes"`
+		}
+		if err := graphQL(token, query, map[string]interface{}{"ids": itemIDs[start:end]}, &result); err != nil {
+			return nil, err
+		}
+		for _, node := range result.Nodes {
+			if node.Content == nil {
+				continue
+			}
+			for _, a := range node.Content.Assignees.Nodes {
+				assignees[node.ID] = append(assignees[node.ID], a.Login)
+			}
+		}
+	}
+	return assignees, nil
+}
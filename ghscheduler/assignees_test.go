@@ -0,0 +1,31 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchAssignees(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"nodes":[
+			{"id":"item-1","content":{"assignees":{"nodes":[{"login":"alice"},{"login":"bob"}]}}},
+			{"id":"item-2","content":{"assignees":{"nodes":[]}}},
+			{"id":"item-3","content":{}}
+		]}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	assignees, err := FetchAssignees("test-token", []string{"item-1", "item-2", "item-3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(assignees) != 1 || len(assignees["item-1"]) != 2 || assignees["item-1"][1] != "bob" {
+		t.Errorf("expected item-1 assigned to alice and bob, got %v", assignees)
+	}
+}
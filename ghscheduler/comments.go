@@ -10,11 +10,38 @@ import (
 // SchedulingCommentMarker is the HTML comment marker used to identify scheduler comments
 const SchedulingCommentMarker = "<!-- p2-scheduler-comment -->"
 
+// Severity levels for scheduling issues
+const (
+	SeverityBlocking = "blocking"
+	SeverityWarning  = "warning"
+)
+
+// Comment headings for each severity
+const (
+	blockingHeading = "🚫 Cannot Be Scheduled"
+	warningHeading  = "⚠️ At Risk"
+)
+
+// Severity returns whether a scheduling issue prevents scheduling (blocking)
+// or only warns about a scheduled issue (warning)
+func Severity(si github.SchedulingIssue) string {
+	switch si.Reason {
+	case "at_risk", "package_overcommit", "overcommitted_milestone", "unknown_assignee":
+		return SeverityWarning
+	default:
+		return SeverityBlocking
+	}
+}
+
 // FormatSchedulingComment creates a comment body for a scheduling issue
 func FormatSchedulingComment(si github.SchedulingIssue) string {
 	var sb strings.Builder
 	sb.WriteString(SchedulingCommentMarker)
-	sb.WriteString("\n**Scheduling Notice**\n\n")
+	heading := blockingHeading
+	if Severity(si) == SeverityWarning {
+		heading = warningHeading
+	}
+	sb.WriteString(fmt.Sprintf("\n**%s**\n\n", heading))
 
 	switch si.Reason {
 	case "cycle":
@@ -33,6 +60,9 @@ func FormatSchedulingComment(si github.SchedulingIssue) string {
 		for _, dep := range si.Details {
 			sb.WriteString(fmt.Sprintf("- %s\n", dep))
 		}
+	case "self_dependency":
+		sb.WriteString("This issue cannot be scheduled because it is listed as blocking itself.\n\n")
+		sb.WriteString("Remove the issue from its own \"Blocked by\" relationships (or dependency labels, body references, or dependency file) to schedule it.\n")
 	case "onhold_dependency":
 		sb.WriteString("This issue cannot be scheduled because it depends on issues that are on hold.\n\n")
 		sb.WriteString("**On-hold dependencies:**\n")
@@ -57,20 +87,58 @@ func FormatSchedulingComment(si github.SchedulingIssue) string {
 		for _, detail := range si.Details {
 			sb.WriteString(fmt.Sprintf("%s\n", detail))
 		}
+	case "no_assignee":
+		sb.WriteString("This issue is not scheduled because it has no assignee.\n\n")
+		sb.WriteString("Assign the issue to include it in the schedule.\n")
+	case "unassigned_dependency":
+		sb.WriteString("This issue cannot be scheduled because it depends on issues that have no assignee.\n\n")
+		sb.WriteString("**Unassigned dependencies:**\n")
+		for _, dep := range si.Details {
+			sb.WriteString(fmt.Sprintf("- %s\n", dep))
+		}
 	case "at_risk":
-		sb.WriteString("**Warning:** This issue is at risk of missing its due date.\n\n")
+		sb.WriteString("**Warning:** This issue is at risk of missing its due date: its Expected Completion is after its Due Date.\n\n")
 		for _, detail := range si.Details {
 			sb.WriteString(fmt.Sprintf("%s\n", detail))
 		}
+	case "package_overcommit":
+		sb.WriteString("**Warning:** This issue's milestone has more work than fits before its due date.\n\n")
+		for _, detail := range si.Details {
+			sb.WriteString(fmt.Sprintf("%s\n", detail))
+		}
+	case "overcommitted_milestone":
+		sb.WriteString("**Warning:** This issue's milestone has more estimated work than its capacity, and this issue is past the limit.\n\n")
+		for _, detail := range si.Details {
+			sb.WriteString(fmt.Sprintf("%s\n", detail))
+		}
+	case "unknown_assignee":
+		sb.WriteString("**Warning:** This issue's assignee has no configured working hours, so default availability was assumed.\n\n")
+		sb.WriteString("**Assignee:**\n")
+		for _, login := range si.Details {
+			sb.WriteString(fmt.Sprintf("- `%s`\n", login))
+		}
 	}
 
 	sb.WriteString("\n---\n*This comment is automatically managed by p2-github-scheduler*")
 	return sb.String()
 }
 
+// CommentClient is the subset of *github.Client used to manage scheduling comments
+type CommentClient interface {
+	GetIssueComments(issueNum int) ([]map[string]interface{}, error)
+	CreateIssueComment(issueNum int, body string) error
+	UpdateIssueComment(commentID int64, body string) error
+	DeleteIssueComment(commentID int64) error
+}
+
 // FindSchedulingComment finds the comment ID of an existing scheduling comment on an issue
-func FindSchedulingComment(client *github.Client, issueNum int) (int64, error) {
-	comments, err := client.GetIssueComments(issueNum)
+func FindSchedulingComment(client CommentClient, issueNum int) (int64, error) {
+	var comments []map[string]interface{}
+	err := withAbuseRetry(func() error {
+		var err error
+		comments, err = client.GetIssueComments(issueNum)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -92,8 +160,63 @@ func FindSchedulingComment(client *github.Client, issueNum int) (int64, error) {
 	return 0, nil // No existing comment found
 }
 
+// CleanupDuplicateSchedulingComments deletes all but the newest scheduling
+// comment on an issue, returning the number deleted. Earlier versions could
+// post several.
+func CleanupDuplicateSchedulingComments(client CommentClient, issueNum int) (int, error) {
+	var comments []map[string]interface{}
+	err := withAbuseRetry(func() error {
+		var err error
+		comments, err = client.GetIssueComments(issueNum)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	type schedulingComment struct {
+		id      int64
+		created string
+	}
+	var found []schedulingComment
+	for _, comment := range comments {
+		body, ok := comment["body"].(string)
+		if !ok || !strings.HasPrefix(body, SchedulingCommentMarker) {
+			continue
+		}
+		id, ok := comment["id"].(float64)
+		if !ok {
+			continue
+		}
+		created, _ := comment["created_at"].(string)
+		found = append(found, schedulingComment{id: int64(id), created: created})
+	}
+	if len(found) < 2 {
+		return 0, nil
+	}
+
+	// Keep the latest created_at; without timestamps, the last listed (newest) wins
+	newest := len(found) - 1
+	for i, c := range found {
+		if c.created > found[newest].created {
+			newest = i
+		}
+	}
+	deleted := 0
+	for i, c := range found {
+		if i == newest {
+			continue
+		}
+		if err := withAbuseRetry(func() error { return client.DeleteIssueComment(c.id) }); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 // PostOrUpdateSchedulingComment posts a new comment or updates an existing one
-func PostOrUpdateSchedulingComment(client *github.Client, si github.SchedulingIssue) error {
+func PostOrUpdateSchedulingComment(client CommentClient, si github.SchedulingIssue) error {
 	existingID, err := FindSchedulingComment(client, si.IssueNum)
 	if err != nil {
 		return fmt.Errorf("failed to check for existing comment: %w", err)
@@ -103,15 +226,15 @@ func PostOrUpdateSchedulingComment(client *github.Client, si github.SchedulingIs
 
 	if existingID > 0 {
 		// Update existing comment
-		return client.UpdateIssueComment(existingID, body)
+		return withAbuseRetry(func() error { return client.UpdateIssueComment(existingID, body) })
 	}
 
 	// Create new comment
-	return client.CreateIssueComment(si.IssueNum, body)
+	return withAbuseRetry(func() error { return client.CreateIssueComment(si.IssueNum, body) })
 }
 
 // DeleteSchedulingComment removes an existing scheduling comment if present
-func DeleteSchedulingComment(client *github.Client, issueNum int) error {
+func DeleteSchedulingComment(client CommentClient, issueNum int) error {
 	existingID, err := FindSchedulingComment(client, issueNum)
 	if err != nil {
 		return fmt.Errorf("failed to check for existing comment: %w", err)
@@ -121,5 +244,11 @@ func DeleteSchedulingComment(client *github.Client, issueNum int) error {
 		return nil // No comment to delete
 	}
 
-	return client.DeleteIssueComment(existingID)
+	return DeleteComment(client, existingID)
+}
+
+// DeleteComment deletes the comment with commentID, such as one found with
+// FindSchedulingComment
+func DeleteComment(client CommentClient, commentID int64) error {
+	return withAbuseRetry(func() error { return client.DeleteIssueComment(commentID) })
 }
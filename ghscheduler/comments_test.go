@@ -102,6 +102,53 @@ func TestFormatSchedulingComment_InvalidEstimate(t *testing.T) {
 	}
 }
 
+func TestFormatSchedulingComment_NoAssignee(t *testing.T) {
+	si := p2.SchedulingIssue{
+		Reason: "no_assignee",
+	}
+
+	comment := FormatSchedulingComment(si)
+
+	if !strings.Contains(comment, SchedulingCommentMarker) {
+		t.Error("comment should contain the marker")
+	}
+	if !strings.Contains(comment, "no assignee") {
+		t.Error("comment should mention no assignee")
+	}
+}
+
+func TestFormatSchedulingComment_SelfDependency(t *testing.T) {
+	si := p2.SchedulingIssue{
+		Reason:  "self_dependency",
+		Details: []string{"owner/repo#1"},
+	}
+
+	comment := FormatSchedulingComment(si)
+
+	if !strings.Contains(comment, "blocking itself") {
+		t.Error("comment should explain that the issue references itself")
+	}
+	if !strings.Contains(comment, blockingHeading) {
+		t.Error("self-dependency should be reported as blocking")
+	}
+}
+
+func TestFormatSchedulingComment_OvercommittedMilestone(t *testing.T) {
+	si := p2.SchedulingIssue{
+		Reason:  "overcommitted_milestone",
+		Details: []string{"Milestone: v1.0 (capacity 10 person-days)", "Scheduled: 12 person-days (2 over)"},
+	}
+
+	comment := FormatSchedulingComment(si)
+
+	if !strings.Contains(comment, "Scheduled: 12 person-days (2 over)") {
+		t.Error("comment should include the milestone's overage")
+	}
+	if !strings.Contains(comment, warningHeading) {
+		t.Error("milestone over capacity should be reported as a warning")
+	}
+}
+
 func TestFormatSchedulingComment_AtRisk(t *testing.T) {
 	si := p2.SchedulingIssue{
 		Reason:  "at_risk",
@@ -119,6 +166,9 @@ func TestFormatSchedulingComment_AtRisk(t *testing.T) {
 	if !strings.Contains(comment, "due date") {
 		t.Error("comment should mention due date")
 	}
+	if !strings.Contains(comment, "Expected Completion is after its Due Date") {
+		t.Error("comment should explain why the issue is at risk")
+	}
 	if !strings.Contains(comment, "Due Date: 2025-03-01") {
 		t.Error("comment should contain the due date")
 	}
@@ -126,3 +176,68 @@ func TestFormatSchedulingComment_AtRisk(t *testing.T) {
 		t.Error("comment should contain the expected completion date")
 	}
 }
+
+func TestFormatSchedulingComment_HeadingBySeverity(t *testing.T) {
+	atRisk := FormatSchedulingComment(p2.SchedulingIssue{
+		Reason:  "at_risk",
+		Details: []string{"Due Date: 2025-03-01", "Expected Completion: 2025-03-15"},
+	})
+	if !strings.Contains(atRisk, "⚠️ At Risk") {
+		t.Error("at_risk comment should use the warning heading")
+	}
+	if strings.Contains(atRisk, "Cannot Be Scheduled") {
+		t.Error("at_risk comment should not use the blocking heading")
+	}
+
+	cycle := FormatSchedulingComment(p2.SchedulingIssue{
+		Reason:  "cycle",
+		Details: []string{"owner/repo#1", "owner/repo#2", "owner/repo#1"},
+	})
+	if !strings.Contains(cycle, "🚫 Cannot Be Scheduled") {
+		t.Error("cycle comment should use the blocking heading")
+	}
+	if !strings.HasPrefix(cycle, SchedulingCommentMarker) {
+		t.Error("comment should start with the marker")
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	if got := Severity(p2.SchedulingIssue{Reason: "at_risk"}); got != SeverityWarning {
+		t.Errorf("expected at_risk to be %q, got %q", SeverityWarning, got)
+	}
+	for _, reason := range []string{"cycle", "missing_dependency", "missing_estimate", "invalid_estimate"} {
+		if got := Severity(p2.SchedulingIssue{Reason: reason}); got != SeverityBlocking {
+			t.Errorf("expected %s to be %q, got %q", reason, SeverityBlocking, got)
+		}
+	}
+}
+
+func TestCleanupDuplicateSchedulingComments_KeepsNewest(t *testing.T) {
+	client := &fakeCommentClient{comments: []map[string]interface{}{
+		{"id": float64(1), "body": SchedulingCommentMarker + "\nold", "created_at": "2025-01-01T00:00:00Z"},
+		{"id": float64(2), "body": "A human comment", "created_at": "2025-01-02T00:00:00Z"},
+		{"id": float64(3), "body": SchedulingCommentMarker + "\nnewest", "created_at": "2025-01-05T00:00:00Z"},
+		{"id": float64(4), "body": SchedulingCommentMarker + "\nolder", "created_at": "2025-01-03T00:00:00Z"},
+	}}
+
+	deleted, err := CleanupDuplicateSchedulingComments(client, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 2 || len(client.deleted) != 2 || client.deleted[0] != 1 || client.deleted[1] != 4 {
+		t.Errorf("expected comments 1 and 4 to be deleted, got %v", client.deleted)
+	}
+}
+
+func TestCleanupDuplicateSchedulingComments_SingleCommentUntouched(t *testing.T) {
+	client := &fakeCommentClient{comments: []map[string]interface{}{
+		{"id": float64(1), "body": SchedulingCommentMarker + "\nonly"},
+	}}
+
+	if deleted, err := CleanupDuplicateSchedulingComments(client, 1); err != nil || deleted != 0 {
+		t.Errorf("expected nothing deleted, got %d (err=%v)", deleted, err)
+	}
+	if len(client.deleted) != 0 {
+		t.Errorf("expected no deletes, got %v", client.deleted)
+	}
+}
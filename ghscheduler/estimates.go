@@ -0,0 +1,66 @@
+package ghscheduler
+
+import (
+	"fmt"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+)
+
+// NumberFieldWriter sets number fields on project items
+type NumberFieldWriter interface {
+	UpdateNumberField(projectID, itemID, fieldID string, value float64) error
+}
+
+// SetEstimates writes the Low and High Estimate fields (as named by names) for each row whose
+// issue is in issues. It returns the number of issues written and the refs
+// of rows whose issues aren't in the project.
+func SetEstimates(writer NumberFieldWriter, rows []p2.EstimateRow, issues map[string]p2.IssueWithProject, names p2.FieldNames) (int, []string, error) {
+	names = names.WithDefaults()
+	written := 0
+	var unknown []string
+	for _, row := range rows {
+		iwp, ok := issues[fmt.Sprintf("github.com/%s/%s/issues/%d", row.Owner, row.Repo, row.IssueNum)]
+		if !ok || iwp.Project == nil {
+			unknown = append(unknown, row.Ref())
+			continue
+		}
+
+		for _, field := range []struct {
+			name  string
+			value float64
+		}{
+			{names.LowEstimate, row.Low},
+			{names.HighEstimate, row.High},
+		} {
+			fieldID, ok := iwp.Project.FieldIDs[field.name]
+			if !ok {
+				return written, unknown, fmt.Errorf("project has no %q field", field.name)
+			}
+			if err := writer.UpdateNumberField(iwp.Project.ProjectID, iwp.Project.ItemID, fieldID, field.value); err != nil {
+				return written, unknown, fmt.Errorf("failed to set %s for %s: %w", field.name, row.Ref(), err)
+			}
+		}
+		written++
+	}
+	return written, unknown, nil
+}
+
+// GraphQLFieldWriter writes project fields through the GitHub GraphQL API
+type GraphQLFieldWriter struct {
+	Token string
+}
+
+// UpdateNumberField sets a number field on a project item
+func (g GraphQLFieldWriter) UpdateNumberField(projectID, itemID, fieldID string, value float64) error {
+	query := `mutation($project: ID!, $item: ID!, $field: ID!, $value: Float!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {number: $value}}) {
+    projectV2Item { id }
+  }
+}`
+	return graphQL(g.Token, query, map[string]interface{}{
+		"project": projectID,
+		"item":    itemID,
+		"field":   fieldID,
+		"value":   value,
+	}, nil)
+}
@@ -0,0 +1,70 @@
+package ghscheduler
+
+import (
+	"testing"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+	"github.com/octoberswimmer/p2/github"
+)
+
+type fieldWrite struct {
+	itemID  string
+	fieldID string
+	value   float64
+}
+
+type fakeFieldWriter struct {
+	writes []fieldWrite
+}
+
+func (f *fakeFieldWriter) UpdateNumberField(projectID, itemID, fieldID string, value float64) error {
+	f.writes = append(f.writes, fieldWrite{itemID: itemID, fieldID: fieldID, value: value})
+	return nil
+}
+
+func TestSetEstimates_WritesKnownRowsAndReportsUnknown(t *testing.T) {
+	issues := map[string]p2.IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			Project: &github.ProjectItemInfo{
+				ProjectID: "proj-1",
+				ItemID:    "item-1",
+				FieldIDs: map[string]string{
+					"Low Estimate":  "low-field",
+					"High Estimate": "high-field",
+				},
+			},
+		},
+	}
+	rows := []p2.EstimateRow{
+		{Owner: "owner", Repo: "repo", IssueNum: 1, Low: 2, High: 5},
+		{Owner: "owner", Repo: "repo", IssueNum: 99, Low: 1, High: 2},
+	}
+
+	writer := &fakeFieldWriter{}
+	written, unknown, err := SetEstimates(writer, rows, issues, p2.DefaultFieldNames)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if written != 1 {
+		t.Errorf("expected 1 issue written, got %d", written)
+	}
+	want := []fieldWrite{
+		{itemID: "item-1", fieldID: "low-field", value: 2},
+		{itemID: "item-1", fieldID: "high-field", value: 5},
+	}
+	if len(writer.writes) != len(want) {
+		t.Fatalf("expected %d field writes, got %+v", len(want), writer.writes)
+	}
+	for i, w := range want {
+		if writer.writes[i] != w {
+			t.Errorf("write %d: expected %+v, got %+v", i, w, writer.writes[i])
+		}
+	}
+	if len(unknown) != 1 || unknown[0] != "owner/repo#99" {
+		t.Errorf("expected owner/repo#99 to be reported as unknown, got %v", unknown)
+	}
+}
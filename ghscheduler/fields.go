@@ -0,0 +1,108 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// fieldValueBatchSize is the maximum number of project items queried per request
+const fieldValueBatchSize = 100
+
+// fieldValue is a project item's value for a number, date, or single-select field
+type fieldValue struct {
+	Number *float64 `json:"number"`
+	Date   string   `json:"date"`
+	Name   string   `json:"name"`
+}
+
+// FetchNumberFieldValues returns the value of the number field named
+// fieldName for each project item ID. Items without a value are omitted.
+func FetchNumberFieldValues(token string, itemIDs []string, fieldName string) (map[string]float64, error) {
+	raw, err := fetchFieldValues(token, itemIDs, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]float64)
+	for id, v := range raw {
+		if v.Number != nil {
+			values[id] = *v.Number
+		}
+	}
+	return values, nil
+}
+
+// FetchDateFieldValues returns the value of the date field named fieldName
+// for each project item ID. Items without a value are omitted.
+func FetchDateFieldValues(token string, itemIDs []string, fieldName string) (map[string]time.Time, error) {
+	raw, err := fetchFieldValues(token, itemIDs, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]time.Time)
+	for id, v := range raw {
+		if v.Date == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", v.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", fieldName, v.Date, err)
+		}
+		values[id] = date
+	}
+	return values, nil
+}
+
+// FetchSingleSelectFieldValues returns the selected option of the
+// single-select field named fieldName for each project item ID. Items without
+// a value are omitted.
+func FetchSingleSelectFieldValues(token string, itemIDs []string, fieldName string) (map[string]string, error) {
+	raw, err := fetchFieldValues(token, itemIDs, fieldName)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for id, v := range raw {
+		if v.Name != "" {
+			values[id] = v.Name
+		}
+	}
+	return values, nil
+}
+
+// fetchFieldValues returns the values of the field named fieldName for each
+// project item ID, querying in batches
+func fetchFieldValues(token string, itemIDs []string, fieldName string) (map[string]fieldValue, error) {
+	query := `query($ids: [ID!]!, $field: String!) {
+  nodes(ids: $ids) {
+    ... on ProjectV2Item {
+      id
+      fieldValueByName(name: $field) {
+        ... on ProjectV2ItemFieldNumberValue { number }
+        ... on ProjectV2ItemFieldDateValue { date }
+        ... on ProjectV2ItemFieldSingleSelectValue { name }
+      }
+    }
+  }
+}`
+
+	values := make(map[string]fieldValue)
+	for start := 0; start < len(itemIDs); start += fieldValueBatchSize {
+		end := min(start+fieldValueBatchSize, len(itemIDs))
+		var result struct {
+			Nodes []struct {
+				ID               string      `json:"id"`
+				FieldValueByName *fieldValue `json:"fieldValueByName"`
+			} `json:"nodes"`
+		}
+		vars := map[string]interface{}{"ids": itemIDs[start:end], "field": fieldName}
+		if err := graphQL(token, query, vars, &result); err != nil {
+			return nil, err
+		}
+		for _, node := range result.Nodes {
+			if node.FieldValueByName != nil {
+				values[node.ID] = *node.FieldValueByName
+			}
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,87 @@
+package ghscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchNumberFieldValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				IDs   []string `json:"ids"`
+				Field string   `json:"field"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Variables.Field != "Rank" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"nodes":[
+			{"id":"item-1","fieldValueByName":{"number":2}},
+			{"id":"item-2","fieldValueByName":null}
+		]}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	values, err := FetchNumberFieldValues("test-token", []string{"item-1", "item-2"}, "Rank")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 1 || values["item-1"] != 2 {
+		t.Errorf("expected only item-1=2, got %v", values)
+	}
+}
+
+func TestFetchDateFieldValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"nodes":[
+			{"id":"item-1","fieldValueByName":{"date":"2025-03-07"}},
+			{"id":"item-2","fieldValueByName":{}}
+		]}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	values, err := FetchDateFieldValues("test-token", []string{"item-1", "item-2"}, "Forecast Start")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	if len(values) != 1 || !values["item-1"].Equal(want) {
+		t.Errorf("expected only item-1=2025-03-07, got %v", values)
+	}
+}
+
+func TestFetchSingleSelectFieldValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"nodes":[
+			{"id":"item-1","fieldValueByName":{"name":"Backend"}},
+			{"id":"item-2","fieldValueByName":null}
+		]}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	values, err := FetchSingleSelectFieldValues("test-token", []string{"item-1", "item-2"}, "Team")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 1 || values["item-1"] != "Backend" {
+		t.Errorf("expected only item-1=Backend, got %v", values)
+	}
+}
@@ -0,0 +1,61 @@
+package ghscheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// CreateGist creates a secret gist containing files (name -> content) and
+// returns its URL
+func CreateGist(token, description string, files map[string]string) (string, error) {
+	type gistFile struct {
+		Content string `json:"content"`
+	}
+	payload := struct {
+		Description string              `json:"description"`
+		Public      bool                `json:"public"`
+		Files       map[string]gistFile `json:"files"`
+	}{
+		Description: description,
+		Files:       make(map[string]gistFile, len(files)),
+	}
+	for name, content := range files {
+		payload.Files[name] = gistFile{Content: content}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", apiBaseURL+"/gists", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("gists API returned %d (the token needs the gist scope)", resp.StatusCode)
+	}
+
+	var gist struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(body, &gist); err != nil {
+		return "", fmt.Errorf("failed to decode gist response: %w", err)
+	}
+	return gist.HTMLURL, nil
+}
@@ -0,0 +1,70 @@
+package ghscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateGist(t *testing.T) {
+	var got struct {
+		Description string `json:"description"`
+		Public      bool   `json:"public"`
+		Files       map[string]struct {
+			Content string `json:"content"`
+		} `json:"files"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/gists" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"html_url":"https://gist.github.com/abc123"}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	report := "| Issue | Title |\n|---|---|\n| 1 | Task |\n"
+	url, err := CreateGist("test-token", "Schedule for myorg/myrepo", map[string]string{"schedule.md": report})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "https://gist.github.com/abc123" {
+		t.Errorf("expected gist URL, got %q", url)
+	}
+	if got.Public {
+		t.Error("expected a secret gist")
+	}
+	if got.Files["schedule.md"].Content != report {
+		t.Errorf("expected gist to carry the report content, got %q", got.Files["schedule.md"].Content)
+	}
+}
+
+func TestCreateGist_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	if _, err := CreateGist("test-token", "", map[string]string{"schedule.md": "x"}); err == nil {
+		t.Error("expected error for non-201 status")
+	}
+}
@@ -0,0 +1,101 @@
+package ghscheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiError is a non-200 response from the GitHub GraphQL API
+type apiError struct {
+	status     int
+	message    string
+	retryAfter time.Duration
+}
+
+func (e *apiError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("GraphQL API returned %d", e.status)
+	}
+	return fmt.Sprintf("GraphQL API returned %d: %s", e.status, e.message)
+}
+
+// RetryAfter returns the delay advised by the response's Retry-After header
+func (e *apiError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// newAPIError builds an apiError from a response, preferring the message of
+// a JSON error body such as GitHub's secondary rate limit response
+func newAPIError(resp *http.Response, body []byte) *apiError {
+	e := &apiError{status: resp.StatusCode}
+	var decoded struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &decoded) == nil && decoded.Message != "" {
+		e.message = decoded.Message
+	} else {
+		e.message = strings.TrimSpace(string(body))
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		e.retryAfter = time.Duration(secs) * time.Second
+	}
+	return e
+}
+
+// graphQL runs query with variables against the GitHub GraphQL API and
+// decodes the response's data into out (which may be nil)
+func graphQL(token, query string, variables map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", apiBaseURL+"/graphql", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp, body)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("GraphQL error: %s", result.Errors[0].Message)
+	}
+	if out == nil || len(result.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package ghscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// RepoPermissions is the token's effective access to a repository
+type RepoPermissions struct {
+	Admin    bool `json:"admin"`
+	Maintain bool `json:"maintain"`
+	Push     bool `json:"push"`
+	Triage   bool `json:"triage"`
+	Pull     bool `json:"pull"`
+}
+
+// CanWriteIssues reports whether the permissions allow commenting on issues
+func (p RepoPermissions) CanWriteIssues() bool {
+	return p.Admin || p.Maintain || p.Push || p.Triage
+}
+
+// FetchRepoPermissions returns the token's permissions on owner/repo.
+// It returns nil, nil if the API does not report permissions for the token
+// (as with some GitHub App installation tokens).
+func FetchRepoPermissions(token, owner, repo string) (*RepoPermissions, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/repos/%s/%s", apiBaseURL, owner, repo), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("repository API returned %d for %s/%s", resp.StatusCode, owner, repo)
+	}
+
+	var repository struct {
+		Permissions *RepoPermissions `json:"permissions"`
+	}
+	if err := json.Unmarshal(body, &repository); err != nil {
+		return nil, fmt.Errorf("failed to decode repository: %w", err)
+	}
+	return repository.Permissions, nil
+}
+
+// FeatureComments is the scheduling comment feature
+const FeatureComments = "comments"
+
+// DisabledFeature records a feature turned off for a repository
+type DisabledFeature struct {
+	Feature string
+	Owner   string
+	Repo    string
+	Reason  string
+}
+
+// Features records which optional write features the token can perform
+type Features struct {
+	// Disabled lists the features turned off, one notice per feature and repo
+	Disabled []DisabledFeature
+
+	commentsDisabled map[string]bool
+}
+
+// CommentsEnabled reports whether scheduling comments can be posted on owner/repo
+func (f *Features) CommentsEnabled(owner, repo string) bool {
+	return f == nil || !f.commentsDisabled[owner+"/"+repo]
+}
+
+// DetectFeatures queries the token's permissions once per repository
+// ("owner/repo") and disables features it cannot perform. Repositories whose
+// permissions cannot be determined keep all features enabled.
+func DetectFeatures(token string, repos []string) *Features {
+	f := &Features{commentsDisabled: make(map[string]bool)}
+	sorted := append([]string(nil), repos...)
+	sort.Strings(sorted)
+	for _, r := range sorted {
+		owner, repo, ok := strings.Cut(r, "/")
+		if !ok || f.commentsDisabled[r] {
+			continue
+		}
+		perms, err := FetchRepoPermissions(token, owner, repo)
+		if err != nil || perms == nil {
+			continue
+		}
+		if !perms.CanWriteIssues() {
+			f.commentsDisabled[r] = true
+			f.Disabled = append(f.Disabled, DisabledFeature{
+				Feature: FeatureComments,
+				Owner:   owner,
+				Repo:    repo,
+				Reason:  "token lacks issues write permission",
+			})
+		}
+	}
+	return f
+}
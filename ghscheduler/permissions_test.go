@@ -0,0 +1,47 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectFeatures_ReadOnlyTokenDisablesComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/myorg/readonly":
+			fmt.Fprint(w, `{"permissions":{"admin":false,"maintain":false,"push":false,"triage":false,"pull":true}}`)
+		case "/repos/myorg/writable":
+			fmt.Fprint(w, `{"permissions":{"admin":false,"maintain":false,"push":true,"triage":true,"pull":true}}`)
+		case "/repos/myorg/unknown":
+			fmt.Fprint(w, `{"name":"unknown"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	features := DetectFeatures("test-token", []string{"myorg/readonly", "myorg/writable", "myorg/unknown"})
+
+	if features.CommentsEnabled("myorg", "readonly") {
+		t.Error("expected comments to be disabled for a read-only token")
+	}
+	if !features.CommentsEnabled("myorg", "writable") {
+		t.Error("expected comments to be enabled with write access")
+	}
+	if !features.CommentsEnabled("myorg", "unknown") {
+		t.Error("expected comments to stay enabled when permissions are not reported")
+	}
+
+	if len(features.Disabled) != 1 {
+		t.Fatalf("expected 1 notice, got %d: %+v", len(features.Disabled), features.Disabled)
+	}
+	if d := features.Disabled[0]; d.Feature != FeatureComments || d.Owner != "myorg" || d.Repo != "readonly" {
+		t.Errorf("unexpected notice: %+v", d)
+	}
+}
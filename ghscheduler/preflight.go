@@ -0,0 +1,90 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// FetchTokenScopes returns the OAuth scopes granted to token. classic is false
+// when GitHub reports no scopes header, as for fine-grained personal access
+// tokens and GitHub App installation tokens, whose permissions must be probed.
+func FetchTokenScopes(token string) (scopes []string, classic bool, err error) {
+	req, err := http.NewRequest("GET", apiBaseURL+"/rate_limit", nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("rate limit API returned %d", resp.StatusCode)
+	}
+
+	header, ok := resp.Header["X-Oauth-Scopes"]
+	if !ok {
+		return nil, false, nil
+	}
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true, nil
+}
+
+// CanUpdateProject reports whether token may update the Projects v2 project with node ID projectID
+func CanUpdateProject(token, projectID string) (bool, error) {
+	query := `query($id: ID!) {
+  node(id: $id) {
+    ... on ProjectV2 { viewerCanUpdate }
+  }
+}`
+	var data struct {
+		Node *struct {
+			ViewerCanUpdate bool `json:"viewerCanUpdate"`
+		} `json:"node"`
+	}
+	if err := graphQL(token, query, map[string]interface{}{"id": projectID}, &data); err != nil {
+		return false, err
+	}
+	return data.Node != nil && data.Node.ViewerCanUpdate, nil
+}
+
+// CheckProjectWriteAccess returns an actionable error if token cannot write
+// fields on the given projects. Classic tokens must have the project scope;
+// other tokens are checked against each project's viewerCanUpdate. A token
+// whose access can't be determined is allowed, so mutations report the failure.
+func CheckProjectWriteAccess(token string, projectIDs []string) error {
+	scopes, classic, err := FetchTokenScopes(token)
+	if err != nil {
+		return nil
+	}
+	if classic {
+		for _, scope := range scopes {
+			if scope == "project" {
+				return nil
+			}
+		}
+		return fmt.Errorf("token is missing the project scope needed to write Projects v2 fields (granted: %s); add it with `gh auth refresh -s project` or create a token with the project scope", strings.Join(scopes, ", "))
+	}
+
+	sorted := append([]string(nil), projectIDs...)
+	sort.Strings(sorted)
+	for _, id := range sorted {
+		ok, err := CanUpdateProject(token, id)
+		if err != nil {
+			continue
+		}
+		if !ok {
+			return fmt.Errorf("token cannot update project %s; grant it Projects read and write access (for GitHub Actions, a GITHUB_TOKEN cannot write Projects v2 fields, so use a GitHub App or personal access token)", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckProjectWriteAccess_ClassicTokenNeedsProjectScope(t *testing.T) {
+	scopes := "repo, read:project"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", scopes)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	err := CheckProjectWriteAccess("test-token", []string{"PVT_1"})
+	if err == nil || !strings.Contains(err.Error(), "project scope") {
+		t.Errorf("expected missing project scope error, got %v", err)
+	}
+
+	scopes = "repo, project"
+	if err := CheckProjectWriteAccess("test-token", []string{"PVT_1"}); err != nil {
+		t.Errorf("expected project scope to pass, got %v", err)
+	}
+}
+
+func TestCheckProjectWriteAccess_ProbesFineGrainedToken(t *testing.T) {
+	canUpdate := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/graphql" {
+			fmt.Fprintf(w, `{"data":{"node":{"viewerCanUpdate":%v}}}`, canUpdate)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	err := CheckProjectWriteAccess("test-token", []string{"PVT_1"})
+	if err == nil || !strings.Contains(err.Error(), "cannot update project PVT_1") {
+		t.Errorf("expected project write error, got %v", err)
+	}
+
+	canUpdate = true
+	if err := CheckProjectWriteAccess("test-token", []string{"PVT_1"}); err != nil {
+		t.Errorf("expected writable project to pass, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+)
+
+// ProjectField is a field defined on a project. Options is set for single
+// select fields.
+type ProjectField struct {
+	Name     string
+	DataType string
+	Options  []string
+}
+
+// FetchProjectFields returns the fields defined on project, without fetching its items
+func FetchProjectFields(token string, project ProjectRef) ([]ProjectField, error) {
+	ownerType := "user"
+	if project.IsOrg {
+		ownerType = "organization"
+	}
+	query := fmt.Sprintf(`query($owner: String!, $number: Int!) {
+  owner: %s(login: $owner) {
+    projectV2(number: $number) {
+      fields(first: 100) {
+        nodes {
+          ... on ProjectV2FieldCommon { name dataType }
+          ... on ProjectV2SingleSelectField { options { name } }
+        }
+      }
+    }
+  }
+}`, ownerType)
+
+	var result struct {
+		Owner *struct {
+			ProjectV2 *struct {
+				Fields struct {
+					Nodes []struct {
+						Name     string `json:"name"`
+						DataType string `json:"dataType"`
+						Options  []struct {
+							Name string `json:"name"`
+						} `json:"options"`
+					} `json:"nodes"`
+				} `json:"fields"`
+			} `json:"projectV2"`
+		} `json:"owner"`
+	}
+	variables := map[string]interface{}{"owner": project.Owner, "number": project.Number}
+	if err := graphQL(token, query, variables, &result); err != nil {
+		return nil, err
+	}
+	if result.Owner == nil || result.Owner.ProjectV2 == nil {
+		return nil, fmt.Errorf("project %s/%d not found or not accessible", project.Owner, project.Number)
+	}
+
+	var fields []ProjectField
+	for _, node := range result.Owner.ProjectV2.Fields.Nodes {
+		field := ProjectField{Name: node.Name, DataType: node.DataType}
+		for _, opt := range node.Options {
+			field.Options = append(field.Options, opt.Name)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// FieldProblem is a project field that is missing or unusable. Without a
+// Required field the project can't be scheduled; the others limit features.
+type FieldProblem struct {
+	Field    string
+	Required bool
+	Problem  string
+}
+
+// CheckProjectFields reports the fields p2 reads and writes that are missing
+// from fields or have the wrong type, using names for the renameable fields.
+// A Scheduling Status field missing one of onHoldStatuses (nil means
+// p2.DefaultOnHoldStatuses) as an option is also reported.
+func CheckProjectFields(fields []ProjectField, names p2.FieldNames, onHoldStatuses []string) []FieldProblem {
+	if onHoldStatuses == nil {
+		onHoldStatuses = p2.DefaultOnHoldStatuses
+	}
+	byName := make(map[string]ProjectField, len(fields))
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+
+	expected := []struct {
+		name     string
+		dataType string
+		required bool
+	}{
+		{names.LowEstimate, "NUMBER", true},
+		{names.HighEstimate, "NUMBER", true},
+		{names.ExpectedStart, "DATE", true},
+		{names.ExpectedCompletion, "DATE", true},
+		{names.Completion98, "DATE", true},
+		{"Due Date", "DATE", false},
+		{SchedulingStatusField, "SINGLE_SELECT", false},
+	}
+
+	var problems []FieldProblem
+	for _, e := range expected {
+		f, ok := byName[e.name]
+		switch {
+		case !ok:
+			problems = append(problems, FieldProblem{Field: e.name, Required: e.required, Problem: "missing"})
+		case f.DataType != e.dataType:
+			problems = append(problems, FieldProblem{
+				Field:    e.name,
+				Required: e.required,
+				Problem:  fmt.Sprintf("has type %s, expected %s", strings.ToLower(f.DataType), strings.ToLower(e.dataType)),
+			})
+		case e.name == SchedulingStatusField:
+			for _, status := range onHoldStatuses {
+				if !hasOption(f.Options, status) {
+					problems = append(problems, FieldProblem{Field: e.name, Problem: fmt.Sprintf("has no %q option", status)})
+				}
+			}
+		}
+	}
+	return problems
+}
+
+func hasOption(options []string, name string) bool {
+	for _, o := range options {
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
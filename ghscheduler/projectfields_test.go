@@ -0,0 +1,99 @@
+package ghscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+)
+
+func TestFetchProjectFields_OrgProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if !strings.Contains(req.Query, "organization(login: $owner)") || strings.Contains(req.Query, "items") {
+			t.Errorf("expected an organization fields-only query, got %s", req.Query)
+		}
+		if req.Variables["owner"] != "myorg" || req.Variables["number"] != float64(4) {
+			t.Errorf("unexpected variables %v", req.Variables)
+		}
+		fmt.Fprint(w, `{"data":{"owner":{"projectV2":{"fields":{"nodes":[
+			{"name":"Low Estimate","dataType":"NUMBER"},
+			{"name":"Scheduling Status","dataType":"SINGLE_SELECT","options":[{"name":"On Hold"}]}
+		]}}}}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	project, ok := ParseProjectURL("https://github.com/orgs/myorg/projects/4")
+	if !ok || !project.IsOrg {
+		t.Fatalf("expected org project URL to parse, got %+v", project)
+	}
+	fields, err := FetchProjectFields("test-token", project)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fields) != 2 || fields[1].Name != "Scheduling Status" || len(fields[1].Options) != 1 || fields[1].Options[0] != "On Hold" {
+		t.Errorf("unexpected fields %+v", fields)
+	}
+}
+
+func TestCheckProjectFields_ReportsMissingAndMistyped(t *testing.T) {
+	fields := []ProjectField{
+		{Name: "Est. Low", DataType: "NUMBER"},
+		{Name: "High Estimate", DataType: "TEXT"},
+		{Name: "Expected Start", DataType: "DATE"},
+		{Name: "Expected Completion", DataType: "DATE"},
+		{Name: "98% Completion", DataType: "DATE"},
+		{Name: "Scheduling Status", DataType: "SINGLE_SELECT", Options: []string{"Blocked"}},
+	}
+	names := p2.DefaultFieldNames
+	names.LowEstimate = "Est. Low"
+
+	problems := CheckProjectFields(fields, names, nil)
+	want := []FieldProblem{
+		{Field: "High Estimate", Required: true, Problem: "has type text, expected number"},
+		{Field: "Due Date", Problem: "missing"},
+		{Field: "Scheduling Status", Problem: `has no "On Hold" option`},
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, problems)
+	}
+	for i := range want {
+		if problems[i] != want[i] {
+			t.Errorf("problem %d: expected %+v, got %+v", i, want[i], problems[i])
+		}
+	}
+}
+
+func TestCheckProjectFields_ConfiguredOnHoldStatuses(t *testing.T) {
+	fields := []ProjectField{
+		{Name: "Low Estimate", DataType: "NUMBER"},
+		{Name: "High Estimate", DataType: "NUMBER"},
+		{Name: "Expected Start", DataType: "DATE"},
+		{Name: "Expected Completion", DataType: "DATE"},
+		{Name: "98% Completion", DataType: "DATE"},
+		{Name: "Due Date", DataType: "DATE"},
+		{Name: "Scheduling Status", DataType: "SINGLE_SELECT", Options: []string{"Parked"}},
+	}
+
+	if problems := CheckProjectFields(fields, p2.DefaultFieldNames, []string{"Parked"}); len(problems) != 0 {
+		t.Errorf("expected no problems when the configured on-hold option exists, got %+v", problems)
+	}
+	problems := CheckProjectFields(fields, p2.DefaultFieldNames, []string{"Parked", "Paused"})
+	if len(problems) != 1 || problems[0].Problem != `has no "Paused" option` {
+		t.Errorf("expected the missing Paused option to be reported, got %+v", problems)
+	}
+}
@@ -0,0 +1,59 @@
+package ghscheduler
+
+import (
+	"github.com/octoberswimmer/p2/github"
+)
+
+// FetchClosingIssues returns the issues that pr will close when merged,
+// including those linked manually from the pull request's sidebar
+func FetchClosingIssues(token string, pr PullRequestRef) ([]github.IssueRef, error) {
+	query := `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      closingIssuesReferences(first: 50) {
+        nodes {
+          number
+          state
+          repository { name owner { login } }
+        }
+      }
+    }
+  }
+}`
+	var result struct {
+		Repository struct {
+			PullRequest *struct {
+				ClosingIssuesReferences struct {
+					Nodes []struct {
+						Number     int    `json:"number"`
+						State      string `json:"state"`
+						Repository struct {
+							Name  string `json:"name"`
+							Owner struct {
+								Login string `json:"login"`
+							} `json:"owner"`
+						} `json:"repository"`
+					} `json:"nodes"`
+				} `json:"closingIssuesReferences"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	}
+	vars := map[string]interface{}{"owner": pr.Owner, "repo": pr.Repo, "number": pr.Number}
+	if err := graphQL(token, query, vars, &result); err != nil {
+		return nil, err
+	}
+	if result.Repository.PullRequest == nil {
+		return nil, nil
+	}
+
+	var refs []github.IssueRef
+	for _, node := range result.Repository.PullRequest.ClosingIssuesReferences.Nodes {
+		refs = append(refs, github.IssueRef{
+			Owner:  node.Repository.Owner.Login,
+			Repo:   node.Repository.Name,
+			Number: node.Number,
+			State:  node.State,
+		})
+	}
+	return refs, nil
+}
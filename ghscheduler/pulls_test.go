@@ -0,0 +1,46 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParsePullRequestURL(t *testing.T) {
+	pr, ok := ParsePullRequestURL("https://github.com/owner/repo/pull/123")
+	if !ok || pr.Owner != "owner" || pr.Repo != "repo" || pr.Number != 123 {
+		t.Errorf("expected owner/repo#123, got %+v (ok=%v)", pr, ok)
+	}
+	if pr, ok := ParsePullRequestURL("https://github.com/owner/repo/pull/9/files"); !ok || pr.Number != 9 {
+		t.Errorf("expected files tab to parse as #9, got %+v (ok=%v)", pr, ok)
+	}
+	if _, ok := ParsePullRequestURL("https://github.com/owner/repo/issues/123"); ok {
+		t.Error("expected issue URL not to parse as a pull request")
+	}
+}
+
+func TestFetchClosingIssues_MultipleIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"repository":{"pullRequest":{"closingIssuesReferences":{"nodes":[
+			{"number":4,"state":"OPEN","repository":{"name":"repo","owner":{"login":"owner"}}},
+			{"number":7,"state":"OPEN","repository":{"name":"other","owner":{"login":"owner"}}}
+		]}}}}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	refs, err := FetchClosingIssues("test-token", PullRequestRef{Owner: "owner", Repo: "repo", Number: 12})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 closing issues, got %d", len(refs))
+	}
+	if refs[0].Repo != "repo" || refs[0].Number != 4 || refs[1].Repo != "other" || refs[1].Number != 7 {
+		t.Errorf("unexpected closing issues: %+v", refs)
+	}
+}
@@ -0,0 +1,49 @@
+package ghscheduler
+
+// FetchMatchingItemIDs returns the IDs of the items in the project with node
+// ID projectID that match query, using the same filter syntax as the project
+// view's filter bar (e.g. "is:open label:backend")
+func FetchMatchingItemIDs(token, projectID, query string) (map[string]bool, error) {
+	gql := `query($id: ID!, $q: String!, $after: String) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      items(first: 100, after: $after, query: $q) {
+        nodes { id }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+	matching := make(map[string]bool)
+	var after interface{}
+	for {
+		var result struct {
+			Node *struct {
+				Items struct {
+					Nodes []struct {
+						ID string `json:"id"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"items"`
+			} `json:"node"`
+		}
+		variables := map[string]interface{}{"id": projectID, "q": query, "after": after}
+		if err := graphQL(token, gql, variables, &result); err != nil {
+			return nil, err
+		}
+		if result.Node == nil {
+			return matching, nil
+		}
+		for _, item := range result.Node.Items.Nodes {
+			matching[item.ID] = true
+		}
+		if !result.Node.Items.PageInfo.HasNextPage {
+			return matching, nil
+		}
+		after = result.Node.Items.PageInfo.EndCursor
+	}
+}
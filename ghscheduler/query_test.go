@@ -0,0 +1,46 @@
+package ghscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchMatchingItemIDs_Paginates(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Variables["q"] != "is:open label:backend" {
+			t.Errorf("expected query to be forwarded, got %v", req.Variables["q"])
+		}
+		requests++
+		if req.Variables["after"] == nil {
+			fmt.Fprint(w, `{"data":{"node":{"items":{"nodes":[{"id":"item-1"}],"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}`)
+			return
+		}
+		if req.Variables["after"] != "c1" {
+			t.Errorf("expected second page after c1, got %v", req.Variables["after"])
+		}
+		fmt.Fprint(w, `{"data":{"node":{"items":{"nodes":[{"id":"item-2"}],"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	matching, err := FetchMatchingItemIDs("test-token", "project-1", "is:open label:backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 || len(matching) != 2 || !matching["item-1"] || !matching["item-2"] {
+		t.Errorf("expected item-1 and item-2 over 2 requests, got %v over %d", matching, requests)
+	}
+}
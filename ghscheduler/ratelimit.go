@@ -0,0 +1,167 @@
+package ghscheduler
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxAbuseRetries is how many times an operation is retried after GitHub's
+// secondary rate limit (abuse detection) rejects it
+const maxAbuseRetries = 3
+
+// defaultAbuseWait is how long to wait when GitHub doesn't advise a duration.
+// GitHub recommends waiting at least a minute before retrying.
+const defaultAbuseWait = time.Minute
+
+// sleep is time.Sleep, overridden in tests
+var sleep = time.Sleep
+
+// jitter returns a random duration in [0, d), overridden in tests
+var jitter = func(d time.Duration) time.Duration { return time.Duration(rand.Int63n(int64(d))) }
+
+// retryAfterError is implemented by errors that carry GitHub's advised retry delay
+type retryAfterError interface {
+	RetryAfter() time.Duration
+}
+
+// isAbuseError reports whether err is a secondary rate limit or abuse detection response
+func isAbuseError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse detection")
+}
+
+// abuseWait returns the delay advised by err. Without advice it backs off
+// exponentially from defaultAbuseWait, plus up to 10% jitter so concurrent
+// runs don't retry in lockstep.
+func abuseWait(err error, attempt int) time.Duration {
+	var ra retryAfterError
+	if errors.As(err, &ra) && ra.RetryAfter() > 0 {
+		return ra.RetryAfter()
+	}
+	wait := defaultAbuseWait << attempt
+	return wait + jitter(wait/10)
+}
+
+// withAbuseRetry runs op, sleeping the advised duration and retrying when
+// GitHub's abuse detection rejects it. Other errors are returned immediately.
+func withAbuseRetry(op func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = op()
+		if !isAbuseError(err) || attempt == maxAbuseRetries {
+			return err
+		}
+		wait := abuseWait(err, attempt)
+		logrus.Debugf("Hit GitHub secondary rate limit, waiting %s before retrying", wait)
+		sleep(wait)
+	}
+}
+
+// rateLimitReserve is the remaining primary rate limit budget below which
+// requests are paced out until the limit resets
+const rateLimitReserve = 100
+
+// now is time.Now, overridden in tests
+var now = time.Now
+
+// rateBudget is the last reported primary rate limit for one resource
+type rateBudget struct {
+	remaining int
+	reset     time.Time
+}
+
+// RateLimiter is an http.RoundTripper that tracks GitHub's primary rate limit
+// from the X-RateLimit-Remaining and X-RateLimit-Reset response headers,
+// separately for each X-RateLimit-Resource (core, graphql, ...). Once a
+// resource's budget drops below rateLimitReserve, requests are spread evenly
+// over the time left until it resets, and with no budget left they wait for
+// the reset, so a run slows down instead of failing halfway through its
+// updates. Responses without the headers, like other hosts', are ignored.
+type RateLimiter struct {
+	next http.RoundTripper
+
+	mu      sync.Mutex
+	budgets map[string]rateBudget
+}
+
+// NewRateLimiter returns a RateLimiter sending requests through next
+func NewRateLimiter(next http.RoundTripper) *RateLimiter {
+	return &RateLimiter{next: next, budgets: make(map[string]rateBudget)}
+}
+
+// RoundTrip waits for budget, sends the request, and records the budget
+// reported by the response
+func (l *RateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if wait := l.delay(rateLimitResource(req)); wait > 0 {
+		logrus.Debugf("GitHub rate limit nearly exhausted, waiting %s", wait.Round(time.Second))
+		sleep(wait)
+	}
+	resp, err := l.next.RoundTrip(req)
+	if err == nil {
+		l.observe(resp.Header)
+	}
+	return resp, err
+}
+
+// delay returns how long to wait before a request against resource
+func (l *RateLimiter) delay(resource string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.budgets[resource]
+	if !ok || b.remaining >= rateLimitReserve {
+		return 0
+	}
+	left := b.reset.Sub(now())
+	if left <= 0 {
+		delete(l.budgets, resource)
+		return 0
+	}
+	if b.remaining <= 0 {
+		return left
+	}
+	return left / time.Duration(b.remaining+1)
+}
+
+// observe records the budget reported in a response's headers
+func (l *RateLimiter) observe(h http.Header) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	resource := h.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+
+	l.mu.Lock()
+	l.budgets[resource] = rateBudget{remaining: remaining, reset: time.Unix(reset, 0)}
+	l.mu.Unlock()
+	if remaining%100 == 0 || remaining < rateLimitReserve {
+		logrus.Debugf("GitHub %s rate limit: %d remaining, resets at %s", resource, remaining, time.Unix(reset, 0).Format(time.Kitchen))
+	}
+}
+
+// rateLimitResource returns the rate limit resource a request counts against
+func rateLimitResource(req *http.Request) string {
+	if strings.HasSuffix(req.URL.Path, "/graphql") {
+		return "graphql"
+	}
+	if strings.HasPrefix(req.URL.Path, "/search/") {
+		return "search"
+	}
+	return "core"
+}
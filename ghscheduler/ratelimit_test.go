@@ -0,0 +1,275 @@
+package ghscheduler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/github"
+)
+
+// abuseError mimics GitHub's secondary rate limit response
+type abuseError struct {
+	wait time.Duration
+}
+
+func (e abuseError) Error() string {
+	return "403 Forbidden: You have exceeded a secondary rate limit"
+}
+
+func (e abuseError) RetryAfter() time.Duration {
+	return e.wait
+}
+
+// fakeCommentClient is an in-memory CommentClient
+type fakeCommentClient struct {
+	comments    []map[string]interface{}
+	createErrs  []error
+	createCalls int
+	nextID      float64
+	deleted     []int64
+}
+
+func (c *fakeCommentClient) GetIssueComments(issueNum int) ([]map[string]interface{}, error) {
+	return c.comments, nil
+}
+
+func (c *fakeCommentClient) CreateIssueComment(issueNum int, body string) error {
+	c.createCalls++
+	if len(c.createErrs) > 0 {
+		err := c.createErrs[0]
+		c.createErrs = c.createErrs[1:]
+		return err
+	}
+	c.nextID++
+	c.comments = append(c.comments, map[string]interface{}{"id": c.nextID, "body": body})
+	return nil
+}
+
+func (c *fakeCommentClient) UpdateIssueComment(commentID int64, body string) error {
+	return nil
+}
+
+func (c *fakeCommentClient) DeleteIssueComment(commentID int64) error {
+	c.deleted = append(c.deleted, commentID)
+	return nil
+}
+
+func stubSleep(t *testing.T) *[]time.Duration {
+	var slept []time.Duration
+	orig := sleep
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	t.Cleanup(func() { sleep = orig })
+	return &slept
+}
+
+func TestPostOrUpdateSchedulingComment_RetriesAbuseResponse(t *testing.T) {
+	slept := stubSleep(t)
+	client := &fakeCommentClient{createErrs: []error{abuseError{wait: 30 * time.Second}}}
+
+	si := github.SchedulingIssue{IssueNum: 1, Reason: "missing_estimate", Details: []string{"Low Estimate"}}
+	if err := PostOrUpdateSchedulingComment(client, si); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if client.createCalls != 2 {
+		t.Errorf("expected 2 create attempts, got %d", client.createCalls)
+	}
+	if len(client.comments) != 1 {
+		t.Errorf("expected the comment to be posted, got %d comments", len(client.comments))
+	}
+	if len(*slept) != 1 || (*slept)[0] != 30*time.Second {
+		t.Errorf("expected one 30s wait, got %v", *slept)
+	}
+}
+
+func TestPostOrUpdateSchedulingComment_OtherErrorsNotRetried(t *testing.T) {
+	slept := stubSleep(t)
+	client := &fakeCommentClient{createErrs: []error{errors.New("404 Not Found")}}
+
+	si := github.SchedulingIssue{IssueNum: 1, Reason: "cycle"}
+	if err := PostOrUpdateSchedulingComment(client, si); err == nil {
+		t.Fatal("expected error")
+	}
+	if client.createCalls != 1 || len(*slept) != 0 {
+		t.Errorf("expected a single attempt with no wait, got %d attempts and waits %v", client.createCalls, *slept)
+	}
+}
+
+// fakeDateFieldClient is a DateFieldClient whose calls fail with errs, in order
+type fakeDateFieldClient struct {
+	errs    []error
+	calls   int
+	updated []string
+}
+
+func (c *fakeDateFieldClient) next() error {
+	c.calls++
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return err
+	}
+	return nil
+}
+
+func (c *fakeDateFieldClient) ClearField(projectID, itemID, fieldID string) error {
+	return c.next()
+}
+
+func (c *fakeDateFieldClient) UpdateDateField(projectID, itemID, fieldID string, date time.Time) error {
+	if err := c.next(); err != nil {
+		return err
+	}
+	c.updated = append(c.updated, fieldID)
+	return nil
+}
+
+func dateUpdate() github.DateUpdate {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	return github.DateUpdate{
+		IssueNum: 1,
+		Project: &github.ProjectItemInfo{
+			ProjectID: "proj-1",
+			ItemID:    "item-1",
+			FieldIDs: map[string]string{
+				"Expected Start":      "start",
+				"Expected Completion": "completion",
+				"98% Completion":      "p98",
+			},
+		},
+		ExpectedStart:      day,
+		ExpectedCompletion: day.AddDate(0, 0, 2),
+		Completion98:       day.AddDate(0, 0, 5),
+	}
+}
+
+func TestApplyUpdate_RetriesAbuseResponseWithBackoff(t *testing.T) {
+	slept := stubSleep(t)
+	origJitter := jitter
+	jitter = func(d time.Duration) time.Duration { return d / 2 }
+	t.Cleanup(func() { jitter = origJitter })
+
+	client := &fakeDateFieldClient{errs: []error{abuseError{}, abuseError{}}}
+	if err := ApplyUpdate(client, dateUpdate()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(client.updated) != 3 {
+		t.Errorf("expected all 3 fields written, got %v", client.updated)
+	}
+	want := []time.Duration{time.Minute + 3*time.Second, 2*time.Minute + 6*time.Second}
+	if len(*slept) != len(want) || (*slept)[0] != want[0] || (*slept)[1] != want[1] {
+		t.Errorf("expected jittered exponential waits %v, got %v", want, *slept)
+	}
+}
+
+func TestApplyUpdate_AggregatesFailures(t *testing.T) {
+	stubSleep(t)
+	client := &fakeDateFieldClient{errs: []error{errors.New("404 Not Found"), nil, errors.New("502 Bad Gateway")}}
+
+	err := ApplyUpdate(client, dateUpdate())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"Expected Start", "98% Completion"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %s, got %v", want, err)
+		}
+	}
+	if len(client.updated) != 1 || client.updated[0] != "completion" {
+		t.Errorf("expected the remaining field to still be written, got %v", client.updated)
+	}
+}
+
+// headerTransport responds to every request with the given headers
+type headerTransport struct {
+	header http.Header
+	calls  int
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return &http.Response{StatusCode: http.StatusOK, Header: t.header, Body: http.NoBody, Request: req}, nil
+}
+
+func TestRateLimiter_PacesRequestsWhenBudgetIsLow(t *testing.T) {
+	origSleep, origNow := sleep, now
+	defer func() { sleep, now = origSleep, origNow }()
+	start := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	reset := strconv.FormatInt(start.Add(10*time.Minute).Unix(), 10)
+	next := &headerTransport{header: http.Header{"X-Ratelimit-Remaining": {"5000"}, "X-Ratelimit-Reset": {reset}}}
+	limiter := NewRateLimiter(next)
+	get := func(path string) {
+		req, _ := http.NewRequest("GET", "https://api.github.com"+path, nil)
+		if _, err := limiter.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	get("/repos/owner/repo")
+	get("/repos/owner/repo")
+	if len(waits) != 0 {
+		t.Fatalf("expected no pacing with plenty of budget, got %v", waits)
+	}
+
+	// 9 requests left for 10 minutes: one per minute
+	next.header.Set("X-RateLimit-Remaining", "9")
+	get("/repos/owner/repo")
+	get("/repos/owner/repo")
+	if len(waits) != 1 || waits[0] != time.Minute {
+		t.Fatalf("expected a one-minute pause, got %v", waits)
+	}
+
+	// The GraphQL budget is tracked separately from core
+	get("/graphql")
+	if len(waits) != 1 {
+		t.Fatalf("expected GraphQL requests not to wait for the core budget, got %v", waits)
+	}
+
+	// Exhausted: wait for the reset
+	next.header.Set("X-RateLimit-Remaining", "0")
+	next.header.Set("X-RateLimit-Resource", "graphql")
+	get("/graphql")
+	get("/graphql")
+	if got := waits[len(waits)-1]; got != 10*time.Minute {
+		t.Errorf("expected to wait until the reset, got %s", got)
+	}
+}
+
+func TestGraphQLFieldWriter_RetriesSecondaryRateLimit(t *testing.T) {
+	slept := stubSleep(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again.","documentation_url":"https://docs.github.com/graphql/overview/rate-limits-and-node-limits-for-the-graphql-api#secondary-rate-limits"}`))
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer server.Close()
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	if err := ApplyUpdate(GraphQLFieldWriter{Token: "token"}, dateUpdate()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected the batched write to be retried once, got %d requests", requests)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 30*time.Second {
+		t.Errorf("expected a 30s wait from Retry-After, got %v", *slept)
+	}
+}
@@ -0,0 +1,97 @@
+package ghscheduler
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+)
+
+// SchedulingStatusField is the name of the project's Scheduling Status single-select field
+const SchedulingStatusField = "Scheduling Status"
+
+// StatusFieldWriter sets and clears single-select fields on project items
+type StatusFieldWriter interface {
+	UpdateSingleSelectField(projectID, itemID, fieldID, optionID string) error
+	ClearField(projectID, itemID, fieldID string) error
+}
+
+// SetAtRiskStatus sets the Scheduling Status of each open issue in atRisk to the
+// atRiskOption option, and moves issues still marked atRiskOption that are no
+// longer at risk to onTrackOption (or clears the field if onTrackOption is "").
+// Projects without an atRiskOption option are left alone. It returns the refs
+// of the issues written.
+func SetAtRiskStatus(writer StatusFieldWriter, issues map[string]p2.IssueWithProject, atRisk map[string]bool, atRiskOption, onTrackOption string) ([]string, error) {
+	refs := make([]string, 0, len(issues))
+	for ref := range issues {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	var written []string
+	for _, ref := range refs {
+		iwp := issues[ref]
+		if iwp.Project == nil || iwp.IsDraft || strings.EqualFold(iwp.State, "closed") {
+			continue
+		}
+		fieldID, ok := iwp.Project.FieldIDs[SchedulingStatusField]
+		if !ok {
+			continue
+		}
+		options := iwp.Project.SingleSelectOptions[SchedulingStatusField]
+		atRiskID, ok := options[atRiskOption]
+		if !ok {
+			continue
+		}
+
+		var err error
+		switch {
+		case atRisk[ref] && iwp.SchedulingStatus != atRiskOption:
+			err = writer.UpdateSingleSelectField(iwp.Project.ProjectID, iwp.Project.ItemID, fieldID, atRiskID)
+		case !atRisk[ref] && iwp.SchedulingStatus == atRiskOption:
+			if onTrackOption == "" {
+				err = writer.ClearField(iwp.Project.ProjectID, iwp.Project.ItemID, fieldID)
+			} else if onTrackID, ok := options[onTrackOption]; ok {
+				err = writer.UpdateSingleSelectField(iwp.Project.ProjectID, iwp.Project.ItemID, fieldID, onTrackID)
+			} else {
+				continue
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return written, err
+		}
+		written = append(written, ref)
+	}
+	return written, nil
+}
+
+// UpdateSingleSelectField sets a single-select field on a project item to optionID
+func (g GraphQLFieldWriter) UpdateSingleSelectField(projectID, itemID, fieldID, optionID string) error {
+	query := `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) {
+    projectV2Item { id }
+  }
+}`
+	return graphQL(g.Token, query, map[string]interface{}{
+		"project": projectID,
+		"item":    itemID,
+		"field":   fieldID,
+		"option":  optionID,
+	}, nil)
+}
+
+// ClearField clears a field on a project item
+func (g GraphQLFieldWriter) ClearField(projectID, itemID, fieldID string) error {
+	query := `mutation($project: ID!, $item: ID!, $field: ID!) {
+  clearProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field}) {
+    projectV2Item { id }
+  }
+}`
+	return graphQL(g.Token, query, map[string]interface{}{
+		"project": projectID,
+		"item":    itemID,
+		"field":   fieldID,
+	}, nil)
+}
@@ -0,0 +1,79 @@
+package ghscheduler
+
+import (
+	"testing"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+	"github.com/octoberswimmer/p2/github"
+)
+
+type statusWrite struct {
+	itemID   string
+	optionID string
+}
+
+type fakeStatusWriter struct {
+	writes []statusWrite
+}
+
+func (f *fakeStatusWriter) UpdateSingleSelectField(projectID, itemID, fieldID, optionID string) error {
+	f.writes = append(f.writes, statusWrite{itemID: itemID, optionID: optionID})
+	return nil
+}
+
+func (f *fakeStatusWriter) ClearField(projectID, itemID, fieldID string) error {
+	f.writes = append(f.writes, statusWrite{itemID: itemID})
+	return nil
+}
+
+func statusProject(itemID string, options map[string]string) *github.ProjectItemInfo {
+	return &github.ProjectItemInfo{
+		ProjectID:           "proj-1",
+		ItemID:              itemID,
+		FieldIDs:            map[string]string{SchedulingStatusField: "status-field"},
+		SingleSelectOptions: map[string]map[string]string{SchedulingStatusField: options},
+	}
+}
+
+func TestSetAtRiskStatus(t *testing.T) {
+	options := map[string]string{"At Risk": "opt-risk", "On Track": "opt-ok"}
+	issues := map[string]p2.IssueWithProject{
+		"github.com/owner/repo/issues/1": {IssueNum: 1, State: "open", Project: statusProject("item-1", options)},
+		"github.com/owner/repo/issues/2": {IssueNum: 2, State: "open", SchedulingStatus: "At Risk", Project: statusProject("item-2", options)},
+		"github.com/owner/repo/issues/3": {IssueNum: 3, State: "open", SchedulingStatus: "At Risk", Project: statusProject("item-3", options)},
+		"github.com/owner/repo/issues/4": {IssueNum: 4, State: "open", Project: statusProject("item-4", map[string]string{"On Track": "opt-ok"})},
+	}
+	atRisk := map[string]bool{
+		"github.com/owner/repo/issues/1": true,
+		"github.com/owner/repo/issues/3": true,
+		"github.com/owner/repo/issues/4": true,
+	}
+
+	writer := &fakeStatusWriter{}
+	written, err := SetAtRiskStatus(writer, issues, atRisk, "At Risk", "On Track")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// #1 becomes at risk, #2 recovers, #3 is already at risk, #4's project has no At Risk option
+	want := []statusWrite{{itemID: "item-1", optionID: "opt-risk"}, {itemID: "item-2", optionID: "opt-ok"}}
+	if len(writer.writes) != len(want) {
+		t.Fatalf("expected %d writes, got %+v", len(want), writer.writes)
+	}
+	for i, w := range want {
+		if writer.writes[i] != w {
+			t.Errorf("write %d: expected %+v, got %+v", i, w, writer.writes[i])
+		}
+	}
+	if len(written) != 2 {
+		t.Errorf("expected 2 issues written, got %v", written)
+	}
+
+	writer = &fakeStatusWriter{}
+	if _, err := SetAtRiskStatus(writer, issues, nil, "At Risk", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(writer.writes) != 2 || writer.writes[0].optionID != "" {
+		t.Errorf("expected recovered issues to have the field cleared, got %+v", writer.writes)
+	}
+}
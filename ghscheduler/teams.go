@@ -0,0 +1,51 @@
+package ghscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// apiBaseURL is the GitHub REST API base URL (overridden in tests)
+var apiBaseURL = "https://api.github.com"
+
+// FetchTeamMembers returns the logins of the members of an organization team
+func FetchTeamMembers(token, org, teamSlug string) ([]string, error) {
+	var logins []string
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/orgs/%s/teams/%s/members?per_page=100&page=%d", apiBaseURL, org, teamSlug, page)
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("team members API returned %d for %s/%s", resp.StatusCode, org, teamSlug)
+		}
+
+		var members []struct {
+			Login string `json:"login"`
+		}
+		if err := json.Unmarshal(body, &members); err != nil {
+			return nil, fmt.Errorf("failed to decode team members: %w", err)
+		}
+		for _, m := range members {
+			logins = append(logins, m.Login)
+		}
+		if len(members) < 100 {
+			return logins, nil
+		}
+	}
+}
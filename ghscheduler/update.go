@@ -1,72 +1,156 @@
 package ghscheduler
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
 	"github.com/octoberswimmer/p2/github"
 	"github.com/sirupsen/logrus"
 )
 
-// ApplyUpdate writes date updates to GitHub
-func ApplyUpdate(client *github.Client, update github.DateUpdate) error {
+// DateFieldClient clears and sets project item fields
+type DateFieldClient interface {
+	ClearField(projectID, itemID, fieldID string) error
+	UpdateDateField(projectID, itemID, fieldID string, date time.Time) error
+}
+
+// FieldChange sets a date field on a project item, or clears it if Date is zero
+type FieldChange struct {
+	Name    string
+	FieldID string
+	Date    time.Time
+}
+
+// BatchFieldClient is implemented by clients that can apply several field
+// changes to one project item in a single request
+type BatchFieldClient interface {
+	UpdateItemFields(projectID, itemID string, changes []FieldChange) error
+}
+
+// ApplyUpdate writes date updates to GitHub using the default field names
+func ApplyUpdate(client DateFieldClient, update github.DateUpdate) error {
+	return ApplyUpdateWithFields(client, update, p2.DefaultFieldNames, false, nil)
+}
+
+// ApplyUpdateWithFields writes date updates to GitHub, looking fields up by names.
+// Clearing a closed issue also clears its estimates unless keepEstimatesOnClose is set.
+// If client is a BatchFieldClient, all of the issue's fields are written in one
+// request; otherwise each field is written separately, and fields that fail are
+// skipped and reported together in the returned error. Mutations are retried when
+// GitHub's secondary rate limit rejects them. Log lines name the issue's
+// owner and repo through privacy.LogFields.
+func ApplyUpdateWithFields(client DateFieldClient, update github.DateUpdate, names p2.FieldNames, keepEstimatesOnClose bool, privacy *p2.PrivacyFilter) error {
+	names = names.WithDefaults()
 	if update.Project == nil {
 		return fmt.Errorf("no project info")
 	}
 
-	// Clear scheduling fields for closed/on-hold tasks
+	var changes []FieldChange
 	if update.ClearDates {
-		// Always clear date fields
+		// Clear scheduling fields for closed/on-hold tasks
 		fieldsToClean := []string{
-			"Expected Start",
-			"Expected Completion",
-			"98% Completion",
+			names.ExpectedStart,
+			names.ExpectedCompletion,
+			names.Completion98,
 		}
-		// Only clear estimates if closed (not on hold)
-		if update.ClearReason == "closed" {
-			fieldsToClean = append(fieldsToClean, "Low Estimate", "High Estimate")
+		// Only clear estimates if closed or archived (not on hold)
+		if (update.ClearReason == "closed" && !keepEstimatesOnClose) || update.ClearReason == "archived" {
+			fieldsToClean = append(fieldsToClean, names.LowEstimate, names.HighEstimate)
 		}
 		for _, fieldName := range fieldsToClean {
 			if fieldID, ok := update.Project.FieldIDs[fieldName]; ok {
-				if err := client.ClearField(update.Project.ProjectID, update.Project.ItemID, fieldID); err != nil {
-					logrus.Warnf("Failed to clear %s for #%d: %v", fieldName, update.IssueNum, err)
-				}
+				changes = append(changes, FieldChange{Name: fieldName, FieldID: fieldID})
+			}
+		}
+	} else {
+		for _, field := range []struct {
+			name string
+			date time.Time
+		}{
+			{names.ExpectedStart, update.ExpectedStart},
+			{names.ExpectedCompletion, update.ExpectedCompletion},
+			{names.Completion98, update.Completion98},
+		} {
+			if field.date.IsZero() {
+				continue
 			}
+			fieldID, ok := update.Project.FieldIDs[field.name]
+			if !ok {
+				logrus.WithFields(privacy.LogFields(update.Owner, update.Repo, update.IssueNum)).Debugf("No '%s' field found", field.name)
+				continue
+			}
+			changes = append(changes, FieldChange{Name: field.name, FieldID: fieldID, Date: field.date})
 		}
+	}
+	if len(changes) == 0 {
 		return nil
 	}
 
-	// Update Expected Start
-	if !update.ExpectedStart.IsZero() {
-		if fieldID, ok := update.Project.FieldIDs["Expected Start"]; ok {
-			if err := client.UpdateDateField(update.Project.ProjectID, update.Project.ItemID, fieldID, update.ExpectedStart); err != nil {
-				logrus.Warnf("Failed to update Expected Start for #%d: %v", update.IssueNum, err)
+	if batch, ok := client.(BatchFieldClient); ok {
+		err := withAbuseRetry(func() error {
+			return batch.UpdateItemFields(update.Project.ProjectID, update.Project.ItemID, changes)
+		})
+		if err != nil {
+			fieldNames := make([]string, len(changes))
+			for i, c := range changes {
+				fieldNames[i] = c.Name
 			}
-		} else {
-			logrus.Debugf("No 'Expected Start' field found for issue #%d", update.IssueNum)
+			return fmt.Errorf("failed to update %s: %w", strings.Join(fieldNames, ", "), err)
 		}
+		return nil
 	}
 
-	// Update Expected Completion
-	if !update.ExpectedCompletion.IsZero() {
-		if fieldID, ok := update.Project.FieldIDs["Expected Completion"]; ok {
-			if err := client.UpdateDateField(update.Project.ProjectID, update.Project.ItemID, fieldID, update.ExpectedCompletion); err != nil {
-				logrus.Warnf("Failed to update Expected Completion for #%d: %v", update.IssueNum, err)
+	var errs []error
+	for _, c := range changes {
+		if c.Date.IsZero() {
+			err := withAbuseRetry(func() error {
+				return client.ClearField(update.Project.ProjectID, update.Project.ItemID, c.FieldID)
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to clear %s: %w", c.Name, err))
 			}
-		} else {
-			logrus.Debugf("No 'Expected Completion' field found for issue #%d", update.IssueNum)
+			continue
+		}
+		err := withAbuseRetry(func() error {
+			return client.UpdateDateField(update.Project.ProjectID, update.Project.ItemID, c.FieldID, c.Date)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update %s: %w", c.Name, err))
 		}
 	}
+	return errors.Join(errs...)
+}
 
-	// Update 98% Completion
-	if !update.Completion98.IsZero() {
-		if fieldID, ok := update.Project.FieldIDs["98% Completion"]; ok {
-			if err := client.UpdateDateField(update.Project.ProjectID, update.Project.ItemID, fieldID, update.Completion98); err != nil {
-				logrus.Warnf("Failed to update 98%% Completion for #%d: %v", update.IssueNum, err)
-			}
-		} else {
-			logrus.Debugf("No '98%% Completion' field found for issue #%d", update.IssueNum)
+// UpdateDateField sets a date field on a project item
+func (g GraphQLFieldWriter) UpdateDateField(projectID, itemID, fieldID string, date time.Time) error {
+	return g.UpdateItemFields(projectID, itemID, []FieldChange{{FieldID: fieldID, Date: date}})
+}
+
+// UpdateItemFields applies changes to a project item in a single GraphQL
+// request, aliasing one mutation per field
+func (g GraphQLFieldWriter) UpdateItemFields(projectID, itemID string, changes []FieldChange) error {
+	variables := map[string]interface{}{
+		"project": projectID,
+		"item":    itemID,
+	}
+	params := []string{"$project: ID!", "$item: ID!"}
+	var body strings.Builder
+	for i, c := range changes {
+		field := fmt.Sprintf("f%d", i)
+		variables[field] = c.FieldID
+		params = append(params, fmt.Sprintf("$%s: ID!", field))
+		if c.Date.IsZero() {
+			fmt.Fprintf(&body, "  %s: clearProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $%s}) {\n    projectV2Item { id }\n  }\n", field, field)
+			continue
 		}
+		value := fmt.Sprintf("v%d", i)
+		variables[value] = c.Date.Format("2006-01-02")
+		params = append(params, fmt.Sprintf("$%s: Date!", value))
+		fmt.Fprintf(&body, "  %s: updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $%s, value: {date: $%s}}) {\n    projectV2Item { id }\n  }\n", field, field, value)
 	}
-
-	return nil
+	query := fmt.Sprintf("mutation(%s) {\n%s}", strings.Join(params, ", "), body.String())
+	return graphQL(g.Token, query, variables, nil)
 }
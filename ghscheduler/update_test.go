@@ -0,0 +1,124 @@
+package ghscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+)
+
+func TestApplyUpdate_BatchesFieldsIntoOneRequest(t *testing.T) {
+	var requests []struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	if err := ApplyUpdate(GraphQLFieldWriter{Token: "test-token"}, dateUpdate()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	req := requests[0]
+	if n := strings.Count(req.Query, "updateProjectV2ItemFieldValue"); n != 3 {
+		t.Errorf("expected 3 aliased mutations, got %d in:\n%s", n, req.Query)
+	}
+	want := map[string]interface{}{
+		"f0": "start", "v0": "2026-03-02",
+		"f1": "completion", "v1": "2026-03-04",
+		"f2": "p98", "v2": "2026-03-07",
+	}
+	for k, v := range want {
+		if req.Variables[k] != v {
+			t.Errorf("expected variable %s=%v, got %v", k, v, req.Variables[k])
+		}
+	}
+}
+
+func TestApplyUpdate_BatchClearsFields(t *testing.T) {
+	var query string
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		query = req.Query
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	update := dateUpdate()
+	update.ClearDates = true
+	update.ClearReason = "on hold"
+	if err := ApplyUpdate(GraphQLFieldWriter{Token: "test-token"}, update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || strings.Count(query, "clearProjectV2ItemFieldValue") != 3 {
+		t.Errorf("expected one request clearing 3 fields, got %d requests:\n%s", calls, query)
+	}
+}
+
+func TestApplyUpdateWithFields_KeepsEstimatesOnClose(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		query = req.Query
+		fmt.Fprint(w, `{"data":{}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	update := dateUpdate()
+	update.Project.FieldIDs["Low Estimate"] = "low"
+	update.Project.FieldIDs["High Estimate"] = "high"
+	update.ClearDates = true
+	update.ClearReason = "closed"
+
+	if err := ApplyUpdateWithFields(GraphQLFieldWriter{Token: "test-token"}, update, p2.DefaultFieldNames, false, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := strings.Count(query, "clearProjectV2ItemFieldValue"); n != 5 {
+		t.Errorf("expected closed issue to clear dates and estimates, got %d clears:\n%s", n, query)
+	}
+
+	if err := ApplyUpdateWithFields(GraphQLFieldWriter{Token: "test-token"}, update, p2.DefaultFieldNames, true, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := strings.Count(query, "clearProjectV2ItemFieldValue"); n != 3 {
+		t.Errorf("expected only the 3 date fields to be cleared, got %d clears:\n%s", n, query)
+	}
+}
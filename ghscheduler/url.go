@@ -0,0 +1,70 @@
+package ghscheduler
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// projectViewSuffix matches the "/views/N" tab suffix of a project URL
+var projectViewSuffix = regexp.MustCompile(`(/projects/\d+)/views/\d+/?$`)
+
+// NormalizeURL strips the parts of a URL copied from the browser that
+// ParseGitHubURL doesn't expect: any ?query or #fragment, and a trailing
+// /views/N on project URLs.
+func NormalizeURL(rawURL string) string {
+	u := strings.TrimSpace(rawURL)
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	u = projectViewSuffix.ReplaceAllString(u, "$1")
+	return strings.TrimSuffix(u, "/")
+}
+
+// pullRequestURL matches "https://github.com/owner/repo/pull/N" and its tabs (e.g. /files)
+var pullRequestURL = regexp.MustCompile(`^(?:https?://)?github\.com/([^/]+)/([^/]+)/pull/(\d+)(?:/[a-z]+)?$`)
+
+// PullRequestRef identifies a pull request
+type PullRequestRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// ParsePullRequestURL returns the pull request a normalized URL refers to.
+// ok is false for any other URL.
+func ParsePullRequestURL(rawURL string) (PullRequestRef, bool) {
+	m := pullRequestURL.FindStringSubmatch(rawURL)
+	if m == nil {
+		return PullRequestRef{}, false
+	}
+	num, err := strconv.Atoi(m[3])
+	if err != nil {
+		return PullRequestRef{}, false
+	}
+	return PullRequestRef{Owner: m[1], Repo: m[2], Number: num}, true
+}
+
+// projectURL matches "https://github.com/orgs/org/projects/N" and its /users/ form
+var projectURL = regexp.MustCompile(`^(?:https?://)?github\.com/(orgs|users)/([^/]+)/projects/(\d+)$`)
+
+// ProjectRef identifies a GitHub project
+type ProjectRef struct {
+	Owner  string
+	Number int
+	IsOrg  bool
+}
+
+// ParseProjectURL returns the project a normalized URL refers to.
+// ok is false for any other URL.
+func ParseProjectURL(rawURL string) (ProjectRef, bool) {
+	m := projectURL.FindStringSubmatch(rawURL)
+	if m == nil {
+		return ProjectRef{}, false
+	}
+	num, err := strconv.Atoi(m[3])
+	if err != nil {
+		return ProjectRef{}, false
+	}
+	return ProjectRef{Owner: m[2], Number: num, IsOrg: m[1] == "orgs"}, true
+}
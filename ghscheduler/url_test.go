@@ -0,0 +1,23 @@
+package ghscheduler
+
+import "testing"
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://github.com/orgs/myorg/projects/7", "https://github.com/orgs/myorg/projects/7"},
+		{"https://github.com/orgs/myorg/projects/7/views/3", "https://github.com/orgs/myorg/projects/7"},
+		{"https://github.com/orgs/myorg/projects/7?filterQuery=is%3Aopen", "https://github.com/orgs/myorg/projects/7"},
+		{"https://github.com/orgs/myorg/projects/7/views/3?sliceBy=assignee", "https://github.com/orgs/myorg/projects/7"},
+		{"https://github.com/users/me/projects/2/views/1#board", "https://github.com/users/me/projects/2"},
+		{"https://github.com/owner/repo/issues/12?pane=issue", "https://github.com/owner/repo/issues/12"},
+		{"owner/repo", "owner/repo"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeURL(tt.in); got != tt.want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package ghscheduler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FetchPrivateRepos returns whether each "owner/repo" in repos is private
+func FetchPrivateRepos(token string, repos []string) (map[string]bool, error) {
+	private := make(map[string]bool)
+	for start := 0; start < len(repos); start += fieldValueBatchSize {
+		end := min(start+fieldValueBatchSize, len(repos))
+
+		var params, fields []string
+		variables := make(map[string]interface{})
+		aliases := make(map[string]string)
+		for i, repo := range repos[start:end] {
+			owner, name, ok := strings.Cut(repo, "/")
+			if !ok {
+				return nil, fmt.Errorf("invalid repository %q: expected owner/repo", repo)
+			}
+			alias := fmt.Sprintf("r%d", i)
+			params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!", i, i))
+			fields = append(fields, fmt.Sprintf("  %s: repository(owner: $o%d, name: $n%d) { isPrivate }", alias, i, i))
+			variables[fmt.Sprintf("o%d", i)] = owner
+			variables[fmt.Sprintf("n%d", i)] = name
+			aliases[alias] = repo
+		}
+		query := fmt.Sprintf("query(%s) {\n%s\n}", strings.Join(params, ", "), strings.Join(fields, "\n"))
+
+		var result map[string]*struct {
+			IsPrivate bool `json:"isPrivate"`
+		}
+		if err := graphQL(token, query, variables, &result); err != nil {
+			return nil, err
+		}
+		for alias, repo := range result {
+			if repo != nil {
+				private[aliases[alias]] = repo.IsPrivate
+			}
+		}
+	}
+	return private, nil
+}
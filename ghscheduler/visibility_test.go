@@ -0,0 +1,37 @@
+package ghscheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchPrivateRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Variables["o0"] != "myorg" || req.Variables["n0"] != "secret" {
+			t.Errorf("expected first repo myorg/secret, got %v", req.Variables)
+		}
+		fmt.Fprint(w, `{"data":{"r0":{"isPrivate":true},"r1":{"isPrivate":false}}}`)
+	}))
+	defer server.Close()
+
+	origBase := apiBaseURL
+	apiBaseURL = server.URL
+	defer func() { apiBaseURL = origBase }()
+
+	private, err := FetchPrivateRepos("test-token", []string{"myorg/secret", "myorg/public"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !private["myorg/secret"] || private["myorg/public"] {
+		t.Errorf("expected only myorg/secret to be private, got %v", private)
+	}
+}
@@ -0,0 +1,83 @@
+package ghscheduler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// VerifyWebhookSignature reports whether signature, the X-Hub-Signature-256
+// header of a webhook delivery, is the HMAC-SHA256 of body keyed with secret
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	if secret == "" || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// WebhookEvent identifies where a webhook delivery's change happened
+type WebhookEvent struct {
+	Owner string
+	// Repo is empty for project item events, which aren't tied to a repository
+	Repo string
+}
+
+// ParseWebhookEvent reads the owner, and for issues events the repository,
+// from a projects_v2_item or issues delivery. ok is false for other events.
+func ParseWebhookEvent(event string, body []byte) (WebhookEvent, bool, error) {
+	var payload struct {
+		Organization struct {
+			Login string `json:"login"`
+		} `json:"organization"`
+		Repository struct {
+			Name  string `json:"name"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	}
+	switch event {
+	case "projects_v2_item", "issues":
+	default:
+		return WebhookEvent{}, false, nil
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return WebhookEvent{}, false, fmt.Errorf("failed to parse %s delivery: %w", event, err)
+	}
+	if event == "projects_v2_item" {
+		if payload.Organization.Login == "" {
+			return WebhookEvent{}, false, fmt.Errorf("projects_v2_item delivery has no organization")
+		}
+		return WebhookEvent{Owner: payload.Organization.Login}, true, nil
+	}
+	if payload.Repository.Owner.Login == "" || payload.Repository.Name == "" {
+		return WebhookEvent{}, false, fmt.Errorf("issues delivery has no repository")
+	}
+	return WebhookEvent{Owner: payload.Repository.Owner.Login, Repo: payload.Repository.Name}, true, nil
+}
+
+// webhookTargetURL matches the owner of a project URL, or the owner and
+// repository of a repository, issue, or short-form URL
+var webhookTargetURL = regexp.MustCompile(`^(?:https?://)?(?:github\.com/)?(?:(?:orgs|users)/([^/]+)/projects/\d+|([^/]+)/([^/#]+))`)
+
+// Affects reports whether the change may alter the schedule of rawURL. Project
+// URLs are affected by any change under their owner, since their items can
+// come from any of its repositories; other URLs only by issue changes in
+// their repository.
+func (e WebhookEvent) Affects(rawURL string) bool {
+	m := webhookTargetURL.FindStringSubmatch(rawURL)
+	if m == nil {
+		return false
+	}
+	if m[1] != "" {
+		return strings.EqualFold(m[1], e.Owner)
+	}
+	return e.Repo != "" && strings.EqualFold(m[2], e.Owner) && strings.EqualFold(m[3], e.Repo)
+}
@@ -0,0 +1,62 @@
+package ghscheduler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"action":"edited"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !VerifyWebhookSignature("s3cret", body, signature) {
+		t.Error("expected signature made with the shared secret to verify")
+	}
+	if VerifyWebhookSignature("other", body, signature) {
+		t.Error("expected signature made with another secret to be rejected")
+	}
+	if VerifyWebhookSignature("s3cret", []byte(`{"action":"deleted"}`), signature) {
+		t.Error("expected signature of a different body to be rejected")
+	}
+	if VerifyWebhookSignature("", body, "sha256=") {
+		t.Error("expected an empty secret to reject every delivery")
+	}
+}
+
+func TestParseWebhookEvent_AffectedURLs(t *testing.T) {
+	item, ok, err := ParseWebhookEvent("projects_v2_item", []byte(`{"action":"edited","organization":{"login":"myorg"}}`))
+	if err != nil || !ok {
+		t.Fatalf("expected projects_v2_item to parse, got ok=%v err=%v", ok, err)
+	}
+	issue, ok, err := ParseWebhookEvent("issues", []byte(`{"action":"closed","repository":{"name":"api","owner":{"login":"myorg"}}}`))
+	if err != nil || !ok {
+		t.Fatalf("expected issues to parse, got ok=%v err=%v", ok, err)
+	}
+	if _, ok, _ := ParseWebhookEvent("push", []byte(`{}`)); ok {
+		t.Error("expected push deliveries to be ignored")
+	}
+
+	tests := []struct {
+		event WebhookEvent
+		url   string
+		want  bool
+	}{
+		{item, "https://github.com/orgs/myorg/projects/1", true},
+		{item, "https://github.com/orgs/other/projects/1", false},
+		{item, "https://github.com/myorg/api", false},
+		{issue, "https://github.com/orgs/myorg/projects/1", true},
+		{issue, "https://github.com/myorg/api", true},
+		{issue, "https://github.com/myorg/api/issues/12", true},
+		{issue, "myorg/api", true},
+		{issue, "myorg/web", false},
+	}
+	for _, tt := range tests {
+		if got := tt.event.Affects(tt.url); got != tt.want {
+			t.Errorf("%+v.Affects(%q) = %v, want %v", tt.event, tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Package gitlab schedules GitLab issues with the p2 scheduling core.
+//
+// GitLab issues have a single date field, so the scheduler writes each issue's
+// Expected Completion to its due date. Estimates come from scoped labels such
+// as "estimate::2-6" (low-high hours) or "estimate::4", and "is blocked by"
+// issue links become dependencies.
+package gitlab
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+	"github.com/octoberswimmer/p2/github"
+)
+
+// EstimateLabelPrefix starts the scoped label holding an issue's estimate
+const EstimateLabelPrefix = "estimate::"
+
+// Client reads and writes the issues of one GitLab project
+type Client struct {
+	// BaseURL is the GitLab instance, e.g. https://gitlab.com
+	BaseURL string
+	// Project is the project's full path, e.g. group/subgroup/project
+	Project string
+	// Token is a personal, group, or project access token with the api scope
+	Token string
+
+	// dueDates holds each fetched issue's due date, keyed by issue number
+	dueDates map[int]time.Time
+}
+
+// ParseProjectURL returns the instance and project path of a GitLab project
+// or issue URL (e.g. https://gitlab.com/group/project/-/issues/3). ok is false
+// for GitHub URLs and short forms, which have no host.
+func ParseProjectURL(rawURL string) (baseURL, project string, ok bool) {
+	u, err := neturl.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", "", false
+	}
+	if host := strings.ToLower(u.Host); host == "github.com" || host == "www.github.com" {
+		return "", "", false
+	}
+	path, _, _ := strings.Cut(strings.Trim(u.Path, "/"), "/-/")
+	if strings.Count(path, "/") < 1 {
+		return "", "", false
+	}
+	return u.Scheme + "://" + u.Host, path, true
+}
+
+// issue is the subset of a GitLab issue the scheduler uses
+type issue struct {
+	IID         int      `json:"iid"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"`
+	Labels      []string `json:"labels"`
+	DueDate     string   `json:"due_date"`
+	Assignees   []struct {
+		Username string `json:"username"`
+	} `json:"assignees"`
+	Milestone *struct {
+		Title   string `json:"title"`
+		DueDate string `json:"due_date"`
+	} `json:"milestone"`
+}
+
+// issueLink is an issue linked to another, as returned by the issue links API
+type issueLink struct {
+	State      string `json:"state"`
+	LinkType   string `json:"link_type"`
+	References struct {
+		Full string `json:"full"`
+	} `json:"references"`
+}
+
+// FetchIssues returns the project's issues in board order, keyed like GitHub
+// issues so the scheduling core can convert them
+func (c *Client) FetchIssues() (map[string]p2.IssueWithProject, error) {
+	owner, repo := c.ownerRepo()
+	issues := make(map[string]p2.IssueWithProject)
+	c.dueDates = make(map[int]time.Time)
+	order := 0
+	for page := "1"; page != ""; {
+		var batch []issue
+		next, err := c.get("/issues?state=all&order_by=relative_position&sort=asc&per_page=100&page="+page, &batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch issues: %w", err)
+		}
+		for _, gi := range batch {
+			iwp, err := c.convert(gi, owner, repo)
+			if err != nil {
+				return nil, err
+			}
+			iwp.Order = order
+			order++
+			if iwp.ExpectedCompletion != nil {
+				c.dueDates[gi.IID] = *iwp.ExpectedCompletion
+			}
+			issues[fmt.Sprintf("github.com/%s/%s/issues/%d", owner, repo, gi.IID)] = iwp
+		}
+		page = next
+	}
+	return issues, nil
+}
+
+// convert maps a GitLab issue to the scheduler's issue type
+func (c *Client) convert(gi issue, owner, repo string) (p2.IssueWithProject, error) {
+	iwp := p2.IssueWithProject{
+		Owner:    owner,
+		Repo:     repo,
+		IssueNum: gi.IID,
+		Title:    gi.Title,
+		Body:     gi.Description,
+		State:    "open",
+		Labels:   gi.Labels,
+		Project:  &github.ProjectItemInfo{ProjectID: c.Project, ItemID: strconv.Itoa(gi.IID)},
+	}
+	if gi.State == "closed" {
+		iwp.State = "closed"
+	}
+	if len(gi.Assignees) > 0 {
+		iwp.Assignee = gi.Assignees[0].Username
+	}
+	if gi.Milestone != nil {
+		iwp.Milestone = gi.Milestone.Title
+		if due, err := time.Parse("2006-01-02", gi.Milestone.DueDate); err == nil {
+			iwp.MilestoneDueDate = &due
+		}
+	}
+	if due, err := time.Parse("2006-01-02", gi.DueDate); err == nil {
+		iwp.ExpectedCompletion = &due
+		iwp.HasSchedulingDates = true
+	}
+	for _, label := range gi.Labels {
+		if value, ok := strings.CutPrefix(label, EstimateLabelPrefix); ok {
+			low, high, err := parseEstimate(value)
+			if err != nil {
+				return iwp, fmt.Errorf("issue #%d: invalid label %q: %w", gi.IID, label, err)
+			}
+			iwp.LowEstimate, iwp.HighEstimate = &low, &high
+		}
+	}
+
+	if iwp.State == "open" {
+		var links []issueLink
+		if _, err := c.get(fmt.Sprintf("/issues/%d/links", gi.IID), &links); err != nil {
+			return iwp, fmt.Errorf("failed to fetch links for issue #%d: %w", gi.IID, err)
+		}
+		for _, link := range links {
+			if link.LinkType != "is_blocked_by" {
+				continue
+			}
+			ref, ok := parseReference(link.References.Full)
+			if !ok {
+				continue
+			}
+			ref.State = "open"
+			if link.State == "closed" {
+				ref.State = "closed"
+			}
+			iwp.BlockedBy = append(iwp.BlockedBy, ref)
+		}
+	}
+	return iwp, nil
+}
+
+// parseEstimate parses "low-high" or a single number of hours
+func parseEstimate(value string) (low, high float64, err error) {
+	lowStr, highStr, isRange := strings.Cut(value, "-")
+	low, err = strconv.ParseFloat(strings.TrimSpace(lowStr), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("expected hours or low-high")
+	}
+	high = low
+	if isRange {
+		high, err = strconv.ParseFloat(strings.TrimSpace(highStr), 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("expected hours or low-high")
+		}
+	}
+	return low, high, nil
+}
+
+// parseReference parses a full GitLab issue reference like "group/project#3"
+func parseReference(full string) (github.IssueRef, bool) {
+	path, numStr, ok := strings.Cut(full, "#")
+	if !ok {
+		return github.IssueRef{}, false
+	}
+	num, err := strconv.Atoi(numStr)
+	if err != nil {
+		return github.IssueRef{}, false
+	}
+	i := strings.LastIndex(path, "/")
+	if i <= 0 {
+		return github.IssueRef{}, false
+	}
+	return github.IssueRef{Owner: path[:i], Repo: path[i+1:], Number: num}, true
+}
+
+// NeedsUpdate reports whether update changes the issue's due date, the only
+// date GitLab stores
+func (c *Client) NeedsUpdate(update p2.DateUpdate) bool {
+	current, ok := c.dueDates[update.IssueNum]
+	if update.ClearDates {
+		return ok
+	}
+	return !ok || current.Format("2006-01-02") != update.ExpectedCompletion.Format("2006-01-02")
+}
+
+// ApplyUpdate writes update's Expected Completion to the issue's due date,
+// or clears the due date of closed and on-hold issues
+func (c *Client) ApplyUpdate(update p2.DateUpdate) error {
+	var due interface{}
+	if !update.ClearDates {
+		due = update.ExpectedCompletion.Format("2006-01-02")
+	}
+	body, err := json.Marshal(map[string]interface{}{"due_date": due})
+	if err != nil {
+		return err
+	}
+	_, err = c.do("PUT", fmt.Sprintf("/issues/%d", update.IssueNum), bytes.NewReader(body), nil)
+	return err
+}
+
+// ownerRepo splits the project path into the namespace and project name
+func (c *Client) ownerRepo() (owner, repo string) {
+	i := strings.LastIndex(c.Project, "/")
+	return c.Project[:i], c.Project[i+1:]
+}
+
+// get decodes a GET of path (relative to the project) into out, returning
+// the next page number, if any
+func (c *Client) get(path string, out interface{}) (string, error) {
+	return c.do("GET", path, nil, out)
+}
+
+// do sends a request for path (relative to the project) and decodes the
+// response into out, which may be nil. It returns the X-Next-Page header.
+func (c *Client) do(method, path string, body io.Reader, out interface{}) (string, error) {
+	url := fmt.Sprintf("%s/api/v4/projects/%s%s", c.BaseURL, neturl.PathEscape(c.Project), path)
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return "", err
+	}
+	if c.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("GitLab API returned %d for %s %s", resp.StatusCode, method, path)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return "", fmt.Errorf("failed to decode GitLab response: %w", err)
+		}
+	}
+	return resp.Header.Get("X-Next-Page"), nil
+}
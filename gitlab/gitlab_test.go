@@ -0,0 +1,126 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+)
+
+func TestParseProjectURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		base    string
+		project string
+		ok      bool
+	}{
+		{"https://gitlab.com/group/project", "https://gitlab.com", "group/project", true},
+		{"https://gitlab.example.com/group/sub/project/-/issues/3", "https://gitlab.example.com", "group/sub/project", true},
+		{"https://github.com/owner/repo", "", "", false},
+		{"owner/repo", "", "", false},
+		{"https://gitlab.com/group", "", "", false},
+	}
+	for _, tt := range tests {
+		base, project, ok := ParseProjectURL(tt.url)
+		if base != tt.base || project != tt.project || ok != tt.ok {
+			t.Errorf("ParseProjectURL(%q) = %q, %q, %v; want %q, %q, %v", tt.url, base, project, ok, tt.base, tt.project, tt.ok)
+		}
+	}
+}
+
+func TestFetchIssues_ConvertsIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "test-token" {
+			t.Errorf("expected token header, got %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		switch r.URL.Path {
+		case "/api/v4/projects/group/project/issues":
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[{"iid":1,"title":"Build API","state":"opened","labels":["estimate::2-6"],
+					"assignees":[{"username":"alice"}],"milestone":{"title":"v1","due_date":"2025-03-01"},"due_date":"2025-02-10"}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"iid":2,"title":"Old work","state":"closed","labels":["estimate::4"]}]`)
+		case "/api/v4/projects/group/project/issues/1/links":
+			fmt.Fprint(w, `[{"state":"opened","link_type":"is_blocked_by","references":{"full":"group/infra#7"}},
+				{"state":"opened","link_type":"relates_to","references":{"full":"group/project#9"}}]`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Project: "group/project", Token: "test-token"}
+	issues, err := client.FetchIssues()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+
+	open := issues["github.com/group/project/issues/1"]
+	if open.State != "open" || open.Assignee != "alice" || open.Milestone != "v1" || open.Project == nil {
+		t.Errorf("unexpected open issue: %+v", open)
+	}
+	if open.LowEstimate == nil || *open.LowEstimate != 2 || open.HighEstimate == nil || *open.HighEstimate != 6 {
+		t.Errorf("expected estimate 2-6, got %v-%v", open.LowEstimate, open.HighEstimate)
+	}
+	if len(open.BlockedBy) != 1 || open.BlockedBy[0].Owner != "group" || open.BlockedBy[0].Repo != "infra" || open.BlockedBy[0].Number != 7 {
+		t.Errorf("expected blocked by group/infra#7, got %+v", open.BlockedBy)
+	}
+	if !open.HasSchedulingDates || open.ExpectedCompletion.Format("2006-01-02") != "2025-02-10" {
+		t.Errorf("expected due date as Expected Completion, got %v", open.ExpectedCompletion)
+	}
+
+	closed := issues["github.com/group/project/issues/2"]
+	if closed.State != "closed" || closed.LowEstimate == nil || *closed.LowEstimate != 4 || *closed.HighEstimate != 4 {
+		t.Errorf("unexpected closed issue: %+v", closed)
+	}
+	if closed.Order <= open.Order {
+		t.Errorf("expected issues to keep board order, got %d and %d", open.Order, closed.Order)
+	}
+
+	due := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	if client.NeedsUpdate(p2.DateUpdate{IssueNum: 1, ExpectedCompletion: due}) {
+		t.Error("expected unchanged due date not to need an update")
+	}
+	if !client.NeedsUpdate(p2.DateUpdate{IssueNum: 1, ExpectedCompletion: due.AddDate(0, 0, 1)}) {
+		t.Error("expected new due date to need an update")
+	}
+	if client.NeedsUpdate(p2.DateUpdate{IssueNum: 2, ClearDates: true}) {
+		t.Error("expected clearing an issue without a due date not to need an update")
+	}
+}
+
+func TestApplyUpdate_WritesDueDate(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/v4/projects/group/project/issues/3" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Project: "group/project"}
+	update := p2.DateUpdate{IssueNum: 3, ExpectedCompletion: time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)}
+	if err := client.ApplyUpdate(update); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.ApplyUpdate(p2.DateUpdate{IssueNum: 3, ClearDates: true, ClearReason: "closed"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0]["due_date"] != "2025-04-02" || bodies[1]["due_date"] != nil {
+		t.Errorf("expected due date to be written then cleared, got %v", bodies)
+	}
+}
@@ -7,6 +7,7 @@ require (
 	github.com/octoberswimmer/p2 v0.18.0
 	github.com/sirupsen/logrus v1.9.3
 	github.com/spf13/cobra v1.9.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 replace github.com/octoberswimmer/p2/static => ./stub/static
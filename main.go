@@ -1,19 +1,25 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"strings"
+	"syscall"
 	"time"
 	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 	p2license "github.com/octoberswimmer/p2/license"
 	"github.com/octoberswimmer/p2-github-scheduler/ghscheduler"
+	"github.com/octoberswimmer/p2-github-scheduler/gitlab"
 	"github.com/octoberswimmer/p2-github-scheduler/p2"
 	"github.com/octoberswimmer/p2/github"
 	"github.com/octoberswimmer/p2/planner"
@@ -22,13 +28,99 @@ import (
 )
 
 var (
-	debug  bool
-	dryRun bool
+	debug           bool
+	dryRun          bool
+	dryRunDiff      bool
+	dependencyLabel string
+	skipUnassigned  bool
+	usersFile       string
+	holidaysFile    string
+	cacheDir        string
+	cacheMaxAge     time.Duration
+	refresh         bool
+	tsvFile         string
+	exportCSV       string
+	stateFile       string
+	statusBehaviors map[string]string
+	onHoldStatuses  []string
+	emptyExitCode   int
+	depsFile        string
+	parseBodyDeps   bool
+	projectQuery    string
+	keepEstimates   bool
+	dedupeComments  bool
+	lowEstLabel     string
+	highEstLabel    string
+	trace           bool
+	estimateFactor  float64
+	writeScenario   bool
+	defaultEstFrac  float64
+	gist            bool
+	groupNoMilest   bool
+	review          bool
+	burndownFile    string
+	utilizationFile string
+	onlyRepos       []string
+	onlyIssues      []string
+	maxUpdates      int
+	dependencyLag   int
+	sortUpdates     bool
+	templateFile    string
+	splitThreshold  float64
+	explainPackages bool
+	atRiskWorkdays  bool
+	orderField      string
+	clearAll        bool
+	baselineFile    string
+	repoFilter      []string
+	writeBaseline   string
+	planStateFile   string
+	milestoneCaps   map[string]string
+	configFile      string
+	roundRobin      bool
+	defaultLow      float64
+	defaultHigh     float64
+	outputFormat    string
+	logFormat       string
+	fieldNameFlags  map[string]string
+	exportDeps      string
+	atRiskStatus    string
+	onTrackStatus   string
+	baseDate        string
+	assigneeFilter  string
+	exportICS       string
+	failOnIssues    []string
+	noComments      bool
+	annotationLevel map[string]string
+	partitionBy     string
+	commentReasons  []string
+
+	// progress receives everything a run prints other than its reports, which
+	// go to stdout; with --output json it is stderr
+	progress io.Writer = os.Stdout
 
 	// Function variables for testing
 	lookupProjectForIssue      = github.LookupProjectForIssue
 	fetchProjectItems          = github.FetchProjectItems
 	fetchRepoIssuesViaProjects = github.FetchRepoIssuesViaProjects
+	fetchTeamMembers           = ghscheduler.FetchTeamMembers
+	fetchFieldValues           = ghscheduler.FetchNumberFieldValues
+	fetchDateValues            = ghscheduler.FetchDateFieldValues
+	fetchSelectValues          = ghscheduler.FetchSingleSelectFieldValues
+	fetchClosingIssues         = ghscheduler.FetchClosingIssues
+	fetchAssignees             = ghscheduler.FetchAssignees
+	fetchPrivateRepos          = ghscheduler.FetchPrivateRepos
+	fetchMatchingItems         = ghscheduler.FetchMatchingItemIDs
+	fetchProjectFields         = ghscheduler.FetchProjectFields
+	createGist                 = ghscheduler.CreateGist
+	detectFeatures             = ghscheduler.DetectFeatures
+	checkProjectAccess         = ghscheduler.CheckProjectWriteAccess
+	newStatusWriter            = func(accessToken string) ghscheduler.StatusFieldWriter {
+		return ghscheduler.GraphQLFieldWriter{Token: accessToken}
+	}
+	newFieldWriter = func(accessToken string) ghscheduler.DateFieldClient {
+		return ghscheduler.GraphQLFieldWriter{Token: accessToken}
+	}
 
 	rootCmd = &cobra.Command{
 		Use:   "p2-github-scheduler <github-url>",
@@ -45,268 +137,1698 @@ Accepts:
 		Args: cobra.ExactArgs(1),
 		RunE: run,
 	}
-)
-
-func init() {
-	// Load .env file if present (same as p2)
-	godotenv.Load()
-
-	rootCmd.Flags().BoolVar(&debug, "debug", false, "Enable debug logging")
-	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be updated without making changes")
+)
+
+func init() {
+	// Load .env file if present (same as p2)
+	godotenv.Load()
+
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Log format: text, or json for one JSON object per line with owner, repo, and issue fields")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", ".p2scheduler.yml", "Config file with project field names and flag defaults (ignored if missing)")
+	rootCmd.PersistentFlags().StringToStringVar(&fieldNameFlags, "field-name", nil, "Project field names by role, overriding --config (e.g. low-estimate=\"Est. Low\",expected-start=\"Forecast Start\")")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be updated without making changes")
+	rootCmd.Flags().BoolVar(&dryRunDiff, "dry-run-diff", false, "Like --dry-run, but show each changed date as old -> new")
+	rootCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Directory to cache fetched issues in")
+	rootCmd.Flags().DurationVar(&cacheMaxAge, "cache-max-age", 24*time.Hour, "Refuse to use cached issues older than this (0 disables)")
+	rootCmd.Flags().BoolVar(&refresh, "refresh", false, "Ignore cached issues and fetch from GitHub")
+	rootCmd.Flags().StringVar(&tsvFile, "tsv", "", "Write the schedule as tab-separated values to this file (- for stdout)")
+	rootCmd.Flags().StringVar(&exportCSV, "export-csv", "", "Write the schedule as comma-separated values to this file (- for stdout)")
+	rootCmd.Flags().StringVar(&stateFile, "state-file", "", "JSON file recording the last run, used to skip runs whose inputs are unchanged")
+	rootCmd.Flags().IntVar(&emptyExitCode, "empty-exit-code", 0, "Exit code to use when there is nothing to schedule or no date changes")
+	rootCmd.Flags().StringSliceVar(&onHoldStatuses, "on-hold-status", p2.DefaultOnHoldStatuses, "Scheduling Status values that mean on hold (e.g. Parked,Paused)")
+	rootCmd.Flags().StringToStringVar(&statusBehaviors, "status-behavior", nil, "Map Scheduling Status values to hold, schedule, require-estimate, or freeze (e.g. Blocked=hold)")
+	rootCmd.Flags().StringVar(&usersFile, "users-file", "", "YAML/JSON file with per-user and per-team working hours")
+	rootCmd.Flags().StringVar(&holidaysFile, "holidays", "", "CSV file of dates (and optional users) with no availability")
+	rootCmd.Flags().BoolVar(&roundRobin, "round-robin-assignees", false, "Schedule issues with several assignees against each of them in turn")
+	rootCmd.Flags().BoolVar(&skipUnassigned, "skip-unassigned", false, "Don't schedule issues without an assignee")
+	rootCmd.Flags().Float64Var(&estimateFactor, "estimate-multiplier", 1, "Scale all estimates by this factor for what-if planning (implies --dry-run unless --write-scenario)")
+	rootCmd.Flags().BoolVar(&writeScenario, "write-scenario", false, "Write dates computed with --estimate-multiplier back to GitHub")
+	rootCmd.Flags().Float64Var(&defaultLow, "default-low", p2.DefaultEstimateLow, "Low Estimate assumed for open issues with no estimates")
+	rootCmd.Flags().Float64Var(&defaultHigh, "default-high", p2.DefaultEstimateHigh, "High Estimate assumed for open issues with no estimates")
+	rootCmd.Flags().Float64Var(&defaultEstFrac, "default-estimate-threshold", p2.DefaultEstimateWarningThreshold, "Warn when more than this fraction of open issues have the default estimate")
+	rootCmd.Flags().BoolVar(&gist, "gist", false, "Upload the schedule as Markdown to a secret gist and print its URL")
+	rootCmd.Flags().StringToStringVar(&milestoneCaps, "milestone-capacity", nil, "Warn when a milestone's open work (summed High Estimates) exceeds this many person-days (e.g. v1.0=10)")
+	rootCmd.Flags().BoolVar(&groupNoMilest, "group-unmilestoned", false, "Group issues without a milestone into a synthetic \"(no milestone)\" package")
+	rootCmd.Flags().BoolVar(&review, "review", false, "Interactively accept or skip each date update before applying")
+	rootCmd.Flags().StringVar(&baseDate, "base-date", "", "Schedule from this date (YYYY-MM-DD) instead of now")
+	rootCmd.Flags().StringVar(&atRiskStatus, "at-risk-status", "", "Set the Scheduling Status of at-risk issues to this option (e.g. \"At Risk\"), if the project has it")
+	rootCmd.Flags().StringVar(&onTrackStatus, "on-track-status", "", "Scheduling Status option to restore when an issue is no longer at risk (default clears the field)")
+	rootCmd.Flags().StringVar(&exportICS, "export-ics", "", "Write expected start-to-completion spans as an iCalendar feed to this file (- for stdout)")
+	rootCmd.Flags().StringVar(&exportDeps, "export-deps", "", "Write the dependency graph as Graphviz DOT to this file (- for stdout)")
+	rootCmd.Flags().StringVar(&utilizationFile, "utilization", "", "Write per-user weekly scheduled vs available hours as CSV to this file (- for stdout)")
+	rootCmd.Flags().StringVar(&burndownFile, "burndown", "", "Write the weekly cumulative completion forecast to this file (.json for JSON, otherwise CSV; - for stdout)")
+	rootCmd.Flags().StringVar(&assigneeFilter, "assignee", "", "Preview the queue of issues assigned to this login, with the issues blocking them (implies --dry-run)")
+	rootCmd.Flags().StringSliceVar(&failOnIssues, "fail-on-issues", nil, "Exit non-zero when scheduling issues with these reasons are found (cycle,missing_dependency,...; bare flag means all blocking reasons)")
+	rootCmd.Flags().Lookup("fail-on-issues").NoOptDefVal = ghscheduler.SeverityBlocking
+	rootCmd.Flags().StringVar(&partitionBy, "partition-by", "", "Schedule groups of issues independently: team, by the project's Team single-select field")
+	rootCmd.Flags().StringToStringVar(&annotationLevel, "annotation-level", nil, "In GitHub Actions, annotate scheduling issues with these reasons at this level: error, warning, or notice (e.g. at_risk=notice; default error for blocking reasons, warning otherwise)")
+	rootCmd.Flags().BoolVar(&noComments, "no-comments", false, "Don't post, update or delete scheduling comments")
+	rootCmd.Flags().StringSliceVar(&commentReasons, "comment-reasons", nil, "Only post scheduling comments for these reasons (cycle,missing_estimate,...; blocking or warning selects by severity)")
+	rootCmd.Flags().StringVar(&projectQuery, "project-query", "", "Only schedule project items matching this filter (e.g. \"is:open label:backend\"); dependencies on other items are reported as missing")
+	rootCmd.Flags().StringSliceVar(&repoFilter, "repo-filter", nil, "Only schedule issues in these repositories (owner/repo,...); dependencies on other repos are reported as missing")
+	rootCmd.Flags().StringSliceVar(&onlyRepos, "only-repos", nil, "Only write dates and comments to these repositories (owner/repo,...); all repos are still used for scheduling")
+	rootCmd.Flags().StringSliceVar(&onlyIssues, "only-issues", nil, "Only write dates to these issues (owner/repo#N,...); the others are listed in the plan but skipped")
+	rootCmd.Flags().IntVar(&maxUpdates, "max-updates", 0, "Abort without writing if there are more than this many date updates (0 for no limit)")
+	rootCmd.Flags().IntVar(&dependencyLag, "dependency-lag", 0, "Working days between a blocker's Expected Completion and its dependents' start (override per issue with a \"lag:N\" label)")
+	rootCmd.Flags().BoolVar(&sortUpdates, "sort-updates", true, "Apply updates in owner, repo, issue number order")
+	rootCmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text, or json to print planned updates and scheduling issues as JSON on stdout (progress goes to stderr)")
+	rootCmd.Flags().StringVar(&templateFile, "template", "", "Render the run result with this Go text/template file to stdout (not with --output json)")
+	rootCmd.Flags().Float64Var(&splitThreshold, "split-threshold", 0, "Schedule tasks with a High Estimate above this as sequential parts no larger than it (0 disables)")
+	rootCmd.Flags().BoolVar(&explainPackages, "explain-packages", false, "Print each milestone package with the attributes that determined its scheduling order")
+	rootCmd.Flags().BoolVar(&atRiskWorkdays, "at-risk-working-days", false, "Treat weekend due dates and completions as the following Monday when detecting at-risk issues")
+	rootCmd.Flags().StringVar(&baselineFile, "baseline", "", "Report issues whose Expected Completion slipped past this committed baseline schedule")
+	rootCmd.Flags().StringVar(&writeBaseline, "write-baseline", "", "Write the full current schedule to this file for use with --baseline")
+	rootCmd.Flags().StringVar(&planStateFile, "plan-state", "", "JSON file with each project's last plan; the summary reports Expected Completions that moved since it, and it is updated with this run's plan")
+	rootCmd.Flags().BoolVar(&keepEstimates, "keep-estimates-on-close", false, "Only clear the dates of closed issues, keeping their Low and High Estimates")
+	rootCmd.Flags().BoolVar(&clearAll, "clear-all", false, "Clear scheduling dates and estimates from every issue instead of scheduling (e.g. when archiving a project)")
+	rootCmd.Flags().BoolVar(&dedupeComments, "dedupe-comments", false, "Delete all but the newest scheduling comment on each commented issue (cleans up after earlier versions)")
+	rootCmd.Flags().StringVar(&orderField, "order-field", "", "Numeric project field to order issues by instead of their project position (e.g. Rank)")
+	rootCmd.Flags().BoolVar(&trace, "trace", false, "Print one compact line per issue describing how it was converted")
+	rootCmd.Flags().StringVar(&depsFile, "deps", "", "YAML/JSON file of extra blocker -> blocked dependencies")
+	rootCmd.Flags().BoolVar(&parseBodyDeps, "parse-body-deps", false, "Add dependencies written in issue bodies as \"Depends on #N\" or \"Blocked by owner/repo#N\"")
+	rootCmd.Flags().StringVar(&lowEstLabel, "low-estimate-label", "", "Regexp matching labels that give the Low Estimate when the field is unset (named group: hours, e.g. '^est-low:(?P<hours>[0-9.]+[hd]?)$')")
+	rootCmd.Flags().StringVar(&highEstLabel, "high-estimate-label", "", "Regexp matching labels that give the High Estimate when the field is unset (named group: hours)")
+	rootCmd.Flags().StringVar(&dependencyLabel, "dependency-label", "", "Regexp matching labels that declare blocking relationships (named groups: owner, repo, num)")
+}
+
+func main() {
+	// Pace every GitHub request, including the p2 library's, by the remaining rate limit
+	http.DefaultTransport = ghscheduler.NewRateLimiter(http.DefaultTransport)
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitError is returned by run to exit with a specific code without
+// reporting an error
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// exitCode returns the process exit code for an error returned by run
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var ee *exitError
+	if errors.As(err, &ee) {
+		return ee.code
+	}
+	return 1
+}
+
+// checkMaxUpdates fails a run whose n date updates exceed --max-updates
+func checkMaxUpdates(n int) error {
+	if maxUpdates > 0 && n > maxUpdates {
+		return fmt.Errorf("refusing to write %d date updates: more than --max-updates %d; check the plan above and re-run with a higher --max-updates or --dry-run", n, maxUpdates)
+	}
+	return nil
+}
+
+// configureLogging sets the log level from --debug and the formatter from --log-format
+func configureLogging() error {
+	switch logFormat {
+	case "text":
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	case "json":
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("--log-format must be text or json, got %q", logFormat)
+	}
+	if debug {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.WarnLevel)
+	}
+	return nil
+}
+
+// emptyResult is returned when a run has nothing to schedule or change.
+// It returns nil unless --empty-exit-code is set.
+func emptyResult(cmd *cobra.Command) error {
+	if emptyExitCode == 0 {
+		return nil
+	}
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &exitError{code: emptyExitCode}
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	if err := applyConfigFile(cmd); err != nil {
+		return err
+	}
+	if err := configureLogging(); err != nil {
+		return err
+	}
+
+	if estimateFactor <= 0 {
+		return fmt.Errorf("--estimate-multiplier must be positive, got %g", estimateFactor)
+	}
+	if defaultLow <= 0 || defaultHigh < defaultLow {
+		return fmt.Errorf("--default-high (%g) must be at least --default-low (%g), and both positive", defaultHigh, defaultLow)
+	}
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("--output must be text or json, got %q", outputFormat)
+	}
+	if outputFormat == "json" && templateFile != "" {
+		return fmt.Errorf("--template and --output json both write to stdout; use one of them")
+	}
+	base := time.Now()
+	if baseDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", baseDate, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --base-date %q: expected YYYY-MM-DD", baseDate)
+		}
+		base = parsed
+	}
+	// With JSON output, stdout carries only the report; progress moves to stderr
+	progress = os.Stdout
+	if outputFormat == "json" {
+		progress = os.Stderr
+	}
+	// What-if scenarios don't write to GitHub unless explicitly requested, and
+	// single-assignee views never do: others' issues would lose their dates
+	noWrite := dryRun || dryRunDiff || (estimateFactor != 1 && !writeScenario) || assigneeFilter != ""
+	if noWrite {
+		fmt.Fprintf(progress, "Dry run scheduling from %s\n", base.Format("2006-01-02"))
+	}
+
+	url := ghscheduler.NormalizeURL(args[0])
+
+	// URLs on other hosts are GitLab projects
+	if baseURL, project, ok := gitlab.ParseProjectURL(url); ok {
+		client := &gitlab.Client{BaseURL: baseURL, Project: project, Token: os.Getenv("GITLAB_TOKEN")}
+		return runBackend(cmd, client, base, noWrite)
+	}
+
+	fieldNames, err := loadFieldNames()
+	if err != nil {
+		return err
+	}
+
+	// Authenticate with GitHub
+	accessToken, err := authenticate()
+	if err != nil {
+		return err
+	}
+
+	if debug {
+		logInstallationRepos(accessToken)
+	}
+
+	// Parse the URL to determine what we're working with. Pull request URLs
+	// schedule the projects of the issues the pull request closes.
+	var urlInfo *github.URLInfo
+	pr, isPullRequest := ghscheduler.ParsePullRequestURL(url)
+	if isPullRequest {
+		urlInfo = &github.URLInfo{Owner: pr.Owner, Repo: pr.Repo}
+	} else {
+		urlInfo, err = github.ParseGitHubURL(url)
+		if err != nil {
+			return fmt.Errorf("invalid GitHub URL: %w", err)
+		}
+	}
+
+	var timings p2.Timings
+	fetchStart := time.Now()
+	var allIssues map[string]github.IssueWithProject
+	fromCache := false
+
+	if cacheDir != "" && !refresh {
+		cached, fetchedAt, err := p2.LoadIssueCache(cacheDir, url, cacheMaxAge, time.Now())
+		if err == nil {
+			fmt.Fprintf(progress, "Using cached issues fetched %s\n", fetchedAt.Format(time.RFC3339))
+			allIssues = cached
+			fromCache = true
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	if !fromCache {
+		if isPullRequest {
+			allIssues, err = fetchPullRequestIssues(accessToken, pr)
+		} else {
+			allIssues, err = fetchIssues(accessToken, urlInfo)
+		}
+		if err != nil {
+			return err
+		}
+		if err := readRenamedFields(accessToken, allIssues, fieldNames); err != nil {
+			return err
+		}
+		if err := markPrivateIssues(accessToken, allIssues); err != nil {
+			return err
+		}
+		if cacheDir != "" && len(allIssues) > 0 {
+			if err := p2.SaveIssueCache(cacheDir, url, allIssues, time.Now()); err != nil {
+				logrus.Warnf("Failed to write cache: %v", err)
+			}
+		}
+	}
+
+	timings.Fetch = time.Since(fetchStart)
+
+	if projectQuery != "" {
+		excluded, err := filterProjectQuery(accessToken, allIssues, projectQuery)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(progress, "Excluded %d items not matching --project-query\n", excluded)
+	}
+	repoScope, err := p2.ParseRepoAllowlist(repoFilter)
+	if err != nil {
+		return err
+	}
+	if excluded := repoScope.FilterIssues(allIssues); excluded > 0 {
+		fmt.Fprintf(progress, "Excluded %d items outside --repo-filter\n", excluded)
+	}
+	var assignees map[string][]string
+	if assigneeFilter != "" || roundRobin {
+		assignees, err = issueAssignees(accessToken, allIssues)
+		if err != nil {
+			return err
+		}
+	}
+	if assigneeFilter != "" {
+		excluded, blockers := p2.FilterAssignee(allIssues, assigneeFilter, assignees)
+		fmt.Fprintf(progress, "Single-assignee view for %s: excluded %d items assigned to others, kept %d blockers\n", assigneeFilter, excluded, blockers)
+	}
+
+	if len(allIssues) == 0 {
+		fmt.Fprintln(progress, "No issues to schedule")
+		return emptyResult(cmd)
+	}
+
+	// Order issues by a project field instead of their position
+	if orderField != "" {
+		var itemIDs []string
+		for _, iwp := range allIssues {
+			if iwp.Project != nil {
+				itemIDs = append(itemIDs, iwp.Project.ItemID)
+			}
+		}
+		values, err := fetchFieldValues(accessToken, itemIDs, orderField)
+		if err != nil {
+			return fmt.Errorf("failed to read %s field: %w", orderField, err)
+		}
+		p2.ApplyFieldOrder(allIssues, values)
+	}
+
+	// Read start-date constraints from projects with an Earliest Start field
+	earliestStarts, err := readEarliestStarts(accessToken, allIssues)
+	if err != nil {
+		return err
+	}
+
+	// Read each issue's team to schedule teams independently
+	var teamOf map[string]string
+	if partitionBy == "team" {
+		teams, err := readTeams(accessToken, allIssues)
+		if err != nil {
+			return err
+		}
+		teamOf = p2.TaskTeams(allIssues, teams)
+	}
+
+	// Fill in estimates from labels where the project fields are unset.
+	// --clear-all must only see real field values.
+	if (lowEstLabel != "" || highEstLabel != "") && !clearAll {
+		n, err := p2.ApplyEstimateLabels(allIssues, lowEstLabel, highEstLabel)
+		if err != nil {
+			return err
+		}
+		logrus.Debugf("Read estimates from labels for %d issues", n)
+	}
+
+	// Add blocking relationships declared via labels
+	if dependencyLabel != "" {
+		if err := p2.ApplyDependencyLabels(allIssues, dependencyLabel); err != nil {
+			return err
+		}
+	}
+
+	// Add dependencies written in issue bodies
+	if parseBodyDeps {
+		if err := p2.ApplyDependencyEdges(allIssues, p2.BodyDependencyEdges(allIssues)); err != nil {
+			return err
+		}
+	}
+
+	// Add dependencies declared in a file
+	var fileEdges []p2.DependencyEdge
+	if depsFile != "" {
+		fileEdges, err = p2.LoadDependencyFile(depsFile)
+		if err != nil {
+			return err
+		}
+		if err := p2.ApplyDependencyEdges(allIssues, fileEdges); err != nil {
+			return err
+		}
+	}
+
+	// Determine current repo for privacy filtering
+	currentRepo := os.Getenv("GITHUB_REPOSITORY")
+	if currentRepo == "" && urlInfo.Repo != "" {
+		currentRepo = urlInfo.Owner + "/" + urlInfo.Repo
+	}
+	privacy := p2.NewPrivacyFilter(currentRepo, allIssues)
+
+	// Load working hours, resolving team defaults from GitHub team membership
+	var availability *p2.Availability
+	if usersFile != "" {
+		availability, err = p2.LoadAvailability(usersFile)
+		if err != nil {
+			return err
+		}
+		loadTeamMembers(accessToken, urlInfo.Owner, availability)
+	}
+	if holidaysFile != "" {
+		holidays, err := p2.LoadHolidays(holidaysFile)
+		if err != nil {
+			return err
+		}
+		if availability == nil {
+			availability = &p2.Availability{}
+		}
+		availability.Holidays = append(availability.Holidays, holidays...)
+	}
+
+	behaviors, err := p2.ParseStatusBehaviors(statusBehaviors)
+	if err != nil {
+		return err
+	}
+	repoAllowlist, err := p2.ParseRepoAllowlist(onlyRepos)
+	if err != nil {
+		return err
+	}
+	capacities, err := p2.ParseMilestoneCapacities(milestoneCaps)
+	if err != nil {
+		return err
+	}
+	annotationLevels, err := ghscheduler.ParseAnnotationLevels(annotationLevel)
+	if err != nil {
+		return err
+	}
+	if partitionBy != "" && partitionBy != "team" {
+		return fmt.Errorf("invalid --partition-by %q: expected team", partitionBy)
+	}
+	issueAllowlist, err := p2.ParseIssueAllowlist(onlyIssues)
+	if err != nil {
+		return err
+	}
+
+	convertOpts := p2.ConvertOptions{
+		SkipUnassigned:       skipUnassigned,
+		Availability:         availability,
+		StatusBehaviors:      behaviors,
+		OnHoldStatuses:       onHoldStatuses,
+		EstimateMultiplier:   estimateFactor,
+		GroupUnmilestoned:    groupNoMilest,
+		Assignees:            assignees,
+		RoundRobinAssignees:  roundRobin,
+		DefaultEstimateLow:   defaultLow,
+		DefaultEstimateHigh:  defaultHigh,
+		KeepEstimatesOnClose: keepEstimates,
+		Privacy:              privacy,
+	}
+
+	if clearAll {
+		// Only clear what a scheduling run with the same flags could write
+		updates := p2.ClearAllUpdatesWithOptions(allIssues, convertOpts)
+		updates = issueAllowlist.FilterUpdates(repoAllowlist.FilterUpdates(updates))
+		return runClearAll(accessToken, updates, allIssues, privacy, fieldNames, noWrite)
+	}
+
+	// Skip the run entirely if nothing relevant changed since the last one:
+	// neither the issues nor anything else that shapes the schedule or what
+	// is written
+	settings := struct {
+		Convert         p2.ConvertOptions
+		FieldNames      p2.FieldNames
+		EarliestStarts  map[string]time.Time
+		Teams           map[string]string
+		Lags            map[string]int
+		SplitThreshold  float64
+		DependencyLabel string
+		DependencyFile  []p2.DependencyEdge
+		BodyDeps        bool
+		LowEstLabel     string
+		HighEstLabel    string
+		Capacities      map[string]float64
+		OnlyRepos       p2.RepoAllowlist
+		OnlyIssues      p2.IssueAllowlist
+		AtRiskStatus    string
+		OnTrackStatus   string
+		AtRiskWorkdays  bool
+		NoComments      bool
+		CommentReasons  []string
+	}{
+		Convert:         convertOpts,
+		FieldNames:      fieldNames,
+		EarliestStarts:  earliestStarts,
+		Teams:           teamOf,
+		Lags:            p2.DependencyLags(allIssues, dependencyLag),
+		SplitThreshold:  splitThreshold,
+		DependencyLabel: dependencyLabel,
+		DependencyFile:  fileEdges,
+		BodyDeps:        parseBodyDeps,
+		LowEstLabel:     lowEstLabel,
+		HighEstLabel:    highEstLabel,
+		Capacities:      capacities,
+		OnlyRepos:       repoAllowlist,
+		OnlyIssues:      issueAllowlist,
+		AtRiskStatus:    atRiskStatus,
+		OnTrackStatus:   onTrackStatus,
+		AtRiskWorkdays:  atRiskWorkdays,
+		NoComments:      noComments,
+		CommentReasons:  commentReasons,
+	}
+	inputHash, err := p2.InputHash(allIssues, base, settings)
+	if err != nil {
+		return err
+	}
+	var state *p2.RunState
+	if stateFile != "" {
+		state, err = p2.LoadRunState(stateFile)
+		if err != nil {
+			return err
+		}
+		if state.InputHash == inputHash {
+			fmt.Fprintln(progress, "No input changes since last run, skipping")
+			if outputFormat == "json" {
+				if err := p2.WriteResultJSON(os.Stdout, p2.NewResult(planner.GanttData{}, nil, nil, timings, privacy)); err != nil {
+					return err
+				}
+			}
+			return emptyResult(cmd)
+		}
+	}
+	var schedIssueKeys []string
+	saveState := func() {
+		if state == nil || noWrite {
+			return
+		}
+		state.InputHash = inputHash
+		state.SchedulingIssues = schedIssueKeys
+		if err := p2.SaveRunState(stateFile, state); err != nil {
+			logrus.Warnf("Failed to save state: %v", err)
+		}
+	}
+
+	// Convert issues to p2 tasks
+	fmt.Fprintln(progress, "Converting to p2 tasks...")
+	tasks, users, schedIssues := p2.IssuesToTasksWithOptions(allIssues, privacy, convertOpts)
+	fmt.Fprintf(progress, "Created %d tasks with %d users\n", len(tasks), len(users))
+
+	if msg := p2.DefaultEstimateWarningFor(allIssues, defaultEstFrac, defaultLow, defaultHigh); msg != "" {
+		fmt.Fprintf(progress, "Warning: %s\n", msg)
+	}
+
+	if explainPackages {
+		fmt.Fprintln(progress, "Package order:")
+		if err := p2.WritePackageExplanations(progress, p2.ExplainPackages(allIssues, convertOpts)); err != nil {
+			return fmt.Errorf("failed to write package explanation: %w", err)
+		}
+	}
+
+	if trace {
+		if err := p2.WriteTrace(os.Stderr, tasks, privacy); err != nil {
+			return fmt.Errorf("failed to write trace: %w", err)
+		}
+	}
+
+	if len(tasks) == 0 {
+		fmt.Fprintln(progress, "No tasks to schedule")
+		saveState()
+		return emptyResult(cmd)
+	}
+
+	// Run the scheduler
+	fmt.Fprintln(progress, "Running scheduler...")
+	scheduleStart := time.Now()
+	for _, t := range tasks {
+		if len(t.DependsOn) > 0 {
+			logrus.WithField("task", t.ID).Debugf("Task (user=%q, done=%v, onhold=%v) depends on: %v", t.User, t.Done, t.OnHold, t.DependsOn)
+		}
+	}
+	// With --partition-by, each team is scheduled on its own
+	partitions := []p2.TaskPartition{{Tasks: tasks}}
+	if teamOf != nil {
+		// Cycles that cross teams only show up in the unpartitioned graph
+		entries := planner.ScheduleWithUsers(p2.SplitLargeTasks(tasks, splitThreshold), users)
+		schedIssues = p2.ExtractCycleIssues(entries, allIssues, schedIssues)
+
+		partitions = p2.PartitionTasks(tasks, teamOf)
+		for _, shared := range p2.SharedAssignees(partitions) {
+			logrus.WithField("user", shared.User).Warnf("Assignee has work in teams %s; each team's schedule books them full time", strings.Join(shared.Teams, ", "))
+		}
+	}
+	var parts []planner.GanttData
+	for _, part := range partitions {
+		// Large tasks are scheduled as sequential parts, then merged back per issue
+		schedTasks := p2.SplitLargeTasks(part.Tasks, splitThreshold)
+		entries := planner.ScheduleWithUsers(schedTasks, users)
+
+		// Extract cycle information from scheduler results
+		schedIssues = p2.ExtractCycleIssues(entries, allIssues, schedIssues)
+
+		partGantt, err := planner.ComputeGanttData(entries, schedTasks, true, base, users)
+		if err != nil {
+			return fmt.Errorf("scheduling failed: %w", err)
+		}
+		parts = append(parts, partGantt)
+	}
+
+	if exportDeps != "" {
+		if err := writeOutput(exportDeps, func(w io.Writer) error {
+			return p2.WriteDependencyDOTWithOptions(w, allIssues, schedIssues, privacy, convertOpts)
+		}); err != nil {
+			return fmt.Errorf("failed to write dependency graph: %w", err)
+		}
+	}
+
+	ganttData := p2.MergeGanttData(parts)
+	p2.MergeSplitBars(&ganttData)
+	delayed := p2.ApplyEarliestStarts(&ganttData, tasks, earliestStarts)
+	if teamOf != nil {
+		// Dependents of other teams' work start once it is done
+		p2.ApplyEarliestStarts(&ganttData, tasks, p2.CrossPartitionStarts(ganttData, tasks, teamOf))
+	}
+	p2.ApplyRamps(&ganttData, tasks, availability)
+	p2.ApplyDependencyLag(&ganttData, tasks, p2.DependencyLags(allIssues, dependencyLag))
+	timings.Schedule = time.Since(scheduleStart)
+
+	if tsvFile != "" {
+		rows := p2.ScheduleRowsWithOptions(ganttData, allIssues, privacy, convertOpts)
+		if err := writeOutput(tsvFile, func(w io.Writer) error { return p2.WriteTSV(w, rows) }); err != nil {
+			return fmt.Errorf("failed to write TSV: %w", err)
+		}
+	}
+
+	if exportCSV != "" {
+		rows := p2.ScheduleRowsWithOptions(ganttData, allIssues, privacy, convertOpts)
+		if err := writeOutput(exportCSV, func(w io.Writer) error { return p2.WriteCSV(w, rows) }); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	if gist {
+		var report bytes.Buffer
+		if err := p2.WriteMarkdown(&report, p2.ScheduleRowsWithOptions(ganttData, allIssues, privacy, convertOpts)); err != nil {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
+		description := fmt.Sprintf("Schedule for %s", url)
+		gistURL, err := createGist(accessToken, description, map[string]string{"schedule.md": report.String()})
+		if err != nil {
+			return fmt.Errorf("failed to create gist: %w", err)
+		}
+		fmt.Fprintf(progress, "Schedule uploaded to %s\n", gistURL)
+	}
+
+	// Build set of issues with scheduling problems
+	unschedulableIssues := make(map[string]bool)
+	for _, si := range schedIssues {
+		unschedulableIssues[si.IssueRef] = true
+	}
+
+	// Prepare updates
+	updates := p2.PrepareUpdatesWithOptions(ganttData, allIssues, unschedulableIssues, convertOpts)
+	if sortUpdates {
+		p2.SortUpdates(updates)
+	}
+	// updates only hold the issues whose dates changed; schedule has them all
+	schedule := p2.CurrentSchedule(ganttData, allIssues)
+
+	if baselineFile != "" || writeBaseline != "" {
+		if writeBaseline != "" {
+			if err := p2.SaveBaseline(writeBaseline, schedule); err != nil {
+				return err
+			}
+		}
+		if baselineFile != "" {
+			baseline, err := p2.LoadBaseline(baselineFile)
+			if err != nil {
+				return err
+			}
+			printSlips(p2.CompareBaseline(baseline, schedule), privacy)
+		}
+	}
+
+	// Compare with this project's plan from the previous run, then replace it
+	// unless nothing is written
+	var drifts []p2.Slip
+	hasPreviousPlan := false
+	if planStateFile != "" {
+		plans, err := p2.LoadPlanState(planStateFile)
+		if err != nil {
+			return err
+		}
+		var previous []p2.DateUpdate
+		previous, hasPreviousPlan = plans[url]
+		drifts = p2.CompareBaselineMoves(previous, schedule)
+		if !noWrite {
+			plans[url] = schedule
+			if err := p2.SavePlanState(planStateFile, plans); err != nil {
+				return err
+			}
+		}
+	}
+
+	if utilizationFile != "" {
+		rows := p2.Utilization(ganttData, tasks, users)
+		if err := writeOutput(utilizationFile, func(w io.Writer) error { return p2.WriteUtilizationCSV(w, rows) }); err != nil {
+			return fmt.Errorf("failed to write utilization: %w", err)
+		}
+	}
+
+	if burndownFile != "" {
+		// Every open issue's forecast, not just those whose dates changed
+		points := p2.Burndown(schedule)
+		write := func(w io.Writer) error { return p2.WriteBurndownCSV(w, points) }
+		if strings.HasSuffix(burndownFile, ".json") {
+			write = func(w io.Writer) error { return p2.WriteBurndownJSON(w, points) }
+		}
+		if err := writeOutput(burndownFile, write); err != nil {
+			return fmt.Errorf("failed to write burndown: %w", err)
+		}
+	}
+
+	if exportICS != "" {
+		if err := writeOutput(exportICS, func(w io.Writer) error {
+			return p2.WriteICS(w, schedule, privacy, time.Now())
+		}); err != nil {
+			return fmt.Errorf("failed to write calendar: %w", err)
+		}
+	}
+
+	// Detect at-risk issues (expected completion after due date) across the
+	// whole schedule, so their status stays set on runs that move no dates
+	atRiskIssues := p2.DetectAtRiskIssuesWithOptions(schedule, allIssues, p2.AtRiskOptions{WorkingDays: atRiskWorkdays})
+	schedIssues = append(schedIssues, atRiskIssues...)
+	// Overcommitted milestones are likewise reported whether or not dates moved
+	schedIssues = append(schedIssues, p2.DetectPackageOvercommit(schedule, allIssues)...)
+	schedIssues = append(schedIssues, p2.DetectUnknownAssignees(allIssues, availability)...)
+	schedIssues = append(schedIssues, p2.DetectMilestoneCapacity(tasks, allIssues, capacities)...)
+	schedIssues = p2.ReconcileSignals(schedIssues)
+
+	// Limit writes and comments to allowed repositories
+	updates = repoAllowlist.FilterUpdates(updates)
+	schedIssues = repoAllowlist.FilterSchedulingIssues(schedIssues)
+
+	// Summarize how scheduling problems changed since the last run
+	schedIssueKeys = p2.SchedulingIssueKeys(schedIssues)
+	if state != nil {
+		if delta := p2.SchedulingIssueDelta(state.SchedulingIssues, schedIssueKeys); delta != "" {
+			fmt.Fprintf(os.Stderr, "Scheduling issues: %s\n", delta)
+		} else {
+			fmt.Fprintln(os.Stderr, "Scheduling issues: no change")
+		}
+	}
+
+	if outputFormat == "json" {
+		if err := p2.WriteResultJSON(os.Stdout, p2.NewResult(ganttData, updates, schedIssues, timings, privacy)); err != nil {
+			return err
+		}
+	}
+
+	// Build set of issues whose scheduling notices (including at-risk warnings)
+	// are commented on; any other issue's comment is stale
+	issuesWithNotices := make(map[string]bool)
+	for _, si := range schedIssues {
+		if wantsComment(si, noComments, commentReasons) {
+			issuesWithNotices[si.IssueRef] = true
+		}
+	}
+
+	// Print scheduling issues
+	if len(schedIssues) > 0 {
+		fmt.Fprintf(progress, "\nFound %d issues with scheduling problems:\n", len(schedIssues))
+		for _, si := range schedIssues {
+			fmt.Fprintf(progress, "  %s #%d: %s\n", privacy.RedactRepo(si.Owner, si.Repo), si.IssueNum, si.Reason)
+			for _, detail := range si.Details {
+				fmt.Fprintf(progress, "       - %s\n", privacy.RedactDepID(detail))
+			}
+		}
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			if err := ghscheduler.WriteAnnotations(progress, schedIssues, annotationLevels, privacy); err != nil {
+				return err
+			}
+		}
+	}
+
+	scheduled := 0
+	for _, t := range tasks {
+		if !t.Done && !t.OnHold {
+			scheduled++
+		}
+	}
+	// printSummary ends the run with its rollup, per-team lines and drift
+	printSummary := func(applied []p2.DateUpdate) error {
+		if err := p2.WriteSummary(progress, scheduled, delayed, applied, schedIssues, noWrite); err != nil {
+			return err
+		}
+		if teamOf != nil {
+			if err := p2.WriteTeamSummaries(progress, p2.TeamSummaries(ganttData, teamOf)); err != nil {
+				return err
+			}
+		}
+		if hasPreviousPlan {
+			printDrift(drifts, privacy)
+		}
+		return nil
+	}
+
+	if len(updates) == 0 && len(schedIssues) == 0 {
+		fmt.Fprintln(progress, "No date changes needed")
+		if !noWrite && atRiskStatus != "" {
+			// Issues that were at risk on the last run have recovered
+			writeAtRiskStatus(accessToken, allIssues, schedIssues, convertOpts, repoAllowlist, issueAllowlist, privacy)
+		}
+		if !noWrite && !noComments {
+			// Notices from earlier runs still need removing now that everything schedules
+			features := detectFeatures(accessToken, issueRepos(allIssues))
+			cleanupSchedulingComments(accessToken, allIssues, issuesWithNotices, convertOpts, repoAllowlist, features, privacy)
+		}
+		saveState()
+		if err := printSummary(nil); err != nil {
+			return err
+		}
+		return emptyResult(cmd)
+	}
+
+	if len(updates) > 0 {
+		fmt.Fprintf(progress, "\nFound %d tasks with date changes:\n", len(updates))
+		for _, u := range updates {
+			fmt.Fprintf(progress, "  %s #%d %s\n", privacy.RedactRepo(u.Owner, u.Repo), u.IssueNum, privacy.RedactTitle(u.Owner, u.Repo, u.Name))
+			if !issueAllowlist.Allows(u.Owner, u.Repo, u.IssueNum) {
+				fmt.Fprintln(progress, "       (skipped by --only-issues)")
+			}
+			if u.ClearDates {
+				if u.ClearReason == "closed" {
+					fmt.Fprintln(progress, "       (clearing dates and estimates - task is closed)")
+				} else if u.ClearReason == "archived" {
+					fmt.Fprintln(progress, "       (clearing dates and estimates)")
+				} else if u.ClearReason == "unschedulable" {
+					fmt.Fprintln(progress, "       (clearing dates - has scheduling issues)")
+				} else {
+					fmt.Fprintf(progress, "       (clearing dates - task is %s)\n", u.ClearReason)
+				}
+			} else if !dryRunDiff {
+				if !u.ExpectedStart.IsZero() {
+					fmt.Fprintf(progress, "       Expected Start: %s\n", u.ExpectedStart.Format("2006-01-02"))
+				}
+				if !u.ExpectedCompletion.IsZero() {
+					fmt.Fprintf(progress, "       Expected Completion: %s\n", u.ExpectedCompletion.Format("2006-01-02"))
+				}
+				if !u.Completion98.IsZero() {
+					fmt.Fprintf(progress, "       98%% Completion: %s\n", u.Completion98.Format("2006-01-02"))
+				}
+			}
+			if dryRunDiff {
+				iwp := allIssues[fmt.Sprintf("github.com/%s/%s/issues/%d", u.Owner, u.Repo, u.IssueNum)]
+				for _, d := range p2.UpdateDiffs(iwp, u, fieldNames) {
+					fmt.Fprintf(progress, "       %s\n", d)
+				}
+			}
+		}
+	}
+
+	if templateFile != "" {
+		result := p2.NewResult(ganttData, updates, schedIssues, timings, privacy)
+		if err := p2.RenderTemplate(os.Stdout, templateFile, result); err != nil {
+			return err
+		}
+	}
+
+	privateCount, publicCount := p2license.CountIssuePrivacy(allIssues)
+
+	if noWrite {
+		fmt.Fprintln(progress, "\nDry run - no changes made")
+		if err := printSummary(updates); err != nil {
+			return err
+		}
+		return failingIssuesError(schedIssues, failOnIssues, privacy)
+	}
+
+	if err := p2license.EnforceSchedule(progress, len(tasks), privateCount, publicCount); err != nil {
+		return err
+	}
+
+	// Only the listed issues are written; the rest were shown in the plan
+	updates = issueAllowlist.FilterUpdates(updates)
+	if err := checkMaxUpdates(len(updates)); err != nil {
+		return err
+	}
+
+	// Fail before any mutation if the token can't write the project's fields
+	if len(updates) > 0 {
+		if err := checkProjectAccess(accessToken, projectIDs(updates)); err != nil {
+			return err
+		}
+	}
+
+	if review && len(updates) > 0 {
+		fmt.Fprintln(progress, "\nReview updates ([y]es, [n]o, [a]ll remaining, [q]uit and skip remaining):")
+		updates = p2.FilterUpdates(updates, reviewPrompt(os.Stdin, progress, allIssues, privacy))
+		fmt.Fprintf(progress, "Accepted %d updates\n", len(updates))
+	}
+
+	// Check token permissions once so features it can't perform are skipped up front
+	features := detectFeatures(accessToken, issueRepos(allIssues))
+	for _, d := range features.Disabled {
+		fmt.Fprintf(progress, "Notice: %s disabled for %s: %s\n", d.Feature, privacy.RedactRepo(d.Owner, d.Repo), d.Reason)
+	}
+
+	// Apply updates to GitHub
+	applied := applyUpdates(accessToken, updates, privacy, fieldNames, keepEstimates)
+
+	if atRiskStatus != "" {
+		writeAtRiskStatus(accessToken, allIssues, schedIssues, convertOpts, repoAllowlist, issueAllowlist, privacy)
+	}
+
+	// Post or update scheduling issue comments
+	if len(schedIssues) > 0 && !noComments {
+		fmt.Fprintln(progress, "\nUpdating scheduling comments...")
+		for _, si := range schedIssues {
+			if !features.CommentsEnabled(si.Owner, si.Repo) || !wantsComment(si, noComments, commentReasons) {
+				continue
+			}
+			client := github.NewClient(accessToken, &github.GitHubRepository{Owner: si.Owner, Name: si.Repo})
+			if dedupeComments {
+				if n, err := ghscheduler.CleanupDuplicateSchedulingComments(client, si.IssueNum); err != nil {
+					logrus.WithFields(privacy.LogFields(si.Owner, si.Repo, si.IssueNum)).Warnf("Failed to remove duplicate comments: %v", err)
+				} else if n > 0 {
+					fmt.Fprintf(progress, "  Removed %d duplicate comments on %s #%d\n", n, privacy.RedactRepo(si.Owner, si.Repo), si.IssueNum)
+				}
+			}
+			redactedSI := privacy.RedactSchedulingIssue(si)
+			if err := ghscheduler.PostOrUpdateSchedulingComment(client, redactedSI); err != nil {
+				logrus.WithFields(privacy.LogFields(si.Owner, si.Repo, si.IssueNum)).Warnf("Failed to post comment: %v", err)
+			} else {
+				fmt.Fprintf(progress, "  Posted comment on %s #%d\n", privacy.RedactRepo(si.Owner, si.Repo), si.IssueNum)
+			}
+		}
+	}
+
+	// Delete comments for issues that no longer have notices
+	if !noComments {
+		cleanupSchedulingComments(accessToken, allIssues, issuesWithNotices, convertOpts, repoAllowlist, features, privacy)
+	}
+
+	saveState()
+	fmt.Fprintln(progress, "Done!")
+	if err := printSummary(applied); err != nil {
+		return err
+	}
+	return failingIssuesError(schedIssues, failOnIssues, privacy)
+}
+
+// issueBackend is an issue tracker other than GitHub
+type issueBackend interface {
+	p2.IssueFetcher
+	p2.UpdateApplier
+}
+
+// runBackend schedules the issues of a non-GitHub backend. It supports the
+// scheduling flags (working hours, estimates, statuses, --base-date,
+// --fail-on-issues) but not GitHub features such as comments or caching.
+func runBackend(cmd *cobra.Command, backend issueBackend, base time.Time, noWrite bool) error {
+	fmt.Fprintln(progress, "Fetching issues...")
+	allIssues, err := backend.FetchIssues()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(progress, "Found %d issues\n", len(allIssues))
+	if len(allIssues) == 0 {
+		fmt.Fprintln(progress, "No issues to schedule")
+		return emptyResult(cmd)
+	}
+
+	var availability *p2.Availability
+	if usersFile != "" {
+		availability, err = p2.LoadAvailability(usersFile)
+		if err != nil {
+			return err
+		}
+	}
+	behaviors, err := p2.ParseStatusBehaviors(statusBehaviors)
+	if err != nil {
+		return err
+	}
+	convertOpts := p2.ConvertOptions{
+		SkipUnassigned:       skipUnassigned,
+		Availability:         availability,
+		StatusBehaviors:      behaviors,
+		OnHoldStatuses:       onHoldStatuses,
+		EstimateMultiplier:   estimateFactor,
+		GroupUnmilestoned:    groupNoMilest,
+		DefaultEstimateLow:   defaultLow,
+		DefaultEstimateHigh:  defaultHigh,
+		KeepEstimatesOnClose: keepEstimates,
+	}
+	// Other trackers don't report visibility, so nothing is redacted
+	privacy := p2.NewPrivacyFilter("", allIssues)
+	tasks, users, schedIssues := p2.IssuesToTasksWithOptions(allIssues, privacy, convertOpts)
+	if len(tasks) == 0 {
+		fmt.Fprintln(progress, "No tasks to schedule")
+		return emptyResult(cmd)
+	}
+
+	fmt.Fprintln(progress, "Running scheduler...")
+	entries := planner.ScheduleWithUsers(tasks, users)
+	schedIssues = p2.ExtractCycleIssues(entries, allIssues, schedIssues)
+	ganttData, err := planner.ComputeGanttData(entries, tasks, true, base, users)
+	if err != nil {
+		return fmt.Errorf("scheduling failed: %w", err)
+	}
+
+	unschedulable := make(map[string]bool)
+	for _, si := range schedIssues {
+		unschedulable[si.IssueRef] = true
+	}
+	updates := p2.FilterUpdates(p2.PrepareUpdatesWithOptions(ganttData, allIssues, unschedulable, convertOpts), backend.NeedsUpdate)
+	p2.SortUpdates(updates)
+
+	if len(schedIssues) > 0 {
+		fmt.Fprintf(progress, "\nFound %d issues with scheduling problems:\n", len(schedIssues))
+		for _, si := range schedIssues {
+			fmt.Fprintf(progress, "  %s #%d: %s\n", privacy.RedactRepo(si.Owner, si.Repo), si.IssueNum, si.Reason)
+			for _, detail := range si.Details {
+				fmt.Fprintf(progress, "       - %s\n", detail)
+			}
+		}
+	}
+	if len(updates) == 0 {
+		fmt.Fprintln(progress, "No date changes needed")
+		if err := failingIssuesError(schedIssues, failOnIssues, privacy); err != nil {
+			return err
+		}
+		return emptyResult(cmd)
+	}
+
+	fmt.Fprintf(progress, "\nFound %d tasks with date changes:\n", len(updates))
+	for _, u := range updates {
+		fmt.Fprintf(progress, "  %s #%d %s\n", privacy.RedactRepo(u.Owner, u.Repo), u.IssueNum, u.Name)
+		if u.ClearDates {
+			fmt.Fprintf(progress, "       (clearing due date - task is %s)\n", u.ClearReason)
+		} else {
+			fmt.Fprintf(progress, "       Due Date: %s\n", u.ExpectedCompletion.Format("2006-01-02"))
+		}
+	}
+	if noWrite {
+		fmt.Fprintln(progress, "\nDry run - no changes made")
+		return failingIssuesError(schedIssues, failOnIssues, privacy)
+	}
+
+	if err := checkMaxUpdates(len(updates)); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(progress, "\nUpdating issues...")
+	failed := 0
+	for _, u := range updates {
+		if err := backend.ApplyUpdate(u); err != nil {
+			logrus.WithFields(privacy.LogFields(u.Owner, u.Repo, u.IssueNum)).Warnf("Failed to update issue: %v", err)
+			failed++
+		} else {
+			fmt.Fprintf(progress, "  Updated %s #%d\n", privacy.RedactRepo(u.Owner, u.Repo), u.IssueNum)
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Failed to apply %d of %d updates\n", failed, len(updates))
+	}
+	fmt.Fprintln(progress, "Done!")
+	return failingIssuesError(schedIssues, failOnIssues, privacy)
+}
+
+// failingIssuesError returns an error listing the scheduling issues whose
+// reason is in reasons, or nil if there are none. The reason "blocking"
+// matches every reason that prevents scheduling.
+func failingIssuesError(schedIssues []p2.SchedulingIssue, reasons []string, privacy *p2.PrivacyFilter) error {
+	if len(reasons) == 0 {
+		return nil
+	}
+	selected := make(map[string]bool, len(reasons))
+	for _, r := range reasons {
+		selected[strings.TrimSpace(r)] = true
+	}
+	var failing []string
+	for _, si := range schedIssues {
+		if !selected[si.Reason] && !(selected[ghscheduler.SeverityBlocking] && ghscheduler.Severity(si) == ghscheduler.SeverityBlocking) {
+			continue
+		}
+		ref := privacy.RedactDepID(fmt.Sprintf("%s/%s#%d", si.Owner, si.Repo, si.IssueNum))
+		failing = append(failing, fmt.Sprintf("%s (%s)", ref, si.Reason))
+	}
+	if len(failing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("found %d scheduling issues selected by --fail-on-issues: %s", len(failing), strings.Join(failing, ", "))
+}
+
+// wantsComment reports whether a scheduling issue gets a comment given
+// --no-comments and --comment-reasons. As with --fail-on-issues, the reasons
+// "blocking" and "warning" select every reason of that severity; no reasons
+// selects all.
+func wantsComment(si p2.SchedulingIssue, disabled bool, reasons []string) bool {
+	if disabled {
+		return false
+	}
+	if len(reasons) == 0 {
+		return true
+	}
+	for _, r := range reasons {
+		r = strings.TrimSpace(r)
+		if r == si.Reason || r == ghscheduler.Severity(si) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanupSchedulingComments deletes the scheduling comment from each open,
+// schedulable issue that no longer has a notice
+func cleanupSchedulingComments(accessToken string, allIssues map[string]github.IssueWithProject, issuesWithNotices map[string]bool, convertOpts p2.ConvertOptions, repoAllowlist p2.RepoAllowlist, features *ghscheduler.Features, privacy *p2.PrivacyFilter) {
+	fmt.Fprintln(progress, "\nCleaning up resolved scheduling comments...")
+	for ref, iwp := range allIssues {
+		// Skip draft issues
+		if iwp.IsDraft {
+			continue
+		}
+		// Skip issues that still have notices (scheduling problems or at-risk warnings)
+		if issuesWithNotices[ref] {
+			continue
+		}
+		// Skip on-hold and closed issues (they don't need scheduling comments cleaned up)
+		if convertOpts.IsHeld(iwp.SchedulingStatus) || strings.EqualFold(iwp.State, "closed") {
+			continue
+		}
+		if !features.CommentsEnabled(iwp.Owner, iwp.Repo) || !repoAllowlist.Allows(iwp.Owner, iwp.Repo) {
+			continue
+		}
+
+		client := github.NewClient(accessToken, &github.GitHubRepository{Owner: iwp.Owner, Name: iwp.Repo})
+		if err := ghscheduler.DeleteSchedulingComment(client, iwp.IssueNum); err != nil {
+			logrus.WithFields(privacy.LogFields(iwp.Owner, iwp.Repo, iwp.IssueNum)).Warnf("Failed to delete comment: %v", err)
+		}
+	}
+}
+
+// authenticate returns a GitHub access token from P2_LICENSE_KEY, stored
+// auth, or the device flow
+func authenticate() (string, error) {
+	if licenseKey := strings.TrimSpace(os.Getenv("P2_LICENSE_KEY")); licenseKey != "" {
+		token, err := p2license.ExtractToken(licenseKey)
+		if err != nil {
+			return "", fmt.Errorf("license key missing token: %w", err)
+		}
+		return token, nil
+	}
+
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return "", fmt.Errorf("P2_LICENSE_KEY is required when running in GitHub Actions")
+	}
+	// Fall back to stored auth (auto-refreshes if expired) or device flow
+	auth, err := github.LoadAndRefreshAuth()
+	if err != nil {
+		fmt.Fprintln(progress, "No stored GitHub authentication found. Starting device flow...")
+		auth, err = runDeviceFlow()
+		if err != nil {
+			return "", fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	// Verify token (in case refresh token also expired)
+	if err := github.VerifyToken(auth.AccessToken); err != nil {
+		fmt.Fprintln(progress, "Stored token is invalid. Starting device flow...")
+		auth, err = runDeviceFlow()
+		if err != nil {
+			return "", fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	return auth.AccessToken, nil
+}
+
+// issueAssignees returns every assignee of the issues with more than one,
+// keyed like the issues map
+func issueAssignees(accessToken string, issues map[string]github.IssueWithProject) (map[string][]string, error) {
+	refByItem := make(map[string]string)
+	var itemIDs []string
+	for ref, iwp := range issues {
+		if iwp.Project != nil && !iwp.IsDraft {
+			refByItem[iwp.Project.ItemID] = ref
+			itemIDs = append(itemIDs, iwp.Project.ItemID)
+		}
+	}
+	byItem, err := fetchAssignees(accessToken, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignees: %w", err)
+	}
+	assignees := make(map[string][]string)
+	for itemID, logins := range byItem {
+		if len(logins) > 1 {
+			assignees[refByItem[itemID]] = logins
+		}
+	}
+	return assignees, nil
+}
+
+// applyConfigFile sets flags from the --config file. Flags given on the
+// command line take precedence over the file. Subcommands skip the
+// scheduling flags they don't have, so one file serves every command.
+func applyConfigFile(cmd *cobra.Command) error {
+	values, err := p2.LoadConfigValues(configFile)
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		flag := cmd.Flags().Lookup(v.Key)
+		if flag == nil && cmd.HasParent() && cmd.Root().Flags().Lookup(v.Key) != nil {
+			continue
+		}
+		if flag == nil || v.Key == "config" || v.Key == "help" {
+			return fmt.Errorf("unknown key %q in %s: expected fields or a flag name such as base-date", v.Key, configFile)
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(v.Key, v.Value); err != nil {
+			return fmt.Errorf("invalid %s in %s: %w", v.Key, configFile, err)
+		}
+	}
+	return nil
+}
+
+// loadFieldNames returns the project field names from --config, overridden by --field-name
+func loadFieldNames() (p2.FieldNames, error) {
+	names, err := p2.LoadFieldNames(configFile)
+	if err != nil {
+		return names, err
+	}
+	for role, name := range fieldNameFlags {
+		if err := names.Set(role, name); err != nil {
+			return names, err
+		}
+	}
+	return names.WithDefaults(), nil
+}
+
+// filterProjectQuery removes the issues whose project items don't match query,
+// returning the number removed
+func filterProjectQuery(accessToken string, issues map[string]github.IssueWithProject, query string) (int, error) {
+	matching := make(map[string]map[string]bool)
+	excluded := 0
+	for ref, iwp := range issues {
+		if iwp.Project == nil {
+			continue
+		}
+		items, ok := matching[iwp.Project.ProjectID]
+		if !ok {
+			var err error
+			items, err = fetchMatchingItems(accessToken, iwp.Project.ProjectID, query)
+			if err != nil {
+				return 0, fmt.Errorf("failed to query project items: %w", err)
+			}
+			matching[iwp.Project.ProjectID] = items
+		}
+		if !items[iwp.Project.ItemID] {
+			delete(issues, ref)
+			excluded++
+		}
+	}
+	return excluded, nil
 }
 
-func main() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+// markPrivateIssues sets IsPrivate on issues in private repositories so the
+// privacy filter redacts them
+func markPrivateIssues(accessToken string, issues map[string]github.IssueWithProject) error {
+	seen := make(map[string]bool)
+	var repos []string
+	for _, iwp := range issues {
+		if iwp.IsDraft {
+			continue
+		}
+		repo := iwp.Owner + "/" + iwp.Repo
+		if !seen[repo] {
+			seen[repo] = true
+			repos = append(repos, repo)
+		}
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+	private, err := fetchPrivateRepos(accessToken, repos)
+	if err != nil {
+		return fmt.Errorf("failed to fetch repository visibility: %w", err)
+	}
+	for ref, iwp := range issues {
+		if private[iwp.Owner+"/"+iwp.Repo] {
+			iwp.IsPrivate = true
+			issues[ref] = iwp
+		}
 	}
+	return nil
 }
 
-func run(cmd *cobra.Command, args []string) error {
-	if debug {
-		logrus.SetLevel(logrus.DebugLevel)
-	} else {
-		logrus.SetLevel(logrus.WarnLevel)
+// readEarliestStarts returns the Earliest Start of each issue that has one,
+// keyed by task ID. Only items in projects with the field are queried.
+func readEarliestStarts(accessToken string, issues map[string]github.IssueWithProject) (map[string]time.Time, error) {
+	taskByItem := make(map[string]string)
+	var itemIDs []string
+	for _, iwp := range issues {
+		if iwp.Project == nil || iwp.IsDraft {
+			continue
+		}
+		if _, ok := iwp.Project.FieldIDs[p2.EarliestStartField]; ok {
+			taskByItem[iwp.Project.ItemID] = fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
+			itemIDs = append(itemIDs, iwp.Project.ItemID)
+		}
+	}
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+	values, err := fetchDateValues(accessToken, itemIDs, p2.EarliestStartField)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s field: %w", p2.EarliestStartField, err)
+	}
+	earliest := make(map[string]time.Time, len(values))
+	for itemID, date := range values {
+		earliest[taskByItem[itemID]] = date
 	}
+	return earliest, nil
+}
 
-	url := args[0]
+// writeAtRiskStatus sets the Scheduling Status of at-risk issues to
+// --at-risk-status and restores recovered ones, skipping frozen issues and
+// those outside --only-repos and --only-issues
+func writeAtRiskStatus(accessToken string, issues map[string]github.IssueWithProject, schedIssues []p2.SchedulingIssue, opts p2.ConvertOptions, repoAllowlist p2.RepoAllowlist, issueAllowlist p2.IssueAllowlist, privacy *p2.PrivacyFilter) {
+	atRisk := make(map[string]bool)
+	for _, si := range schedIssues {
+		if si.Reason == "at_risk" {
+			atRisk[si.IssueRef] = true
+		}
+	}
+	allowed := make(map[string]github.IssueWithProject)
+	for ref, iwp := range issues {
+		if repoAllowlist.Allows(iwp.Owner, iwp.Repo) && issueAllowlist.Allows(iwp.Owner, iwp.Repo, iwp.IssueNum) && !opts.IsFrozen(iwp.SchedulingStatus) {
+			allowed[ref] = iwp
+		}
+	}
+	written, err := ghscheduler.SetAtRiskStatus(newStatusWriter(accessToken), allowed, atRisk, atRiskStatus, onTrackStatus)
+	if err != nil {
+		logrus.Warnf("Failed to update %s: %v", ghscheduler.SchedulingStatusField, err)
+	}
+	for _, ref := range written {
+		iwp := issues[ref]
+		fmt.Fprintf(progress, "  Updated %s on %s #%d\n", ghscheduler.SchedulingStatusField, privacy.RedactRepo(iwp.Owner, iwp.Repo), iwp.IssueNum)
+	}
+}
 
-	// Authenticate with GitHub
-	var accessToken string
+// readTeams returns the Team of each issue that has one, keyed like the
+// issues map. Only items in projects with the field are queried.
+func readTeams(accessToken string, issues map[string]github.IssueWithProject) (map[string]string, error) {
+	refByItem := make(map[string]string)
+	var itemIDs []string
+	for ref, iwp := range issues {
+		if iwp.Project == nil {
+			continue
+		}
+		if _, ok := iwp.Project.FieldIDs[p2.TeamField]; ok {
+			refByItem[iwp.Project.ItemID] = ref
+			itemIDs = append(itemIDs, iwp.Project.ItemID)
+		}
+	}
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+	values, err := fetchSelectValues(accessToken, itemIDs, p2.TeamField)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s field: %w", p2.TeamField, err)
+	}
+	teams := make(map[string]string, len(values))
+	for itemID, team := range values {
+		teams[refByItem[itemID]] = team
+	}
+	return teams, nil
+}
 
-	if licenseKey := strings.TrimSpace(os.Getenv("P2_LICENSE_KEY")); licenseKey != "" {
-		token, err := p2license.ExtractToken(licenseKey)
-		if err != nil {
-			return fmt.Errorf("license key missing token: %w", err)
+// readRenamedFields reads the values of estimate and date fields whose names
+// differ from the defaults, which the project fetch only reads by default name
+func readRenamedFields(accessToken string, issues map[string]github.IssueWithProject, names p2.FieldNames) error {
+	var itemIDs []string
+	for _, iwp := range issues {
+		if iwp.Project != nil {
+			itemIDs = append(itemIDs, iwp.Project.ItemID)
 		}
-		accessToken = token
-	} else {
-		if os.Getenv("GITHUB_ACTIONS") == "true" {
-			return fmt.Errorf("P2_LICENSE_KEY is required when running in GitHub Actions")
+	}
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	numbers := []struct {
+		name, defaultName string
+		set               func(*github.IssueWithProject, *float64)
+	}{
+		{names.LowEstimate, p2.DefaultFieldNames.LowEstimate, func(iwp *github.IssueWithProject, v *float64) { iwp.LowEstimate = v }},
+		{names.HighEstimate, p2.DefaultFieldNames.HighEstimate, func(iwp *github.IssueWithProject, v *float64) { iwp.HighEstimate = v }},
+	}
+	for _, field := range numbers {
+		if field.name == field.defaultName {
+			continue
 		}
-		// Fall back to stored auth (auto-refreshes if expired) or device flow
-		auth, err := github.LoadAndRefreshAuth()
+		values, err := fetchFieldValues(accessToken, itemIDs, field.name)
 		if err != nil {
-			fmt.Println("No stored GitHub authentication found. Starting device flow...")
-			auth, err = runDeviceFlow()
-			if err != nil {
-				return fmt.Errorf("authentication failed: %w", err)
+			return fmt.Errorf("failed to read %s field: %w", field.name, err)
+		}
+		for ref, iwp := range issues {
+			if iwp.Project == nil {
+				continue
+			}
+			var value *float64
+			if v, ok := values[iwp.Project.ItemID]; ok {
+				value = &v
 			}
+			field.set(&iwp, value)
+			issues[ref] = iwp
 		}
+	}
 
-		// Verify token (in case refresh token also expired)
-		if err := github.VerifyToken(auth.AccessToken); err != nil {
-			fmt.Println("Stored token is invalid. Starting device flow...")
-			auth, err = runDeviceFlow()
-			if err != nil {
-				return fmt.Errorf("authentication failed: %w", err)
+	dates := []struct {
+		name, defaultName string
+		set               func(*github.IssueWithProject, *time.Time)
+	}{
+		{names.ExpectedStart, p2.DefaultFieldNames.ExpectedStart, func(iwp *github.IssueWithProject, v *time.Time) { iwp.ExpectedStart = v }},
+		{names.ExpectedCompletion, p2.DefaultFieldNames.ExpectedCompletion, func(iwp *github.IssueWithProject, v *time.Time) { iwp.ExpectedCompletion = v }},
+		{names.Completion98, p2.DefaultFieldNames.Completion98, func(iwp *github.IssueWithProject, v *time.Time) { iwp.Completion98 = v }},
+	}
+	renamedDates := false
+	for _, field := range dates {
+		if field.name == field.defaultName {
+			continue
+		}
+		renamedDates = true
+		values, err := fetchDateValues(accessToken, itemIDs, field.name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s field: %w", field.name, err)
+		}
+		for ref, iwp := range issues {
+			if iwp.Project == nil {
+				continue
+			}
+			var value *time.Time
+			if v, ok := values[iwp.Project.ItemID]; ok {
+				value = &v
 			}
+			field.set(&iwp, value)
+			issues[ref] = iwp
 		}
-		accessToken = auth.AccessToken
 	}
-
-	if debug {
-		logInstallationRepos(accessToken)
+	if renamedDates {
+		for ref, iwp := range issues {
+			iwp.HasSchedulingDates = iwp.ExpectedStart != nil || iwp.ExpectedCompletion != nil || iwp.Completion98 != nil
+			issues[ref] = iwp
+		}
 	}
+	return nil
+}
 
-	// Parse the URL to determine what we're working with
-	urlInfo, err := github.ParseGitHubURL(url)
+// writeOutput writes to path using write, or to stdout if path is "-"
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("invalid GitHub URL: %w", err)
+		return err
 	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
 
-	var allIssues map[string]github.IssueWithProject
-
+// fetchIssues fetches the issues to schedule for a project, issue, or repo URL
+func fetchIssues(accessToken string, urlInfo *github.URLInfo) (map[string]github.IssueWithProject, error) {
 	if urlInfo.IsProject {
 		// Fetch issues directly from the project
-		fmt.Printf("Fetching items from project %s #%d...\n", urlInfo.Owner, urlInfo.ProjectNum)
-		allIssues, err = fetchProjectItems(accessToken, urlInfo)
-		if err != nil {
-			return err
-		}
-	} else if urlInfo.IssueNum > 0 {
+		fmt.Fprintf(progress, "Fetching items from project %s #%d...\n", urlInfo.Owner, urlInfo.ProjectNum)
+		return fetchProjectItems(accessToken, urlInfo)
+	}
+
+	if urlInfo.IssueNum > 0 {
 		// Issue URL - look up its project and fetch all items from that project
-		fmt.Printf("Looking up project for %s/%s#%d...\n", urlInfo.Owner, urlInfo.Repo, urlInfo.IssueNum)
+		fmt.Fprintf(progress, "Looking up project for %s/%s#%d...\n", urlInfo.Owner, urlInfo.Repo, urlInfo.IssueNum)
 		projectInfo, err := lookupProjectForIssue(accessToken, urlInfo)
 		if err != nil {
 			// Issue is not in a project - nothing to schedule
-			fmt.Printf("Issue #%d is not in a project, nothing to schedule\n", urlInfo.IssueNum)
-			return nil
+			fmt.Fprintf(progress, "Issue #%d is not in a project, nothing to schedule\n", urlInfo.IssueNum)
+			return nil, nil
 		}
-		fmt.Printf("Fetching items from project %s #%d...\n", projectInfo.Owner, projectInfo.ProjectNum)
-		allIssues, err = fetchProjectItems(accessToken, projectInfo)
+		fmt.Fprintf(progress, "Fetching items from project %s #%d...\n", projectInfo.Owner, projectInfo.ProjectNum)
+		return fetchProjectItems(accessToken, projectInfo)
+	}
+
+	// Repo URL - find projects for issues in this repo and fetch all items from those projects
+	fmt.Fprintf(progress, "Looking up projects for %s/%s...\n", urlInfo.Owner, urlInfo.Repo)
+	return fetchRepoIssuesViaProjects(accessToken, urlInfo)
+}
+
+// fetchPullRequestIssues fetches the items of every project containing an
+// issue that pr closes
+func fetchPullRequestIssues(accessToken string, pr ghscheduler.PullRequestRef) (map[string]github.IssueWithProject, error) {
+	fmt.Fprintf(progress, "Looking up issues closed by %s/%s#%d...\n", pr.Owner, pr.Repo, pr.Number)
+	closing, err := fetchClosingIssues(accessToken, pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up issues closed by pull request: %w", err)
+	}
+	if len(closing) == 0 {
+		fmt.Fprintf(progress, "Pull request #%d does not close any issues, nothing to schedule\n", pr.Number)
+		return nil, nil
+	}
+
+	allIssues := make(map[string]github.IssueWithProject)
+	fetched := make(map[string]bool)
+	for _, ref := range closing {
+		issueInfo := &github.URLInfo{Owner: ref.Owner, Repo: ref.Repo, IssueNum: ref.Number}
+		projectInfo, err := lookupProjectForIssue(accessToken, issueInfo)
 		if err != nil {
-			return err
+			fmt.Fprintf(progress, "Issue %s/%s#%d is not in a project, skipping\n", ref.Owner, ref.Repo, ref.Number)
+			continue
 		}
-	} else {
-		// Repo URL - find projects for issues in this repo and fetch all items from those projects
-		fmt.Printf("Looking up projects for %s/%s...\n", urlInfo.Owner, urlInfo.Repo)
-		allIssues, err = fetchRepoIssuesViaProjects(accessToken, urlInfo)
+		key := fmt.Sprintf("%s/%d", projectInfo.Owner, projectInfo.ProjectNum)
+		if fetched[key] {
+			continue
+		}
+		fetched[key] = true
+
+		fmt.Fprintf(progress, "Fetching items from project %s #%d...\n", projectInfo.Owner, projectInfo.ProjectNum)
+		items, err := fetchProjectItems(accessToken, projectInfo)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		for k, v := range items {
+			allIssues[k] = v
 		}
 	}
+	return allIssues, nil
+}
 
-	if len(allIssues) == 0 {
-		fmt.Println("No issues to schedule")
-		return nil
+// issueRepos returns the distinct "owner/repo" of the non-draft issues
+func issueRepos(issues map[string]p2.IssueWithProject) []string {
+	seen := make(map[string]bool)
+	var repos []string
+	for _, iwp := range issues {
+		if iwp.IsDraft {
+			continue
+		}
+		r := iwp.Owner + "/" + iwp.Repo
+		if !seen[r] {
+			seen[r] = true
+			repos = append(repos, r)
+		}
 	}
+	return repos
+}
 
-	// Determine current repo for privacy filtering
-	currentRepo := os.Getenv("GITHUB_REPOSITORY")
-	if currentRepo == "" && urlInfo.Repo != "" {
-		currentRepo = urlInfo.Owner + "/" + urlInfo.Repo
+// runClearAll lists and applies updates that clear scheduling fields, without
+// scheduling. It runs the same checks as a scheduling run before writing.
+func runClearAll(accessToken string, updates []p2.DateUpdate, allIssues map[string]p2.IssueWithProject, privacy *p2.PrivacyFilter, fieldNames p2.FieldNames, noWrite bool) error {
+	if len(updates) == 0 {
+		fmt.Fprintln(progress, "No scheduling fields to clear")
+		return nil
 	}
-	privacy := p2.NewPrivacyFilter(currentRepo, allIssues)
 
-	// Convert issues to p2 tasks
-	fmt.Println("Converting to p2 tasks...")
-	tasks, users, schedIssues := p2.IssuesToTasks(allIssues, privacy)
-	fmt.Printf("Created %d tasks with %d users\n", len(tasks), len(users))
+	fmt.Fprintf(progress, "\nClearing scheduling fields from %d tasks:\n", len(updates))
+	for _, u := range updates {
+		fmt.Fprintf(progress, "  %s #%d %s (%s)\n", privacy.RedactRepo(u.Owner, u.Repo), u.IssueNum, privacy.RedactTitle(u.Owner, u.Repo, u.Name), u.ClearReason)
+	}
 
-	if len(tasks) == 0 {
-		fmt.Println("No tasks to schedule")
+	if noWrite {
+		fmt.Fprintln(progress, "\nDry run - no changes made")
 		return nil
 	}
 
-	// Run the scheduler
-	fmt.Println("Running scheduler...")
-	for _, t := range tasks {
-		if len(t.DependsOn) > 0 {
-			logrus.Debugf("Task %s (user=%q, done=%v, onhold=%v) depends on: %v", t.ID, t.User, t.Done, t.OnHold, t.DependsOn)
-		}
+	privateCount, publicCount := p2license.CountIssuePrivacy(allIssues)
+	if err := p2license.EnforceSchedule(progress, len(updates), privateCount, publicCount); err != nil {
+		return err
 	}
-	base := time.Now()
-	entries := planner.ScheduleWithUsers(tasks, users)
-
-	// Extract cycle information from scheduler results
-	schedIssues = p2.ExtractCycleIssues(entries, allIssues, schedIssues)
-
-	ganttData, err := planner.ComputeGanttData(entries, tasks, true, base, users)
-	if err != nil {
-		return fmt.Errorf("scheduling failed: %w", err)
+	if err := checkMaxUpdates(len(updates)); err != nil {
+		return err
 	}
-
-	// Build set of issues with scheduling problems
-	unschedulableIssues := make(map[string]bool)
-	for _, si := range schedIssues {
-		unschedulableIssues[si.IssueRef] = true
+	if err := checkProjectAccess(accessToken, projectIDs(updates)); err != nil {
+		return err
 	}
 
-	// Prepare updates
-	updates := p2.PrepareUpdates(ganttData, allIssues, unschedulableIssues)
-
-	// Detect at-risk issues (expected completion after due date)
-	atRiskIssues := p2.DetectAtRiskIssues(updates, allIssues)
-	schedIssues = append(schedIssues, atRiskIssues...)
-
-	// Build set of all issues with scheduling notices (including at-risk warnings)
-	issuesWithNotices := make(map[string]bool)
-	for _, si := range schedIssues {
-		issuesWithNotices[si.IssueRef] = true
+	applied := applyUpdates(accessToken, updates, privacy, fieldNames, false)
+	if len(applied) < len(updates) {
+		return fmt.Errorf("failed to clear scheduling fields from %d of %d tasks", len(updates)-len(applied), len(updates))
 	}
+	return nil
+}
 
-	// Print scheduling issues
-	if len(schedIssues) > 0 {
-		fmt.Printf("\nFound %d issues with scheduling problems:\n", len(schedIssues))
-		for _, si := range schedIssues {
-			fmt.Printf("  %s #%d: %s\n", privacy.RedactRepo(si.Owner, si.Repo), si.IssueNum, si.Reason)
-			for _, detail := range si.Details {
-				fmt.Printf("       - %s\n", privacy.RedactDepID(detail))
-			}
-		}
+// printSlips reports issues that slipped past the baseline schedule
+func printSlips(slips []p2.Slip, privacy *p2.PrivacyFilter) {
+	if len(slips) == 0 {
+		fmt.Fprintln(progress, "\nNo issues slipped past the baseline")
+		return
 	}
-
-	if len(updates) == 0 && len(schedIssues) == 0 {
-		fmt.Println("No date changes needed")
-		return nil
+	fmt.Fprintf(progress, "\n%d issues slipped past the baseline:\n", len(slips))
+	for _, s := range slips {
+		fmt.Fprintf(progress, "  %s #%d %s: %s -> %s (+%d working days)\n",
+			privacy.RedactRepo(s.Owner, s.Repo), s.IssueNum, privacy.RedactTitle(s.Owner, s.Repo, s.Name),
+			s.Baseline.Format("2006-01-02"), s.Current.Format("2006-01-02"), s.Days)
 	}
+}
 
-	if len(updates) > 0 {
-		fmt.Printf("\nFound %d tasks with date changes:\n", len(updates))
-		for _, u := range updates {
-			fmt.Printf("  %s #%d %s\n", privacy.RedactRepo(u.Owner, u.Repo), u.IssueNum, privacy.RedactTitle(u.Owner, u.Repo, u.Name))
-			if u.ClearDates {
-				if u.ClearReason == "closed" {
-					fmt.Println("       (clearing dates and estimates - task is closed)")
-				} else if u.ClearReason == "unschedulable" {
-					fmt.Println("       (clearing dates - has scheduling issues)")
-				} else {
-					fmt.Println("       (clearing dates - task is on hold)")
-				}
-			} else {
-				if !u.ExpectedStart.IsZero() {
-					fmt.Printf("       Expected Start: %s\n", u.ExpectedStart.Format("2006-01-02"))
-				}
-				if !u.ExpectedCompletion.IsZero() {
-					fmt.Printf("       Expected Completion: %s\n", u.ExpectedCompletion.Format("2006-01-02"))
-				}
-				if !u.Completion98.IsZero() {
-					fmt.Printf("       98%% Completion: %s\n", u.Completion98.Format("2006-01-02"))
-				}
-			}
+// printDrift reports issues whose Expected Completion moved since the previous plan,
+// continuing the run summary
+func printDrift(drifts []p2.Slip, privacy *p2.PrivacyFilter) {
+	later := 0
+	for _, d := range drifts {
+		if d.Days > 0 {
+			later++
 		}
 	}
-
-	privateCount, publicCount := p2license.CountIssuePrivacy(allIssues)
-
-	if dryRun {
-		fmt.Println("\nDry run - no changes made")
-		return nil
+	if len(drifts) == 0 {
+		fmt.Fprintln(progress, "  Moved since last run: 0")
+		return
 	}
-
-	if err := p2license.EnforceSchedule(os.Stdout, len(tasks), privateCount, publicCount); err != nil {
-		return err
+	fmt.Fprintf(progress, "  Moved since last run: %d (%d later, %d earlier)\n", len(drifts), later, len(drifts)-later)
+	for _, d := range drifts {
+		fmt.Fprintf(progress, "    %s #%d %s: %s -> %s (%+d working days)\n",
+			privacy.RedactRepo(d.Owner, d.Repo), d.IssueNum, privacy.RedactTitle(d.Owner, d.Repo, d.Name),
+			d.Baseline.Format("2006-01-02"), d.Current.Format("2006-01-02"), d.Days)
 	}
+}
 
-	// Apply updates to GitHub
-	fmt.Println("\nUpdating GitHub...")
+// projectIDs returns the distinct project node IDs that updates write to
+func projectIDs(updates []p2.DateUpdate) []string {
+	seen := make(map[string]bool)
+	var ids []string
 	for _, u := range updates {
-		client := github.NewClient(accessToken, &github.GitHubRepository{Owner: u.Owner, Name: u.Repo})
-		if err := ghscheduler.ApplyUpdate(client, u); err != nil {
-			logrus.Warnf("Failed to update issue #%d: %v", u.IssueNum, err)
-		} else {
-			fmt.Printf("  Updated %s #%d\n", privacy.RedactRepo(u.Owner, u.Repo), u.IssueNum)
+		if u.Project != nil && !seen[u.Project.ProjectID] {
+			seen[u.Project.ProjectID] = true
+			ids = append(ids, u.Project.ProjectID)
 		}
 	}
+	return ids
+}
 
-	// Post or update scheduling issue comments
-	if len(schedIssues) > 0 {
-		fmt.Println("\nUpdating scheduling comments...")
-		for _, si := range schedIssues {
-			client := github.NewClient(accessToken, &github.GitHubRepository{Owner: si.Owner, Name: si.Repo})
-			redactedSI := privacy.RedactSchedulingIssue(si)
-			if err := ghscheduler.PostOrUpdateSchedulingComment(client, redactedSI); err != nil {
-				logrus.Warnf("Failed to post comment for #%d: %v", si.IssueNum, err)
-			} else {
-				fmt.Printf("  Posted comment on %s #%d\n", privacy.RedactRepo(si.Owner, si.Repo), si.IssueNum)
-			}
+// applyUpdates writes the date fields for updates to GitHub. With
+// keepEstimatesOnClose, closed issues keep their estimates.
+func applyUpdates(accessToken string, updates []p2.DateUpdate, privacy *p2.PrivacyFilter, fieldNames p2.FieldNames, keepEstimatesOnClose bool) []p2.DateUpdate {
+	fmt.Fprintln(progress, "\nUpdating GitHub...")
+	var applied []p2.DateUpdate
+	failed := 0
+	// Each issue's fields are written in a single batched GraphQL request
+	writer := newFieldWriter(accessToken)
+	for _, u := range updates {
+		if err := ghscheduler.ApplyUpdateWithFields(writer, u, fieldNames, keepEstimatesOnClose, privacy); err != nil {
+			logrus.WithFields(privacy.LogFields(u.Owner, u.Repo, u.IssueNum)).Warnf("Failed to update issue: %v", err)
+			failed++
+		} else {
+			fmt.Fprintf(progress, "  Updated %s #%d\n", privacy.RedactRepo(u.Owner, u.Repo), u.IssueNum)
+			applied = append(applied, u)
 		}
 	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Failed to apply %d of %d updates\n", failed, len(updates))
+	}
+	return applied
+}
 
-	// Delete comments for issues that no longer have notices
-	fmt.Println("\nCleaning up resolved scheduling comments...")
-	for ref, iwp := range allIssues {
-		// Skip draft issues
-		if iwp.IsDraft {
-			continue
+// reviewPrompt returns a decision function for p2.FilterUpdates that shows
+// each update's current and proposed dates on out and reads a y/n/a/q answer from in
+func reviewPrompt(in io.Reader, out io.Writer, issues map[string]p2.IssueWithProject, privacy *p2.PrivacyFilter) func(p2.DateUpdate) bool {
+	scanner := bufio.NewScanner(in)
+	var all, quit bool
+	return func(u p2.DateUpdate) bool {
+		if all || quit {
+			return all
 		}
-		// Skip issues that still have notices (scheduling problems or at-risk warnings)
-		if issuesWithNotices[ref] {
-			continue
+		iwp := issues[fmt.Sprintf("github.com/%s/%s/issues/%d", u.Owner, u.Repo, u.IssueNum)]
+		fmt.Fprintf(out, "\n%s #%d %s\n", privacy.RedactRepo(u.Owner, u.Repo), u.IssueNum, privacy.RedactTitle(u.Owner, u.Repo, u.Name))
+		if u.ClearDates {
+			fmt.Fprintf(out, "       clear dates (%s)\n", u.ClearReason)
+		} else {
+			fmt.Fprintf(out, "       Expected Start:      %s -> %s\n", reviewDate(iwp.ExpectedStart), u.ExpectedStart.Format("2006-01-02"))
+			fmt.Fprintf(out, "       Expected Completion: %s -> %s\n", reviewDate(iwp.ExpectedCompletion), u.ExpectedCompletion.Format("2006-01-02"))
+			fmt.Fprintf(out, "       98%% Completion:      %s -> %s\n", reviewDate(iwp.Completion98), u.Completion98.Format("2006-01-02"))
 		}
-		// Skip on-hold and closed issues (they don't need scheduling comments cleaned up)
-		if iwp.SchedulingStatus == "On Hold" || strings.EqualFold(iwp.State, "closed") {
-			continue
+		for {
+			fmt.Fprint(out, "Apply? [y/n/a/q] ")
+			if !scanner.Scan() {
+				// End of input skips everything remaining
+				quit = true
+				return false
+			}
+			switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+			case "y", "yes":
+				return true
+			case "n", "no":
+				return false
+			case "a", "all":
+				all = true
+				return true
+			case "q", "quit":
+				quit = true
+				return false
+			}
 		}
+	}
+}
 
-		client := github.NewClient(accessToken, &github.GitHubRepository{Owner: iwp.Owner, Name: iwp.Repo})
-		if err := ghscheduler.DeleteSchedulingComment(client, iwp.IssueNum); err != nil {
-			logrus.Warnf("Failed to delete comment for #%d: %v", iwp.IssueNum, err)
-		}
+// reviewDate formats an optional current date for reviewPrompt
+func reviewDate(t *time.Time) string {
+	if t == nil {
+		return "(none)"
 	}
+	return t.Format("2006-01-02")
+}
 
-	fmt.Println("Done!")
-	return nil
+// loadTeamMembers populates availability.TeamMembers for each configured team in org
+func loadTeamMembers(token, org string, availability *p2.Availability) {
+	if len(availability.Teams) == 0 {
+		return
+	}
+	availability.TeamMembers = make(map[string][]string)
+	for team := range availability.Teams {
+		members, err := fetchTeamMembers(token, org, team)
+		if err != nil {
+			logrus.Warnf("Failed to fetch members of team %s/%s: %v", org, team, err)
+			continue
+		}
+		logrus.Debugf("Team %s/%s has %d members", org, team, len(members))
+		availability.TeamMembers[team] = members
+	}
 }
 
 func logInstallationRepos(token string) {
@@ -352,8 +1874,8 @@ func logInstallationRepos(token string) {
 func runDeviceFlow() (*github.StoredAuth, error) {
 	config := github.GetDefaultConfig()
 
-	fmt.Println("\nGitHub OAuth Authentication (Device Flow)")
-	fmt.Println("==========================================")
+	fmt.Fprintln(progress, "\nGitHub OAuth Authentication (Device Flow)")
+	fmt.Fprintln(progress, "==========================================")
 
 	// Request device code
 	deviceResp, err := github.RequestDeviceCode(config)
@@ -362,9 +1884,9 @@ func runDeviceFlow() (*github.StoredAuth, error) {
 	}
 
 	// Display instructions
-	fmt.Printf("\nTo complete authentication, visit:\n%s\n", deviceResp.VerificationURI)
-	fmt.Printf("\nAnd enter code: %s\n", deviceResp.UserCode)
-	fmt.Println("\nWaiting for you to complete authentication...")
+	fmt.Fprintf(progress, "\nTo complete authentication, visit:\n%s\n", deviceResp.VerificationURI)
+	fmt.Fprintf(progress, "\nAnd enter code: %s\n", deviceResp.UserCode)
+	fmt.Fprintln(progress, "\nWaiting for you to complete authentication...")
 
 	// Poll for token
 	timeout := time.Duration(deviceResp.ExpiresIn) * time.Second
@@ -372,44 +1894,80 @@ func runDeviceFlow() (*github.StoredAuth, error) {
 	defer cancel()
 
 	interval := time.Duration(deviceResp.Interval) * time.Second
+	tokenResp, err := pollDeviceToken(ctx, interval, func() (*github.TokenResponse, error) {
+		return github.PollForDeviceToken(config, deviceResp.DeviceCode, deviceResp.Interval)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Success! Verify and save
+	username, err := github.GetAuthenticatedUser(tokenResp.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify token: %w", err)
+	}
+
+	// Save authentication (includes refresh token and expiration if provided)
+	auth := github.TokenResponseToStoredAuth(tokenResp)
+
+	if err := github.SaveStoredAuth(auth); err != nil {
+		return nil, fmt.Errorf("failed to save auth: %w", err)
+	}
+
+	fmt.Fprintf(progress, "\nAuthenticated as: %s\n", username)
+	if auth.RefreshToken != "" {
+		fmt.Fprintln(progress, "✓ Refresh token received - session will auto-renew")
+	}
+	return &auth, nil
+}
+
+// maxTransientPollErrors is how many consecutive network errors the device
+// flow tolerates before giving up
+const maxTransientPollErrors = 3
+
+// pollDeviceToken calls poll every interval until it returns a token, the
+// user denies access, or ctx expires. Transient network errors are retried
+// (up to maxTransientPollErrors in a row) so a blip doesn't force the user to
+// restart the flow with a new code.
+func pollDeviceToken[T any](ctx context.Context, interval time.Duration, poll func() (T, error)) (T, error) {
+	var zero T
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	transientErrors := 0
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("authentication timeout - device code expired")
+			return zero, fmt.Errorf("authentication timeout - device code expired")
 		case <-ticker.C:
-			tokenResp, err := github.PollForDeviceToken(config, deviceResp.DeviceCode, deviceResp.Interval)
+			tokenResp, err := poll()
 			if err != nil {
 				if err.Error() == "authorization_pending" {
+					transientErrors = 0
 					continue
 				}
 				if err.Error() == "slow_down" {
 					ticker.Reset(interval * 2)
 					continue
 				}
-				return nil, fmt.Errorf("device flow error: %w", err)
-			}
-
-			// Success! Verify and save
-			username, err := github.GetAuthenticatedUser(tokenResp.AccessToken)
-			if err != nil {
-				return nil, fmt.Errorf("failed to verify token: %w", err)
-			}
-
-			// Save authentication (includes refresh token and expiration if provided)
-			auth := github.TokenResponseToStoredAuth(tokenResp)
-
-			if err := github.SaveStoredAuth(auth); err != nil {
-				return nil, fmt.Errorf("failed to save auth: %w", err)
-			}
-
-			fmt.Printf("\nAuthenticated as: %s\n", username)
-			if auth.RefreshToken != "" {
-				fmt.Println("✓ Refresh token received - session will auto-renew")
+				if isTransientNetworkError(err) && transientErrors < maxTransientPollErrors {
+					transientErrors++
+					logrus.Debugf("Transient error polling for device token (attempt %d/%d): %v", transientErrors, maxTransientPollErrors, err)
+					continue
+				}
+				return zero, fmt.Errorf("device flow error: %w", err)
 			}
-			return &auth, nil
+			return tokenResp, nil
 		}
 	}
 }
+
+// isTransientNetworkError returns true for connection-level failures that are
+// worth retrying
+func isTransientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
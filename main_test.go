@@ -1,10 +1,27 @@
 package main
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/octoberswimmer/p2-github-scheduler/ghscheduler"
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
 	"github.com/octoberswimmer/p2/github"
+	"github.com/octoberswimmer/p2/planner"
 	"github.com/spf13/cobra"
 )
 
@@ -94,3 +111,849 @@ func TestRun_RepoURL_FetchesViaProjects(t *testing.T) {
 		t.Errorf("expected nil error, got: %v", err)
 	}
 }
+
+func TestPollDeviceToken_RetriesTransientNetworkError(t *testing.T) {
+	calls := 0
+	poll := func() (string, error) {
+		calls++
+		switch calls {
+		case 1:
+			return "", errors.New("authorization_pending")
+		case 2:
+			return "", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection reset by peer")}
+		default:
+			return "access-token", nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	token, err := pollDeviceToken(ctx, time.Millisecond, poll)
+	if err != nil {
+		t.Fatalf("expected flow to complete after transient error, got: %v", err)
+	}
+	if token != "access-token" {
+		t.Errorf("expected access-token, got %q", token)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 polls, got %d", calls)
+	}
+}
+
+func TestPollDeviceToken_GivesUpAfterRepeatedNetworkErrors(t *testing.T) {
+	poll := func() (string, error) {
+		return "", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("network is unreachable")}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := pollDeviceToken(ctx, time.Millisecond, poll); err == nil {
+		t.Fatal("expected error after repeated network errors")
+	}
+}
+
+func TestPollDeviceToken_NonTransientErrorFails(t *testing.T) {
+	calls := 0
+	poll := func() (string, error) {
+		calls++
+		return "", errors.New("access_denied")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := pollDeviceToken(ctx, time.Millisecond, poll); err == nil {
+		t.Fatal("expected error for access_denied")
+	}
+	if calls != 1 {
+		t.Errorf("expected no retries for non-transient error, got %d calls", calls)
+	}
+}
+
+func TestRun_EmptyRunUsesConfiguredExitCode(t *testing.T) {
+	origFetch := fetchRepoIssuesViaProjects
+	origCode := emptyExitCode
+	defer func() {
+		fetchRepoIssuesViaProjects = origFetch
+		emptyExitCode = origCode
+	}()
+
+	fetchRepoIssuesViaProjects = func(accessToken string, info *github.URLInfo) (map[string]github.IssueWithProject, error) {
+		return nil, nil
+	}
+	t.Setenv("P2_LICENSE_KEY", `{"t":"test-token"}`)
+
+	emptyExitCode = 3
+	err := run(&cobra.Command{}, []string{"https://github.com/owner/repo"})
+	if code := exitCode(err); code != 3 {
+		t.Errorf("expected exit code 3 for empty run, got %d (err=%v)", code, err)
+	}
+
+	emptyExitCode = 0
+	err = run(&cobra.Command{}, []string{"https://github.com/owner/repo"})
+	if err != nil {
+		t.Errorf("expected nil error for empty run without --empty-exit-code, got: %v", err)
+	}
+}
+
+func TestRun_JSONOutputLeavesStdoutForReports(t *testing.T) {
+	origFetch := fetchRepoIssuesViaProjects
+	origFormat := outputFormat
+	defer func() {
+		fetchRepoIssuesViaProjects = origFetch
+		outputFormat = origFormat
+		progress = os.Stdout
+	}()
+	fetchRepoIssuesViaProjects = func(accessToken string, info *github.URLInfo) (map[string]github.IssueWithProject, error) {
+		return nil, nil
+	}
+	t.Setenv("P2_LICENSE_KEY", `{"t":"test-token"}`)
+
+	stdout := os.Stdout
+	outputFormat = "json"
+	if err := run(&cobra.Command{}, []string{"https://github.com/owner/repo"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if os.Stdout != stdout {
+		t.Error("expected os.Stdout to be left alone so - outputs still reach stdout")
+	}
+	if progress != os.Stderr {
+		t.Error("expected progress output to go to stderr with --output json")
+	}
+}
+
+func TestRun_DryRunLeavesPlanStateAlone(t *testing.T) {
+	origFetch, origPrivate := fetchRepoIssuesViaProjects, fetchPrivateRepos
+	origDryRun, origPlanState := dryRun, planStateFile
+	defer func() {
+		fetchRepoIssuesViaProjects, fetchPrivateRepos = origFetch, origPrivate
+		dryRun, planStateFile = origDryRun, origPlanState
+		progress = os.Stdout
+	}()
+	low, high := 1.0, 2.0
+	fetchRepoIssuesViaProjects = func(accessToken string, info *github.URLInfo) (map[string]github.IssueWithProject, error) {
+		return map[string]github.IssueWithProject{
+			"github.com/owner/repo/issues/1": {
+				Owner:        "owner",
+				Repo:         "repo",
+				IssueNum:     1,
+				State:        "open",
+				Assignee:     "alice",
+				LowEstimate:  &low,
+				HighEstimate: &high,
+				Project:      &github.ProjectItemInfo{ProjectID: "project", ItemID: "1"},
+			},
+		}, nil
+	}
+	fetchPrivateRepos = func(token string, repos []string) (map[string]bool, error) {
+		return map[string]bool{}, nil
+	}
+	t.Setenv("P2_LICENSE_KEY", `{"t":"test-token"}`)
+
+	dryRun = true
+	planStateFile = filepath.Join(t.TempDir(), "plans.json")
+	if err := run(&cobra.Command{}, []string{"https://github.com/owner/repo"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(planStateFile); !os.IsNotExist(err) {
+		t.Errorf("expected --dry-run not to write --plan-state, got %v", err)
+	}
+}
+
+func TestRun_JSONOutputRejectsTemplate(t *testing.T) {
+	origFormat, origTemplate := outputFormat, templateFile
+	defer func() { outputFormat, templateFile = origFormat, origTemplate }()
+
+	outputFormat = "json"
+	templateFile = "summary.tmpl"
+	err := run(&cobra.Command{}, []string{"https://github.com/owner/repo"})
+	if err == nil || !strings.Contains(err.Error(), "--template and --output json") {
+		t.Errorf("expected --template to conflict with --output json, got %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if code := exitCode(nil); code != 0 {
+		t.Errorf("expected 0 for nil error, got %d", code)
+	}
+	if code := exitCode(errors.New("boom")); code != 1 {
+		t.Errorf("expected 1 for ordinary error, got %d", code)
+	}
+	if code := exitCode(&exitError{code: 7}); code != 7 {
+		t.Errorf("expected 7 for exitError, got %d", code)
+	}
+}
+
+func TestReviewPrompt_Decisions(t *testing.T) {
+	updates := []p2.DateUpdate{
+		{Owner: "owner", Repo: "repo", IssueNum: 1},
+		{Owner: "owner", Repo: "repo", IssueNum: 2},
+		{Owner: "owner", Repo: "repo", IssueNum: 3},
+		{Owner: "owner", Repo: "repo", IssueNum: 4},
+	}
+	in := strings.NewReader("n\nmaybe\ny\na\n")
+	accepted := p2.FilterUpdates(updates, reviewPrompt(in, io.Discard, map[string]p2.IssueWithProject{}, p2.NewPrivacyFilter("owner/repo", nil)))
+	if len(accepted) != 3 || accepted[0].IssueNum != 2 || accepted[2].IssueNum != 4 {
+		t.Errorf("expected #2, #3, #4 to be accepted, got %+v", accepted)
+	}
+}
+
+func TestFetchPullRequestIssues_MultipleClosingIssues(t *testing.T) {
+	origClosing := fetchClosingIssues
+	origLookup := lookupProjectForIssue
+	origFetch := fetchProjectItems
+	defer func() {
+		fetchClosingIssues = origClosing
+		lookupProjectForIssue = origLookup
+		fetchProjectItems = origFetch
+	}()
+
+	fetchClosingIssues = func(accessToken string, pr ghscheduler.PullRequestRef) ([]github.IssueRef, error) {
+		return []github.IssueRef{
+			{Owner: "owner", Repo: "repo", Number: 4},
+			{Owner: "owner", Repo: "other", Number: 7},
+		}, nil
+	}
+	// Each issue is in a different project
+	lookupProjectForIssue = func(accessToken string, info *github.URLInfo) (*github.URLInfo, error) {
+		return &github.URLInfo{Owner: "owner", IsOrg: true, IsProject: true, ProjectNum: info.IssueNum}, nil
+	}
+	fetchProjectItems = func(accessToken string, info *github.URLInfo) (map[string]github.IssueWithProject, error) {
+		repo := "repo"
+		if info.ProjectNum == 7 {
+			repo = "other"
+		}
+		key := fmt.Sprintf("github.com/owner/%s/issues/%d", repo, info.ProjectNum)
+		return map[string]github.IssueWithProject{
+			key: {Owner: "owner", Repo: repo, IssueNum: info.ProjectNum},
+		}, nil
+	}
+
+	issues, err := fetchPullRequestIssues("test-token", ghscheduler.PullRequestRef{Owner: "owner", Repo: "repo", Number: 12})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{"github.com/owner/repo/issues/4", "github.com/owner/other/issues/7"} {
+		if _, ok := issues[key]; !ok {
+			t.Errorf("expected %s in issues, got %v", key, issues)
+		}
+	}
+}
+
+func TestReadRenamedFields_ReadsConfiguredNames(t *testing.T) {
+	origNumbers := fetchFieldValues
+	origDates := fetchDateValues
+	defer func() {
+		fetchFieldValues = origNumbers
+		fetchDateValues = origDates
+	}()
+
+	var requested []string
+	fetchFieldValues = func(accessToken string, itemIDs []string, fieldName string) (map[string]float64, error) {
+		requested = append(requested, fieldName)
+		if fieldName == "Est. Low" {
+			return map[string]float64{"item-1": 2}, nil
+		}
+		return map[string]float64{"item-1": 6}, nil
+	}
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	fetchDateValues = func(accessToken string, itemIDs []string, fieldName string) (map[string]time.Time, error) {
+		requested = append(requested, fieldName)
+		return map[string]time.Time{"item-1": start}, nil
+	}
+
+	issues := map[string]github.IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, Project: &github.ProjectItemInfo{ItemID: "item-1"}},
+	}
+	names := p2.FieldNames{LowEstimate: "Est. Low", HighEstimate: "Est. High", ExpectedStart: "Forecast Start"}.WithDefaults()
+
+	if err := readRenamedFields("test-token", issues, names); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requested) != 3 {
+		t.Errorf("expected only the 3 renamed fields to be read, got %v", requested)
+	}
+	iwp := issues["github.com/owner/repo/issues/1"]
+	if iwp.LowEstimate == nil || *iwp.LowEstimate != 2 || iwp.HighEstimate == nil || *iwp.HighEstimate != 6 {
+		t.Errorf("expected estimates 2-6, got %v-%v", iwp.LowEstimate, iwp.HighEstimate)
+	}
+	if iwp.ExpectedStart == nil || !iwp.ExpectedStart.Equal(start) || !iwp.HasSchedulingDates {
+		t.Errorf("expected Forecast Start to be read as Expected Start, got %v", iwp.ExpectedStart)
+	}
+}
+
+func TestReadEarliestStarts_OnlyQueriesProjectsWithTheField(t *testing.T) {
+	origDates := fetchDateValues
+	defer func() { fetchDateValues = origDates }()
+
+	var queried []string
+	delivery := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	fetchDateValues = func(accessToken string, itemIDs []string, fieldName string) (map[string]time.Time, error) {
+		queried = append(queried, itemIDs...)
+		return map[string]time.Time{"item-1": delivery}, nil
+	}
+
+	withField := map[string]string{p2.EarliestStartField: "field-9"}
+	issues := map[string]github.IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, Project: &github.ProjectItemInfo{ItemID: "item-1", FieldIDs: withField}},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, Project: &github.ProjectItemInfo{ItemID: "item-2"}},
+	}
+
+	earliest, err := readEarliestStarts("test-token", issues)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queried) != 1 || queried[0] != "item-1" {
+		t.Errorf("expected only item-1 to be queried, got %v", queried)
+	}
+	if len(earliest) != 1 || !earliest["owner/repo#1"].Equal(delivery) {
+		t.Errorf("expected owner/repo#1 to start no earlier than %s, got %v", delivery.Format("2006-01-02"), earliest)
+	}
+}
+
+func TestWantsComment(t *testing.T) {
+	cycle := p2.SchedulingIssue{Reason: "cycle"}
+	atRisk := p2.SchedulingIssue{Reason: "at_risk"}
+
+	if !wantsComment(cycle, false, nil) || !wantsComment(atRisk, false, nil) {
+		t.Error("expected every reason to be commented on by default")
+	}
+	if wantsComment(cycle, true, nil) {
+		t.Error("expected --no-comments to disable comments")
+	}
+	if !wantsComment(cycle, false, []string{"cycle", "missing_estimate"}) || wantsComment(atRisk, false, []string{"cycle", "missing_estimate"}) {
+		t.Error("expected only the listed reasons to be commented on")
+	}
+	if wantsComment(cycle, false, []string{"warning"}) || !wantsComment(atRisk, false, []string{"warning"}) {
+		t.Error("expected a severity to select its reasons")
+	}
+}
+
+func TestFailingIssuesError(t *testing.T) {
+	schedIssues := []p2.SchedulingIssue{
+		{Owner: "owner", Repo: "repo", IssueNum: 1, Reason: "cycle"},
+		{Owner: "owner", Repo: "repo", IssueNum: 2, Reason: "at_risk"},
+		{Owner: "owner", Repo: "repo", IssueNum: 3, Reason: "missing_estimate"},
+	}
+	privacy := p2.NewPrivacyFilter("owner/repo", nil)
+
+	if err := failingIssuesError(schedIssues, nil, privacy); err != nil {
+		t.Errorf("expected no error when the flag is off, got %v", err)
+	}
+	if err := failingIssuesError(schedIssues, []string{"missing_dependency"}, privacy); err != nil {
+		t.Errorf("expected no error without matching reasons, got %v", err)
+	}
+
+	err := failingIssuesError(schedIssues, []string{"cycle"}, privacy)
+	if err == nil || err.Error() != "found 1 scheduling issues selected by --fail-on-issues: owner/repo#1 (cycle)" {
+		t.Errorf("expected the cycle to be reported, got %v", err)
+	}
+
+	err = failingIssuesError(schedIssues, []string{"blocking"}, privacy)
+	if err == nil || !strings.Contains(err.Error(), "owner/repo#3 (missing_estimate)") || strings.Contains(err.Error(), "at_risk") {
+		t.Errorf("expected blocking reasons but not warnings to be reported, got %v", err)
+	}
+}
+
+func TestApplyConfigFile_FlagsOverrideFile(t *testing.T) {
+	origConfig := configFile
+	defer func() { configFile = origConfig }()
+	configFile = filepath.Join(t.TempDir(), ".p2scheduler.yml")
+	content := "base-date: 2025-01-06\ndefault-low: 2\nrepo-filter: [owner/api, owner/web]\n"
+	if err := os.WriteFile(configFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var base string
+	var low float64
+	var repos []string
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&base, "base-date", "", "")
+	cmd.Flags().Float64Var(&low, "default-low", 1, "")
+	cmd.Flags().StringSliceVar(&repos, "repo-filter", nil, "")
+	if err := cmd.Flags().Parse([]string{"--default-low", "4"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyConfigFile(cmd); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base != "2025-01-06" {
+		t.Errorf("expected base-date from config, got %q", base)
+	}
+	if low != 4 {
+		t.Errorf("expected --default-low to override config, got %g", low)
+	}
+	if len(repos) != 2 || repos[0] != "owner/api" || repos[1] != "owner/web" {
+		t.Errorf("expected repo-filter from config, got %v", repos)
+	}
+}
+
+func TestApplyConfigFile_UnknownKey(t *testing.T) {
+	origConfig := configFile
+	defer func() { configFile = origConfig }()
+	configFile = filepath.Join(t.TempDir(), ".p2scheduler.yml")
+	if err := os.WriteFile(configFile, []byte("base-dat: 2025-01-06\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("base-date", "", "")
+	err := applyConfigFile(cmd)
+	if err == nil || !strings.Contains(err.Error(), `unknown key "base-dat"`) {
+		t.Errorf("expected unknown key error, got %v", err)
+	}
+}
+
+func TestApplyConfigFile_SubcommandSkipsSchedulingFlags(t *testing.T) {
+	origConfig := configFile
+	defer func() { configFile = origConfig }()
+	configFile = filepath.Join(t.TempDir(), ".p2scheduler.yml")
+	content := "base-date: 2025-01-06\non-hold-status: [Parked]\n"
+	if err := os.WriteFile(configFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var statuses []string
+	root := &cobra.Command{}
+	root.Flags().String("base-date", "", "")
+	cmd := &cobra.Command{Use: "clear"}
+	cmd.Flags().StringSliceVar(&statuses, "on-hold-status", nil, "")
+	root.AddCommand(cmd)
+
+	if err := applyConfigFile(cmd); err != nil {
+		t.Fatalf("expected scheduling flags to be skipped, got %v", err)
+	}
+	if len(statuses) != 1 || statuses[0] != "Parked" {
+		t.Errorf("expected on-hold-status from config, got %v", statuses)
+	}
+}
+
+func TestMarkPrivateIssues_RedactsOtherPrivateRepos(t *testing.T) {
+	origFetch := fetchPrivateRepos
+	defer func() { fetchPrivateRepos = origFetch }()
+	var requested []string
+	fetchPrivateRepos = func(token string, repos []string) (map[string]bool, error) {
+		requested = repos
+		return map[string]bool{"myorg/secret": true, "myorg/app": true, "myorg/public": false}, nil
+	}
+
+	issues := map[string]github.IssueWithProject{
+		"github.com/myorg/secret/issues/1": {Owner: "myorg", Repo: "secret", IssueNum: 1, Title: "Secret plans"},
+		"github.com/myorg/secret/issues/2": {Owner: "myorg", Repo: "secret", IssueNum: 2, Title: "More secrets"},
+		"github.com/myorg/app/issues/3":    {Owner: "myorg", Repo: "app", IssueNum: 3, Title: "App work"},
+		"github.com/myorg/public/issues/4": {Owner: "myorg", Repo: "public", IssueNum: 4, Title: "Open work"},
+	}
+	if err := markPrivateIssues("test-token", issues); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requested) != 3 {
+		t.Errorf("expected each repository to be looked up once, got %v", requested)
+	}
+	if !issues["github.com/myorg/secret/issues/1"].IsPrivate || issues["github.com/myorg/public/issues/4"].IsPrivate {
+		t.Errorf("expected only private repos to be marked, got %+v", issues)
+	}
+
+	privacy := p2.NewPrivacyFilter("myorg/app", issues)
+	if got := privacy.RedactTitle("myorg", "secret", "Secret plans"); got != "" {
+		t.Errorf("expected other private repo title to be redacted, got %q", got)
+	}
+	if got := privacy.RedactRepo("myorg", "secret"); got != "[private]" {
+		t.Errorf("expected other private repo to be redacted, got %q", got)
+	}
+	if got := privacy.RedactTitle("myorg", "app", "App work"); got != "App work" {
+		t.Errorf("expected current repo title, got %q", got)
+	}
+	if got := privacy.RedactTitle("myorg", "public", "Open work"); got != "Open work" {
+		t.Errorf("expected public repo title, got %q", got)
+	}
+}
+
+func TestFilterProjectQuery_DropsUnmatchedItems(t *testing.T) {
+	origFetch := fetchMatchingItems
+	defer func() { fetchMatchingItems = origFetch }()
+	queries := 0
+	fetchMatchingItems = func(token, projectID, query string) (map[string]bool, error) {
+		queries++
+		if projectID != "project-1" || query != "label:backend" {
+			t.Errorf("unexpected query %q for %s", query, projectID)
+		}
+		return map[string]bool{"item-1": true}, nil
+	}
+
+	issues := map[string]github.IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  1,
+			State:     "open",
+			Project:   &github.ProjectItemInfo{ProjectID: "project-1", ItemID: "item-1"},
+			BlockedBy: []github.IssueRef{{Owner: "owner", Repo: "repo", Number: 2, State: "open"}},
+		},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, State: "open", Project: &github.ProjectItemInfo{ProjectID: "project-1", ItemID: "item-2"}},
+	}
+	excluded, err := filterProjectQuery("test-token", issues, "label:backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if excluded != 1 || queries != 1 {
+		t.Errorf("expected 1 item excluded with 1 query, got %d excluded with %d queries", excluded, queries)
+	}
+	if _, ok := issues["github.com/owner/repo/issues/2"]; ok {
+		t.Error("expected unmatched item to be removed")
+	}
+
+	_, _, schedIssues := p2.IssuesToTasks(issues, nil)
+	missing := false
+	for _, si := range schedIssues {
+		if si.Reason == "missing_dependency" && si.IssueNum == 1 && len(si.Details) == 1 && si.Details[0] == "owner/repo#2" {
+			missing = true
+		}
+	}
+	if !missing {
+		t.Errorf("expected #1 to report its excluded blocker as missing, got %+v", schedIssues)
+	}
+}
+
+// fakeBackend is an in-memory issueBackend
+type fakeBackend struct {
+	issues  map[string]p2.IssueWithProject
+	applied []p2.DateUpdate
+}
+
+func (b *fakeBackend) FetchIssues() (map[string]p2.IssueWithProject, error) {
+	return b.issues, nil
+}
+
+func (b *fakeBackend) NeedsUpdate(update p2.DateUpdate) bool {
+	return true
+}
+
+func (b *fakeBackend) ApplyUpdate(update p2.DateUpdate) error {
+	b.applied = append(b.applied, update)
+	return nil
+}
+
+func TestRunBackend_ClearsClosedIssueDates(t *testing.T) {
+	due := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	backend := &fakeBackend{issues: map[string]p2.IssueWithProject{
+		"github.com/group/project/issues/1": {
+			Owner:              "group",
+			Repo:               "project",
+			IssueNum:           1,
+			Title:              "Shipped",
+			State:              "closed",
+			Project:            &github.ProjectItemInfo{ProjectID: "group/project", ItemID: "1"},
+			ExpectedCompletion: &due,
+			HasSchedulingDates: true,
+		},
+	}}
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	if err := runBackend(&cobra.Command{}, backend, base, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backend.applied) != 0 {
+		t.Errorf("expected dry run not to apply updates, got %v", backend.applied)
+	}
+
+	if err := runBackend(&cobra.Command{}, backend, base, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backend.applied) != 1 || !backend.applied[0].ClearDates || backend.applied[0].ClearReason != "closed" {
+		t.Errorf("expected closed #1 to have its dates cleared, got %+v", backend.applied)
+	}
+}
+
+func TestRunBackend_MaxUpdatesAbortsBeforeWriting(t *testing.T) {
+	origMax := maxUpdates
+	defer func() { maxUpdates = origMax }()
+
+	due := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	issues := make(map[string]p2.IssueWithProject)
+	for i := 1; i <= 2; i++ {
+		issues[fmt.Sprintf("github.com/group/project/issues/%d", i)] = p2.IssueWithProject{
+			Owner:              "group",
+			Repo:               "project",
+			IssueNum:           i,
+			State:              "closed",
+			Project:            &github.ProjectItemInfo{ProjectID: "group/project", ItemID: fmt.Sprint(i)},
+			ExpectedCompletion: &due,
+			HasSchedulingDates: true,
+		}
+	}
+	backend := &fakeBackend{issues: issues}
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	maxUpdates = 1
+	err := runBackend(&cobra.Command{}, backend, base, false)
+	if err == nil || !strings.Contains(err.Error(), "--max-updates 1") {
+		t.Fatalf("expected --max-updates error, got %v", err)
+	}
+	if len(backend.applied) != 0 {
+		t.Errorf("expected no updates to be applied, got %+v", backend.applied)
+	}
+
+	maxUpdates = 2
+	if err := runBackend(&cobra.Command{}, backend, base, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backend.applied) != 2 {
+		t.Errorf("expected both updates at the cap to be applied, got %+v", backend.applied)
+	}
+}
+
+func TestRunValidate_FailsOnMissingRequiredField(t *testing.T) {
+	origFetch := fetchProjectFields
+	origConfig := configFile
+	defer func() { fetchProjectFields, configFile = origFetch, origConfig }()
+	configFile = filepath.Join(t.TempDir(), ".p2scheduler.yml")
+	t.Setenv("P2_LICENSE_KEY", `{"t":"test-token"}`)
+
+	fields := []ghscheduler.ProjectField{
+		{Name: "Low Estimate", DataType: "NUMBER"},
+		{Name: "High Estimate", DataType: "NUMBER"},
+		{Name: "Expected Start", DataType: "DATE"},
+		{Name: "Expected Completion", DataType: "DATE"},
+	}
+	var requested ghscheduler.ProjectRef
+	fetchProjectFields = func(token string, project ghscheduler.ProjectRef) ([]ghscheduler.ProjectField, error) {
+		requested = project
+		return fields, nil
+	}
+
+	err := runValidate(&cobra.Command{}, []string{"https://github.com/orgs/myorg/projects/4/views/1"})
+	if err == nil || !strings.Contains(err.Error(), "1 required project fields") {
+		t.Errorf("expected missing 98%% Completion to fail validation, got %v", err)
+	}
+	if requested.Owner != "myorg" || requested.Number != 4 {
+		t.Errorf("expected fields of myorg project 4 to be fetched, got %+v", requested)
+	}
+
+	// Missing optional fields are only warnings
+	fields = append(fields, ghscheduler.ProjectField{Name: "98% Completion", DataType: "DATE"})
+	if err := runValidate(&cobra.Command{}, []string{"https://github.com/orgs/myorg/projects/4"}); err != nil {
+		t.Errorf("expected only warnings for optional fields, got %v", err)
+	}
+
+	if err := runValidate(&cobra.Command{}, []string{"owner/repo"}); err == nil {
+		t.Error("expected a repository URL to be rejected")
+	}
+}
+
+func TestWebhookHandler_CoalescesBurstIntoOneRun(t *testing.T) {
+	var mu sync.Mutex
+	runs := make(map[string]int)
+	done := make(chan struct{}, 10)
+	r := newRescheduler(50*time.Millisecond, func(url string) {
+		mu.Lock()
+		runs[url]++
+		mu.Unlock()
+		done <- struct{}{}
+	})
+	urls := []string{"https://github.com/orgs/myorg/projects/1", "https://github.com/orgs/other/projects/2"}
+	server := httptest.NewServer(webhookHandler("s3cret", urls, r.trigger))
+	defer server.Close()
+
+	deliver := func(event, body, secret string) int {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	body := `{"action":"edited","organization":{"login":"myorg"}}`
+	if code := deliver("projects_v2_item", body, "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("expected unsigned delivery to be rejected with 401, got %d", code)
+	}
+	for i := 0; i < 5; i++ {
+		if code := deliver("projects_v2_item", body, "s3cret"); code != http.StatusAccepted {
+			t.Fatalf("expected delivery to be accepted, got %d", code)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a run after the burst")
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if n := runs[urls[0]]; n != 1 {
+		t.Errorf("expected the burst to coalesce into 1 run of %s, got %d", urls[0], n)
+	}
+	if n := runs[urls[1]]; n != 0 {
+		t.Errorf("expected no run of the unaffected project, got %d", n)
+	}
+}
+
+// failingFieldWriter is a DateFieldClient that records cleared items and
+// fails for the items in fail
+type failingFieldWriter struct {
+	fail    map[string]bool
+	cleared []string
+}
+
+func (w *failingFieldWriter) ClearField(projectID, itemID, fieldID string) error {
+	if w.fail[itemID] {
+		return errors.New("502 Bad Gateway")
+	}
+	w.cleared = append(w.cleared, itemID)
+	return nil
+}
+
+func (w *failingFieldWriter) UpdateDateField(projectID, itemID, fieldID string, date time.Time) error {
+	return nil
+}
+
+// clearAllUpdates returns archived-issue updates for n items that each have
+// an Expected Start field
+func clearAllUpdates(n int) []p2.DateUpdate {
+	var updates []p2.DateUpdate
+	for i := 1; i <= n; i++ {
+		updates = append(updates, p2.DateUpdate{
+			Owner:       "owner",
+			Repo:        "repo",
+			IssueNum:    i,
+			ClearDates:  true,
+			ClearReason: "archived",
+			Project: &github.ProjectItemInfo{
+				ProjectID: "project",
+				ItemID:    fmt.Sprint(i),
+				FieldIDs:  map[string]string{"Expected Start": "start"},
+			},
+		})
+	}
+	return updates
+}
+
+func TestRunClearAll_ReturnsErrorWhenWritesFail(t *testing.T) {
+	origWriter, origAccess := newFieldWriter, checkProjectAccess
+	defer func() {
+		newFieldWriter, checkProjectAccess = origWriter, origAccess
+		progress = os.Stdout
+	}()
+	progress = io.Discard
+	checkProjectAccess = func(string, []string) error { return nil }
+	writer := &failingFieldWriter{fail: map[string]bool{"2": true}}
+	newFieldWriter = func(string) ghscheduler.DateFieldClient { return writer }
+
+	issues := map[string]p2.IssueWithProject{}
+	privacy := p2.NewPrivacyFilter("owner/repo", issues)
+	err := runClearAll("token", clearAllUpdates(2), issues, privacy, p2.DefaultFieldNames, false)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 tasks") {
+		t.Errorf("expected failed write to be reported, got %v", err)
+	}
+	if len(writer.cleared) != 1 || writer.cleared[0] != "1" {
+		t.Errorf("expected the other item to be cleared, got %v", writer.cleared)
+	}
+}
+
+func TestRunClearAll_ChecksBeforeWriting(t *testing.T) {
+	origWriter, origAccess, origMax := newFieldWriter, checkProjectAccess, maxUpdates
+	defer func() {
+		newFieldWriter, checkProjectAccess, maxUpdates = origWriter, origAccess, origMax
+		progress = os.Stdout
+	}()
+	progress = io.Discard
+	writer := &failingFieldWriter{}
+	newFieldWriter = func(string) ghscheduler.DateFieldClient { return writer }
+	issues := map[string]p2.IssueWithProject{}
+	privacy := p2.NewPrivacyFilter("owner/repo", issues)
+
+	checkProjectAccess = func(string, []string) error { return nil }
+	maxUpdates = 1
+	err := runClearAll("token", clearAllUpdates(2), issues, privacy, p2.DefaultFieldNames, false)
+	if err == nil || !strings.Contains(err.Error(), "--max-updates 1") {
+		t.Errorf("expected --max-updates error, got %v", err)
+	}
+
+	maxUpdates = 0
+	checkProjectAccess = func(string, []string) error { return errors.New("token cannot write project") }
+	err = runClearAll("token", clearAllUpdates(2), issues, privacy, p2.DefaultFieldNames, false)
+	if err == nil || !strings.Contains(err.Error(), "cannot write project") {
+		t.Errorf("expected project access error, got %v", err)
+	}
+	if len(writer.cleared) != 0 {
+		t.Errorf("expected nothing to be cleared, got %v", writer.cleared)
+	}
+}
+
+type recordedStatusWriter struct {
+	writes map[string]string
+}
+
+func (w *recordedStatusWriter) UpdateSingleSelectField(projectID, itemID, fieldID, optionID string) error {
+	w.writes[itemID] = optionID
+	return nil
+}
+
+func (w *recordedStatusWriter) ClearField(projectID, itemID, fieldID string) error {
+	w.writes[itemID] = ""
+	return nil
+}
+
+func TestWriteAtRiskStatus_StaysSetWhileDatesDontChange(t *testing.T) {
+	origWriter := newStatusWriter
+	origAtRisk, origOnTrack := atRiskStatus, onTrackStatus
+	defer func() {
+		newStatusWriter = origWriter
+		atRiskStatus, onTrackStatus = origAtRisk, origOnTrack
+		progress = os.Stdout
+	}()
+	progress = io.Discard
+	atRiskStatus, onTrackStatus = "At Risk", "On Track"
+	writer := &recordedStatusWriter{writes: make(map[string]string)}
+	newStatusWriter = func(string) ghscheduler.StatusFieldWriter { return writer }
+
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	start, completion, end98, due := day(2), day(10), day(12), day(6)
+	ref := "github.com/owner/repo/issues/1"
+	issues := map[string]github.IssueWithProject{
+		ref: {
+			Owner: "owner", Repo: "repo", IssueNum: 1, State: "open",
+			ExpectedStart: &start, ExpectedCompletion: &completion, Completion98: &end98, DueDate: &due,
+			Project: &github.ProjectItemInfo{
+				ProjectID:           "proj-1",
+				ItemID:              "item-1",
+				FieldIDs:            map[string]string{ghscheduler.SchedulingStatusField: "status-field"},
+				SingleSelectOptions: map[string]map[string]string{ghscheduler.SchedulingStatusField: {"At Risk": "opt-risk", "On Track": "opt-ok"}},
+			},
+		},
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{{ID: "owner/repo#1", ExpStartDate: start, MeanDate: completion, End98Date: end98}},
+	}
+
+	// Two runs in a row reach the same schedule, so no dates are written
+	for run := 1; run <= 2; run++ {
+		if updates := p2.PrepareUpdates(ganttData, issues, nil); len(updates) != 0 {
+			t.Fatalf("run %d: expected no date changes, got %+v", run, updates)
+		}
+		schedIssues := p2.DetectAtRiskIssuesWithOptions(p2.CurrentSchedule(ganttData, issues), issues, p2.AtRiskOptions{})
+		writeAtRiskStatus("token", issues, schedIssues, p2.ConvertOptions{}, nil, nil, p2.NewPrivacyFilter("owner/repo", issues))
+
+		if option, ok := writer.writes["item-1"]; ok {
+			iwp := issues[ref]
+			iwp.SchedulingStatus = map[string]string{"opt-risk": "At Risk", "opt-ok": "On Track"}[option]
+			issues[ref] = iwp
+		}
+		if status := issues[ref].SchedulingStatus; status != "At Risk" {
+			t.Errorf("run %d: expected the late issue to stay At Risk, got %q", run, status)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package p2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RepoAllowlist limits scheduling, writes, or comments to a set of repositories.
+// A nil RepoAllowlist allows every repository.
+type RepoAllowlist map[string]bool
+
+// ParseRepoAllowlist builds a RepoAllowlist from "owner/repo" entries.
+// An empty list returns nil, allowing all repositories.
+func ParseRepoAllowlist(repos []string) (RepoAllowlist, error) {
+	if len(repos) == 0 {
+		return nil, nil
+	}
+	allow := make(RepoAllowlist, len(repos))
+	for _, r := range repos {
+		r = strings.TrimSpace(r)
+		owner, repo, ok := strings.Cut(r, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid repository %q: expected owner/repo", r)
+		}
+		allow[strings.ToLower(r)] = true
+	}
+	return allow, nil
+}
+
+// Allows reports whether owner/repo may be written to. Names are compared case-insensitively.
+func (a RepoAllowlist) Allows(owner, repo string) bool {
+	return a == nil || a[strings.ToLower(owner+"/"+repo)]
+}
+
+// FilterUpdates drops updates for repositories that are not allowed
+func (a RepoAllowlist) FilterUpdates(updates []DateUpdate) []DateUpdate {
+	if a == nil {
+		return updates
+	}
+	return FilterUpdates(updates, func(u DateUpdate) bool { return a.Allows(u.Owner, u.Repo) })
+}
+
+// FilterSchedulingIssues drops scheduling issues for repositories that are not allowed
+func (a RepoAllowlist) FilterSchedulingIssues(schedIssues []SchedulingIssue) []SchedulingIssue {
+	if a == nil {
+		return schedIssues
+	}
+	var allowed []SchedulingIssue
+	for _, si := range schedIssues {
+		if a.Allows(si.Owner, si.Repo) {
+			allowed = append(allowed, si)
+		}
+	}
+	return allowed
+}
+
+// FilterIssues removes issues in repositories that are not allowed and returns
+// how many were removed. Dependencies on removed issues are then reported as
+// missing, like blockers the token can't access.
+func (a RepoAllowlist) FilterIssues(issues map[string]IssueWithProject) int {
+	if a == nil {
+		return 0
+	}
+	excluded := 0
+	for ref, iwp := range issues {
+		if !a.Allows(iwp.Owner, iwp.Repo) {
+			delete(issues, ref)
+			excluded++
+		}
+	}
+	return excluded
+}
+
+// FilterAssignee removes issues not assigned to login (compared
+// case-insensitively), keeping the issues they are transitively blocked by
+// so the queue still waits for them. assignees lists every assignee of
+// issues with more than one, keyed like issues; any of them may match. It
+// returns how many issues were removed and how many were kept as blockers.
+func FilterAssignee(issues map[string]IssueWithProject, login string, assignees map[string][]string) (excluded, blockers int) {
+	keep := make(map[string]bool)
+	var queue []string
+	for ref, iwp := range issues {
+		if assignedTo(iwp.Assignee, assignees[ref], login) {
+			keep[ref] = true
+			queue = append(queue, ref)
+		}
+	}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		for _, b := range issues[ref].BlockedBy {
+			blockerRef := fmt.Sprintf("github.com/%s/%s/issues/%d", b.Owner, b.Repo, b.Number)
+			if _, ok := issues[blockerRef]; ok && !keep[blockerRef] {
+				keep[blockerRef] = true
+				queue = append(queue, blockerRef)
+				blockers++
+			}
+		}
+	}
+	for ref := range issues {
+		if !keep[ref] {
+			delete(issues, ref)
+			excluded++
+		}
+	}
+	return excluded, blockers
+}
+
+// assignedTo reports whether login is the first assignee or one of others
+func assignedTo(first string, others []string, login string) bool {
+	if strings.EqualFold(first, login) {
+		return true
+	}
+	for _, a := range others {
+		if strings.EqualFold(a, login) {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueAllowlist limits writes to a set of issues. A nil IssueAllowlist
+// allows every issue.
+type IssueAllowlist map[string]bool
+
+// ParseIssueAllowlist builds an IssueAllowlist from "owner/repo#N" entries.
+// An empty list returns nil, allowing all issues.
+func ParseIssueAllowlist(refs []string) (IssueAllowlist, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	allow := make(IssueAllowlist, len(refs))
+	for _, r := range refs {
+		ref, ok := parseIssueRef(r)
+		if !ok {
+			return nil, fmt.Errorf("invalid issue %q: expected owner/repo#N", strings.TrimSpace(r))
+		}
+		allow[strings.ToLower(fmt.Sprintf("%s/%s#%d", ref.Owner, ref.Repo, ref.Number))] = true
+	}
+	return allow, nil
+}
+
+// Allows reports whether owner/repo#num may be written to. Names are compared case-insensitively.
+func (a IssueAllowlist) Allows(owner, repo string, num int) bool {
+	return a == nil || a[strings.ToLower(fmt.Sprintf("%s/%s#%d", owner, repo, num))]
+}
+
+// FilterUpdates drops updates for issues that are not allowed
+func (a IssueAllowlist) FilterUpdates(updates []DateUpdate) []DateUpdate {
+	if a == nil {
+		return updates
+	}
+	return FilterUpdates(updates, func(u DateUpdate) bool { return a.Allows(u.Owner, u.Repo, u.IssueNum) })
+}
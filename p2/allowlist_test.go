@@ -0,0 +1,162 @@
+package p2
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRepoAllowlist_DropsDisallowedWritesButSchedulesAll(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/ours/app/issues/1": {
+			Owner:    "ours",
+			Repo:     "app",
+			IssueNum: 1,
+			State:    "open",
+			BlockedBy: []IssueRef{
+				{Owner: "theirs", Repo: "lib", Number: 7},
+			},
+		},
+		"github.com/theirs/lib/issues/7": {
+			Owner:    "theirs",
+			Repo:     "lib",
+			IssueNum: 7,
+			State:    "open",
+		},
+	}
+
+	// Scheduling still sees the other repo's issue as a dependency
+	tasks, _, _ := IssuesToTasks(issues, nil)
+	for _, task := range tasks {
+		if task.ID == "ours/app#1" && (len(task.DependsOn) != 1 || task.DependsOn[0] != "theirs/lib#7") {
+			t.Errorf("expected ours/app#1 to depend on theirs/lib#7, got %v", task.DependsOn)
+		}
+	}
+
+	allow, err := ParseRepoAllowlist([]string{"Ours/App"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updates := allow.FilterUpdates([]DateUpdate{
+		{Owner: "ours", Repo: "app", IssueNum: 1},
+		{Owner: "theirs", Repo: "lib", IssueNum: 7},
+	})
+	if len(updates) != 1 || updates[0].Repo != "app" {
+		t.Errorf("expected only the ours/app update, got %+v", updates)
+	}
+
+	schedIssues := allow.FilterSchedulingIssues([]SchedulingIssue{
+		{Owner: "ours", Repo: "app", IssueNum: 1, Reason: "at_risk"},
+		{Owner: "theirs", Repo: "lib", IssueNum: 7, Reason: "missing_estimate"},
+	})
+	if len(schedIssues) != 1 || schedIssues[0].Repo != "app" {
+		t.Errorf("expected only the ours/app comment, got %+v", schedIssues)
+	}
+}
+
+func TestParseRepoAllowlist(t *testing.T) {
+	allow, err := ParseRepoAllowlist(nil)
+	if err != nil || allow != nil || !allow.Allows("any", "repo") {
+		t.Error("expected an empty list to allow every repository")
+	}
+	if _, err := ParseRepoAllowlist([]string{"not-a-repo"}); err == nil {
+		t.Error("expected error for invalid repository")
+	}
+}
+
+func TestRepoAllowlist_FilterIssuesReportsOutsideDependencies(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/myorg/app/issues/1": {
+			Owner: "myorg", Repo: "app", IssueNum: 1, State: "open",
+			BlockedBy: []IssueRef{{Owner: "myorg", Repo: "infra", Number: 2, State: "open"}},
+		},
+		"github.com/myorg/infra/issues/2": {Owner: "myorg", Repo: "infra", IssueNum: 2, State: "open"},
+		"github.com/myorg/web/issues/3":   {Owner: "myorg", Repo: "web", IssueNum: 3, State: "open"},
+	}
+	allow, err := ParseRepoAllowlist([]string{"myorg/app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if excluded := allow.FilterIssues(issues); excluded != 2 {
+		t.Errorf("expected 2 excluded issues, got %d", excluded)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 remaining issue, got %d", len(issues))
+	}
+
+	_, _, schedIssues := IssuesToTasks(issues, nil)
+	var missing *SchedulingIssue
+	for i := range schedIssues {
+		if schedIssues[i].Reason == "missing_dependency" {
+			missing = &schedIssues[i]
+		}
+	}
+	if missing == nil || missing.Details[0] != "myorg/infra#2" {
+		t.Errorf("expected missing_dependency on myorg/infra#2, got %+v", schedIssues)
+	}
+}
+
+func TestFilterAssignee_KeepsOneQueueAndItsBlockers(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/myorg/app/issues/1": {
+			Owner: "myorg", Repo: "app", IssueNum: 1, State: "open", Assignee: "Alice",
+			BlockedBy: []IssueRef{{Owner: "myorg", Repo: "app", Number: 2, State: "open"}},
+		},
+		"github.com/myorg/app/issues/2": {
+			Owner: "myorg", Repo: "app", IssueNum: 2, State: "open", Assignee: "bob",
+			BlockedBy: []IssueRef{{Owner: "myorg", Repo: "app", Number: 4, State: "open"}},
+		},
+		"github.com/myorg/app/issues/3": {Owner: "myorg", Repo: "app", IssueNum: 3, State: "open"},
+		"github.com/myorg/app/issues/4": {Owner: "myorg", Repo: "app", IssueNum: 4, State: "open", Assignee: "carol"},
+		// alice is the second assignee
+		"github.com/myorg/app/issues/5": {Owner: "myorg", Repo: "app", IssueNum: 5, State: "open", Assignee: "dave"},
+		"github.com/myorg/app/issues/6": {Owner: "myorg", Repo: "app", IssueNum: 6, State: "open", Assignee: "dave"},
+	}
+	assignees := map[string][]string{"github.com/myorg/app/issues/5": {"dave", "alice"}}
+
+	excluded, blockers := FilterAssignee(issues, "alice", assignees)
+	if excluded != 2 || blockers != 2 {
+		t.Errorf("expected 2 excluded issues and 2 blockers, got %d and %d", excluded, blockers)
+	}
+	for _, num := range []int{1, 2, 4, 5} {
+		if _, ok := issues[fmt.Sprintf("github.com/myorg/app/issues/%d", num)]; !ok {
+			t.Errorf("expected myorg/app#%d to remain", num)
+		}
+	}
+	if len(issues) != 4 {
+		t.Fatalf("expected 4 issues to remain, got %v", issues)
+	}
+
+	// Blockers are scheduled as context rather than reported as missing
+	_, _, schedIssues := IssuesToTasks(issues, nil)
+	for _, si := range schedIssues {
+		if si.Reason == "missing_dependency" {
+			t.Errorf("expected no missing dependencies, got %+v", si)
+		}
+	}
+}
+
+func TestIssueAllowlist_FilterUpdates(t *testing.T) {
+	allow, err := ParseIssueAllowlist([]string{"MyOrg/App#1", " myorg/web#7"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updates := []DateUpdate{
+		{Owner: "myorg", Repo: "app", IssueNum: 1},
+		{Owner: "myorg", Repo: "app", IssueNum: 2},
+		{Owner: "myorg", Repo: "web", IssueNum: 7},
+	}
+	kept := allow.FilterUpdates(updates)
+	if len(kept) != 2 || kept[0].IssueNum != 1 || kept[1].IssueNum != 7 {
+		t.Errorf("expected myorg/app#1 and myorg/web#7 to be kept, got %+v", kept)
+	}
+
+	if _, err := ParseIssueAllowlist([]string{"myorg/app"}); err == nil {
+		t.Error("expected error for an entry without an issue number")
+	}
+	var all IssueAllowlist
+	if !all.Allows("any", "repo", 3) {
+		t.Error("expected a nil allowlist to allow every issue")
+	}
+}
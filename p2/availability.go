@@ -0,0 +1,162 @@
+package p2
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/octoberswimmer/p2/recfile"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkingHours is the number of hours a user is available on each weekday.
+// The planner schedules Monday through Friday only, so there are no weekend
+// fields.
+type WorkingHours struct {
+	Monday    float64 `yaml:"monday"`
+	Tuesday   float64 `yaml:"tuesday"`
+	Wednesday float64 `yaml:"wednesday"`
+	Thursday  float64 `yaml:"thursday"`
+	Friday    float64 `yaml:"friday"`
+}
+
+// DefaultWorkingHours is used for users with no configured availability.
+var DefaultWorkingHours = WorkingHours{
+	Monday:    8,
+	Tuesday:   8,
+	Wednesday: 8,
+	Thursday:  8,
+	Friday:    8,
+}
+
+// Availability configures working hours per user, with team-level defaults
+// for users that have no per-user entry.
+type Availability struct {
+	Users map[string]WorkingHours `yaml:"users"`
+	Teams map[string]WorkingHours `yaml:"teams"`
+
+	// Ramps limits users to a percentage of their hours over date ranges,
+	// e.g. while a new hire ramps up or someone winds down before leaving.
+	Ramps map[string][]RampWindow `yaml:"ramps"`
+
+	// Holidays are days with no availability, for everyone or for one user.
+	Holidays []Holiday `yaml:"holidays"`
+
+	// TeamMembers maps a team slug to its member logins.
+	// It is populated from GitHub rather than the config file.
+	TeamMembers map[string][]string `yaml:"-"`
+}
+
+// RampWindow limits a user to Percent of their working hours from From
+// through Until, inclusive.
+type RampWindow struct {
+	From    time.Time `yaml:"from"`
+	Until   time.Time `yaml:"until"`
+	Percent float64   `yaml:"percent"`
+}
+
+// LoadAvailability reads an availability config from a YAML or JSON file.
+func LoadAvailability(path string) (*Availability, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read availability file: %w", err)
+	}
+	var a Availability
+	if err := yaml.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("failed to parse availability file %s: %w", path, err)
+	}
+	return &a, nil
+}
+
+// HoursFor returns the working hours for login. Per-user entries take
+// precedence over team defaults; when a user is in several configured teams,
+// the first team in alphabetical order wins. Users with neither get
+// DefaultWorkingHours.
+func (a *Availability) HoursFor(login string) WorkingHours {
+	if a == nil {
+		return DefaultWorkingHours
+	}
+	if hours, ok := a.Users[login]; ok {
+		return hours
+	}
+
+	teams := make([]string, 0, len(a.Teams))
+	for team := range a.Teams {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+	for _, team := range teams {
+		for _, member := range a.TeamMembers[team] {
+			if member == login {
+				return a.Teams[team]
+			}
+		}
+	}
+
+	return DefaultWorkingHours
+}
+
+// User builds a recfile.User for login from the configured availability.
+func (a *Availability) User(login string) recfile.User {
+	hours := a.HoursFor(login)
+	return recfile.User{
+		ID:             login,
+		MondayHours:    hours.Monday,
+		TuesdayHours:   hours.Tuesday,
+		WednesdayHours: hours.Wednesday,
+		ThursdayHours:  hours.Thursday,
+		FridayHours:    hours.Friday,
+	}
+}
+
+// Knows reports whether login has configured hours, either directly or as a team member
+func (a *Availability) Knows(login string) bool {
+	if a == nil {
+		return false
+	}
+	if _, ok := a.Users[login]; ok {
+		return true
+	}
+	for team := range a.Teams {
+		for _, member := range a.TeamMembers[team] {
+			if member == login {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DetectUnknownAssignees flags open issues assigned to users missing from the
+// availability config, who would otherwise silently get DefaultWorkingHours.
+// It returns nothing when no config was provided.
+func DetectUnknownAssignees(issues map[string]IssueWithProject, a *Availability) []SchedulingIssue {
+	if a == nil || (len(a.Users) == 0 && len(a.Teams) == 0) {
+		return nil
+	}
+
+	refs := make([]string, 0, len(issues))
+	for ref := range issues {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	var unknown []SchedulingIssue
+	for _, ref := range refs {
+		iwp := issues[ref]
+		if iwp.Assignee == "" || iwp.IsDraft || strings.EqualFold(iwp.State, "closed") || a.Knows(iwp.Assignee) {
+			continue
+		}
+		unknown = append(unknown, SchedulingIssue{
+			IssueRef: ref,
+			IssueNum: iwp.IssueNum,
+			Owner:    iwp.Owner,
+			Repo:     iwp.Repo,
+			Reason:   "unknown_assignee",
+			Details:  []string{iwp.Assignee},
+		})
+	}
+	return unknown
+}
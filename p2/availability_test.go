@@ -0,0 +1,161 @@
+package p2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAvailability_TeamDefaultAppliesWithoutUserOverride(t *testing.T) {
+	contractor := WorkingHours{Monday: 4, Tuesday: 4, Wednesday: 4, Thursday: 4}
+	avail := &Availability{
+		Users: map[string]WorkingHours{
+			"bob": {Monday: 6, Tuesday: 6, Wednesday: 6, Thursday: 6, Friday: 6},
+		},
+		Teams: map[string]WorkingHours{
+			"contractors": contractor,
+		},
+		TeamMembers: map[string][]string{
+			"contractors": {"alice", "bob"},
+		},
+	}
+
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			Title:    "Contractor Task",
+			State:    "open",
+			Assignee: "alice",
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 2,
+			Title:    "Overridden Task",
+			State:    "open",
+			Assignee: "bob",
+		},
+		"github.com/owner/repo/issues/3": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 3,
+			Title:    "Employee Task",
+			State:    "open",
+			Assignee: "carol",
+		},
+	}
+
+	_, users, _ := IssuesToTasksWithOptions(issues, nil, ConvertOptions{Availability: avail})
+
+	byID := make(map[string]float64)
+	for _, u := range users {
+		byID[u.ID] = u.MondayHours + u.TuesdayHours + u.WednesdayHours + u.ThursdayHours + u.FridayHours
+	}
+
+	if byID["alice"] != 16 {
+		t.Errorf("expected alice to get contractor hours (16/week), got %.1f", byID["alice"])
+	}
+	if byID["bob"] != 30 {
+		t.Errorf("expected bob's per-user override (30/week), got %.1f", byID["bob"])
+	}
+	if byID["carol"] != 40 {
+		t.Errorf("expected carol to get default hours (40/week), got %.1f", byID["carol"])
+	}
+}
+
+func TestAvailability_UnassignedWorkKeepsSyntheticUser(t *testing.T) {
+	avail := &Availability{
+		Users: map[string]WorkingHours{
+			"parttime": {Monday: 4, Tuesday: 4, Wednesday: 4},
+		},
+	}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, State: "open", Assignee: "parttime"},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, State: "open"},
+	}
+
+	tasks, users, _ := IssuesToTasksWithOptions(issues, nil, ConvertOptions{Availability: avail})
+
+	byID := make(map[string]float64)
+	for _, u := range users {
+		byID[u.ID] = u.MondayHours + u.TuesdayHours + u.WednesdayHours + u.ThursdayHours + u.FridayHours
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected parttime and unassigned users, got %+v", users)
+	}
+	if byID["parttime"] != 12 {
+		t.Errorf("expected parttime to get configured hours (12/week), got %.1f", byID["parttime"])
+	}
+	if byID["unassigned"] != 40 {
+		t.Errorf("expected unassigned to get default hours (40/week), got %.1f", byID["unassigned"])
+	}
+	for _, task := range tasks {
+		if task.ID == "owner/repo#2" && task.User != "unassigned" {
+			t.Errorf("expected unassigned task to use the unassigned user, got %q", task.User)
+		}
+	}
+}
+
+func TestAvailability_NilUsesDefaults(t *testing.T) {
+	var avail *Availability
+	if hours := avail.HoursFor("anyone"); hours != DefaultWorkingHours {
+		t.Errorf("expected default hours, got %+v", hours)
+	}
+}
+
+func TestLoadAvailability(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.yml")
+	content := `users:
+  alice:
+    monday: 8
+    tuesday: 8
+    wednesday: 8
+    thursday: 8
+teams:
+  contractors:
+    monday: 4
+    friday: 4
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	avail, err := LoadAvailability(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if avail.Users["alice"].Thursday != 8 || avail.Users["alice"].Friday != 0 {
+		t.Errorf("unexpected hours for alice: %+v", avail.Users["alice"])
+	}
+	if avail.Teams["contractors"].Friday != 4 {
+		t.Errorf("unexpected hours for contractors: %+v", avail.Teams["contractors"])
+	}
+}
+
+func TestDetectUnknownAssignees(t *testing.T) {
+	availability := &Availability{
+		Users:       map[string]WorkingHours{"alice": DefaultWorkingHours},
+		Teams:       map[string]WorkingHours{"contractors": {Monday: 4}},
+		TeamMembers: map[string][]string{"contractors": {"bob"}},
+	}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, State: "open", Assignee: "alice"},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, State: "open", Assignee: "bob"},
+		"github.com/owner/repo/issues/3": {Owner: "owner", Repo: "repo", IssueNum: 3, State: "open", Assignee: "departed"},
+		"github.com/owner/repo/issues/4": {Owner: "owner", Repo: "repo", IssueNum: 4, State: "closed", Assignee: "departed"},
+	}
+
+	unknown := DetectUnknownAssignees(issues, availability)
+	if len(unknown) != 1 {
+		t.Fatalf("expected 1 unknown assignee, got %d: %+v", len(unknown), unknown)
+	}
+	if unknown[0].IssueNum != 3 || unknown[0].Reason != "unknown_assignee" || unknown[0].Details[0] != "departed" {
+		t.Errorf("expected #3 flagged for departed, got %+v", unknown[0])
+	}
+
+	if got := DetectUnknownAssignees(issues, nil); len(got) != 0 {
+		t.Errorf("expected no advisories without a config, got %+v", got)
+	}
+}
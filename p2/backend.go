@@ -0,0 +1,16 @@
+package p2
+
+// IssueFetcher fetches the issues to schedule from an issue tracker. Keys use
+// the "github.com/owner/repo/issues/N" form IssuesToTasks expects whatever the
+// tracker, and each issue needs a Project for PrepareUpdates to write it.
+type IssueFetcher interface {
+	FetchIssues() (map[string]IssueWithProject, error)
+}
+
+// UpdateApplier writes scheduled dates back to an issue tracker
+type UpdateApplier interface {
+	// NeedsUpdate reports whether applying update would change what the
+	// tracker stores, which may be fewer dates than a DateUpdate carries
+	NeedsUpdate(update DateUpdate) bool
+	ApplyUpdate(update DateUpdate) error
+}
@@ -0,0 +1,179 @@
+package p2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// Slip is an issue whose Expected Completion moved from the baseline
+type Slip struct {
+	Owner    string
+	Repo     string
+	IssueNum int
+	Name     string
+	Baseline time.Time
+	Current  time.Time
+	// Days is the move in working days: positive when later, negative when
+	// earlier (only reported by CompareBaselineMoves)
+	Days int
+}
+
+// PlanState holds a baseline for each project, keyed by the URL it was
+// scheduled from
+type PlanState map[string][]DateUpdate
+
+// CurrentSchedule returns a DateUpdate carrying the scheduled dates of every
+// open issue in ganttData, whether or not its dates changed. It is the format
+// written by SaveBaseline.
+func CurrentSchedule(ganttData planner.GanttData, issues map[string]IssueWithProject) []DateUpdate {
+	taskToIssue := make(map[string]IssueWithProject, len(issues))
+	for _, iwp := range issues {
+		taskToIssue[fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)] = iwp
+	}
+
+	var schedule []DateUpdate
+	for _, bar := range ganttData.Bars {
+		if bar.IsPackage || bar.Done || bar.OnHold || bar.MeanDate.IsZero() {
+			continue
+		}
+		iwp, ok := taskToIssue[bar.ID]
+		if !ok || strings.EqualFold(iwp.State, "closed") {
+			continue
+		}
+		schedule = append(schedule, DateUpdate{
+			Owner:              iwp.Owner,
+			Repo:               iwp.Repo,
+			RepoKey:            fmt.Sprintf("%s/%s", iwp.Owner, iwp.Repo),
+			IssueNum:           iwp.IssueNum,
+			Name:               iwp.Title,
+			ExpectedStart:      bar.ExpStartDate,
+			ExpectedCompletion: bar.MeanDate,
+			Completion98:       bar.End98Date,
+		})
+	}
+	SortUpdates(schedule)
+	return schedule
+}
+
+// LoadBaseline reads a baseline schedule written by SaveBaseline
+func LoadBaseline(path string) ([]DateUpdate, error) {
+	var baseline []DateUpdate
+	if err := loadBaselineJSON(path, &baseline); err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+// SaveBaseline writes schedule to path as JSON
+func SaveBaseline(path string, schedule []DateUpdate) error {
+	return saveBaselineJSON(path, schedule)
+}
+
+// LoadPlanState reads the per-project baselines written by SavePlanState.
+// A missing file yields an empty state.
+func LoadPlanState(path string) (PlanState, error) {
+	state := PlanState{}
+	if err := loadBaselineJSON(path, &state); errors.Is(err, os.ErrNotExist) {
+		return PlanState{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// SavePlanState writes state to path as JSON
+func SavePlanState(path string, state PlanState) error {
+	return saveBaselineJSON(path, state)
+}
+
+func loadBaselineJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read baseline: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return nil
+}
+
+func saveBaselineJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// CompareBaseline returns the issues whose current Expected Completion is
+// later than in baseline, largest slip first. Issues missing from either
+// schedule are not reported.
+func CompareBaseline(baseline, current []DateUpdate) []Slip {
+	return compareBaseline(baseline, current, false)
+}
+
+// CompareBaselineMoves is CompareBaseline that also reports issues whose
+// Expected Completion moved earlier, with negative Days, largest move first
+func CompareBaselineMoves(baseline, current []DateUpdate) []Slip {
+	return compareBaseline(baseline, current, true)
+}
+
+func compareBaseline(baseline, current []DateUpdate, earlier bool) []Slip {
+	planned := make(map[string]DateUpdate, len(baseline))
+	for _, u := range baseline {
+		planned[fmt.Sprintf("%s/%s#%d", u.Owner, u.Repo, u.IssueNum)] = u
+	}
+
+	var slips []Slip
+	for _, u := range current {
+		b, ok := planned[fmt.Sprintf("%s/%s#%d", u.Owner, u.Repo, u.IssueNum)]
+		if !ok || b.ExpectedCompletion.IsZero() || u.ExpectedCompletion.IsZero() {
+			continue
+		}
+		before, after := truncateDay(b.ExpectedCompletion), truncateDay(u.ExpectedCompletion)
+		days := workingDaysBetween(before, after) - workingDaysBetween(after, before)
+		if days == 0 || (days < 0 && !earlier) {
+			continue
+		}
+		slips = append(slips, Slip{
+			Owner:    u.Owner,
+			Repo:     u.Repo,
+			IssueNum: u.IssueNum,
+			Name:     u.Name,
+			Baseline: b.ExpectedCompletion,
+			Current:  u.ExpectedCompletion,
+			Days:     days,
+		})
+	}
+
+	sort.SliceStable(slips, func(i, j int) bool {
+		if a, b := abs(slips[i].Days), abs(slips[j].Days); a != b {
+			return a > b
+		}
+		if slips[i].Owner != slips[j].Owner {
+			return slips[i].Owner < slips[j].Owner
+		}
+		if slips[i].Repo != slips[j].Repo {
+			return slips[i].Repo < slips[j].Repo
+		}
+		return slips[i].IssueNum < slips[j].IssueNum
+	})
+	return slips
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
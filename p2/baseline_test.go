@@ -0,0 +1,108 @@
+package p2
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestCompareBaseline_ReportsSlippedIssues(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, Title: "On track", State: "open"},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, Title: "Slipped", State: "open"},
+		"github.com/owner/repo/issues/3": {Owner: "owner", Repo: "repo", IssueNum: 3, Title: "Slipped more", State: "open"},
+		"github.com/owner/repo/issues/4": {Owner: "owner", Repo: "repo", IssueNum: 4, Title: "Ahead", State: "open"},
+		"github.com/owner/repo/issues/5": {Owner: "owner", Repo: "repo", IssueNum: 5, Title: "New", State: "open"},
+	}
+
+	baselineData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: day(3), MeanDate: day(5), End98Date: day(7)},
+		{ID: "owner/repo#2", ExpStartDate: day(3), MeanDate: day(6), End98Date: day(7)},
+		{ID: "owner/repo#3", ExpStartDate: day(3), MeanDate: day(7), End98Date: day(10)},
+		{ID: "owner/repo#4", ExpStartDate: day(3), MeanDate: day(12), End98Date: day(14)},
+	}}
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := SaveBaseline(path, CurrentSchedule(baselineData, issues)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	currentData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: day(3), MeanDate: day(5), End98Date: day(7)},
+		{ID: "owner/repo#2", ExpStartDate: day(3), MeanDate: day(10), End98Date: day(11)},
+		{ID: "owner/repo#3", ExpStartDate: day(3), MeanDate: day(14), End98Date: day(17)},
+		{ID: "owner/repo#4", ExpStartDate: day(3), MeanDate: day(10), End98Date: day(11)},
+		{ID: "owner/repo#5", ExpStartDate: day(3), MeanDate: day(20), End98Date: day(21)},
+	}}
+
+	slips := CompareBaseline(baseline, CurrentSchedule(currentData, issues))
+
+	if len(slips) != 2 {
+		t.Fatalf("expected 2 slipped issues, got %d: %+v", len(slips), slips)
+	}
+	if s := slips[0]; s.IssueNum != 3 || s.Days != 5 || !s.Baseline.Equal(day(7)) || !s.Current.Equal(day(14)) {
+		t.Errorf("expected #3 to slip 5 working days from 2025-03-07 to 2025-03-14, got %+v", s)
+	}
+	if s := slips[1]; s.IssueNum != 2 || s.Days != 2 || s.Name != "Slipped" {
+		t.Errorf("expected #2 to slip 2 working days, got %+v", s)
+	}
+}
+
+func TestCompareBaselineMoves_EarlierAndLater(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	previous := []DateUpdate{
+		{Owner: "myorg", Repo: "app", IssueNum: 1, ExpectedCompletion: day(2)},
+		{Owner: "myorg", Repo: "app", IssueNum: 2, ExpectedCompletion: day(10)},
+		{Owner: "myorg", Repo: "app", IssueNum: 3, ExpectedCompletion: day(4)},
+	}
+	current := []DateUpdate{
+		{Owner: "myorg", Repo: "app", IssueNum: 1, Name: "Downstream", ExpectedCompletion: day(9)},
+		{Owner: "myorg", Repo: "app", IssueNum: 2, Name: "Pulled in", ExpectedCompletion: day(9)},
+		{Owner: "myorg", Repo: "app", IssueNum: 3, ExpectedCompletion: day(4)},
+		{Owner: "myorg", Repo: "app", IssueNum: 4, ExpectedCompletion: day(5)},
+	}
+
+	drifts := CompareBaselineMoves(previous, current)
+	if len(drifts) != 2 {
+		t.Fatalf("expected 2 moved issues, got %+v", drifts)
+	}
+	if d := drifts[0]; d.IssueNum != 1 || d.Days != 5 {
+		t.Errorf("expected #1 to move 5 working days later first, got %+v", d)
+	}
+	if d := drifts[1]; d.IssueNum != 2 || d.Days != -1 {
+		t.Errorf("expected #2 to move 1 working day earlier, got %+v", d)
+	}
+}
+
+func TestPlanState_RoundTripKeyedByProject(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans.json")
+	state, err := LoadPlanState(path)
+	if err != nil || len(state) != 0 {
+		t.Fatalf("expected a missing file to give an empty state, got %v, %v", state, err)
+	}
+
+	completion := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	state["https://github.com/orgs/myorg/projects/1"] = []DateUpdate{{Owner: "myorg", Repo: "app", IssueNum: 1, ExpectedCompletion: completion}}
+	state["https://github.com/orgs/myorg/projects/2"] = nil
+	if err := SavePlanState(path, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := LoadPlanState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plan, ok := loaded["https://github.com/orgs/myorg/projects/1"]
+	if !ok || len(plan) != 1 || !plan[0].ExpectedCompletion.Equal(completion) {
+		t.Errorf("expected project 1's plan to round-trip, got %+v", loaded)
+	}
+	if _, ok := loaded["https://github.com/orgs/myorg/projects/2"]; !ok {
+		t.Error("expected project 2 to keep its entry")
+	}
+}
@@ -0,0 +1,81 @@
+package p2
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// BurndownPoint is the forecast number of issues completed in, and by the end of, one week
+type BurndownPoint struct {
+	// Week is the Monday the week starts on
+	Week       time.Time `json:"week"`
+	Completed  int       `json:"completed"`
+	Cumulative int       `json:"cumulative"`
+}
+
+// Burndown buckets the 98% Completion dates of updates by week and returns
+// the cumulative forecast, one point per week from the first to the last
+// completion (weeks with no completions included). Updates that clear dates
+// are ignored.
+func Burndown(updates []DateUpdate) []BurndownPoint {
+	counts := make(map[time.Time]int)
+	for _, u := range updates {
+		if u.ClearDates || u.Completion98.IsZero() {
+			continue
+		}
+		counts[weekStart(u.Completion98)]++
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	weeks := make([]time.Time, 0, len(counts))
+	for w := range counts {
+		weeks = append(weeks, w)
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Before(weeks[j]) })
+
+	var points []BurndownPoint
+	cumulative := 0
+	for w := weeks[0]; !w.After(weeks[len(weeks)-1]); w = w.AddDate(0, 0, 7) {
+		cumulative += counts[w]
+		points = append(points, BurndownPoint{Week: w, Completed: counts[w], Cumulative: cumulative})
+	}
+	return points
+}
+
+// weekStart returns midnight UTC on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// WriteBurndownCSV writes points as CSV with a header line
+func WriteBurndownCSV(w io.Writer, points []BurndownPoint) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Week", "Completed", "Cumulative"}); err != nil {
+		return err
+	}
+	for _, p := range points {
+		if err := cw.Write([]string{formatDate(p.Week), strconv.Itoa(p.Completed), strconv.Itoa(p.Cumulative)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteBurndownJSON writes points as a JSON array
+func WriteBurndownJSON(w io.Writer, points []BurndownPoint) error {
+	if points == nil {
+		points = []BurndownPoint{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(points)
+}
@@ -0,0 +1,50 @@
+package p2
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestBurndown_WeeklyCumulativeCounts(t *testing.T) {
+	date := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	updates := []DateUpdate{
+		{IssueNum: 1, Completion98: date(time.February, 3)},  // Tue, week of Feb 2
+		{IssueNum: 2, Completion98: date(time.February, 6)},  // Fri, week of Feb 2
+		{IssueNum: 3, Completion98: date(time.February, 9)},  // Mon, week of Feb 9
+		{IssueNum: 4, Completion98: date(time.February, 26)}, // Thu, week of Feb 23
+		{IssueNum: 5, ClearDates: true},
+	}
+
+	points := Burndown(updates)
+
+	want := []struct {
+		week       time.Time
+		completed  int
+		cumulative int
+	}{
+		{date(time.February, 2), 2, 2},
+		{date(time.February, 9), 1, 3},
+		{date(time.February, 16), 0, 3},
+		{date(time.February, 23), 1, 4},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("expected %d weeks, got %d: %+v", len(want), len(points), points)
+	}
+	for i, w := range want {
+		p := points[i]
+		if !p.Week.Equal(w.week) || p.Completed != w.completed || p.Cumulative != w.cumulative {
+			t.Errorf("week %d: expected %s completed=%d cumulative=%d, got %s completed=%d cumulative=%d",
+				i, w.week.Format("2006-01-02"), w.completed, w.cumulative, p.Week.Format("2006-01-02"), p.Completed, p.Cumulative)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBurndownCSV(&buf, points); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantCSV := "Week,Completed,Cumulative\n2026-02-02,2,2\n2026-02-09,1,3\n2026-02-16,0,3\n2026-02-23,1,4\n"
+	if buf.String() != wantCSV {
+		t.Errorf("unexpected CSV:\n got: %q\nwant: %q", buf.String(), wantCSV)
+	}
+}
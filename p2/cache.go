@@ -0,0 +1,59 @@
+package p2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// issueCache is the on-disk format of a cached fetch
+type issueCache struct {
+	FetchedAt time.Time                   `json:"fetched_at"`
+	Issues    map[string]IssueWithProject `json:"issues"`
+}
+
+// cachePath returns the cache file for a GitHub URL
+func cachePath(dir, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "issues-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// SaveIssueCache writes fetched issues for url to dir, recording fetchedAt.
+func SaveIssueCache(dir, url string, issues map[string]IssueWithProject, fetchedAt time.Time) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(issueCache{FetchedAt: fetchedAt, Issues: issues})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := os.WriteFile(cachePath(dir, url), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// LoadIssueCache reads cached issues for url from dir. If maxAge is positive
+// and the cache was fetched more than maxAge before now, an error describing
+// how stale it is is returned. A missing cache returns an error wrapping
+// os.ErrNotExist.
+func LoadIssueCache(dir, url string, maxAge time.Duration, now time.Time) (map[string]IssueWithProject, time.Time, error) {
+	data, err := os.ReadFile(cachePath(dir, url))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read cache: %w", err)
+	}
+	var cache issueCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse cache: %w", err)
+	}
+	age := now.Sub(cache.FetchedAt)
+	if maxAge > 0 && age > maxAge {
+		return nil, cache.FetchedAt, fmt.Errorf("cached data is %s old (fetched %s), exceeding --cache-max-age %s; re-run with --refresh",
+			age.Round(time.Minute), cache.FetchedAt.Format(time.RFC3339), maxAge)
+	}
+	return cache.Issues, cache.FetchedAt, nil
+}
@@ -0,0 +1,58 @@
+package p2
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLoadIssueCache_FreshCacheLoads(t *testing.T) {
+	dir := t.TempDir()
+	fetchedAt := time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC)
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			Title:    "Cached Task",
+			State:    "open",
+		},
+	}
+
+	if err := SaveIssueCache(dir, "owner/repo", issues, fetchedAt); err != nil {
+		t.Fatalf("unexpected error saving cache: %v", err)
+	}
+
+	loaded, gotFetchedAt, err := LoadIssueCache(dir, "owner/repo", time.Hour, fetchedAt.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("expected fresh cache to load, got: %v", err)
+	}
+	if !gotFetchedAt.Equal(fetchedAt) {
+		t.Errorf("expected fetch time %s, got %s", fetchedAt, gotFetchedAt)
+	}
+	if loaded["github.com/owner/repo/issues/1"].Title != "Cached Task" {
+		t.Errorf("expected cached issue to round-trip, got %+v", loaded)
+	}
+}
+
+func TestLoadIssueCache_StaleCacheReturnsError(t *testing.T) {
+	dir := t.TempDir()
+	fetchedAt := time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC)
+
+	if err := SaveIssueCache(dir, "owner/repo", map[string]IssueWithProject{}, fetchedAt); err != nil {
+		t.Fatalf("unexpected error saving cache: %v", err)
+	}
+
+	_, _, err := LoadIssueCache(dir, "owner/repo", 24*time.Hour, fetchedAt.Add(7*24*time.Hour))
+	if err == nil {
+		t.Fatal("expected error loading cache older than max age")
+	}
+}
+
+func TestLoadIssueCache_MissingCache(t *testing.T) {
+	_, _, err := LoadIssueCache(t.TempDir(), "owner/repo", time.Hour, time.Now())
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist for missing cache, got %v", err)
+	}
+}
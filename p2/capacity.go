@@ -0,0 +1,86 @@
+package p2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// personDayHours is the number of estimate hours in a person-day of milestone capacity
+const personDayHours = 8
+
+// ParseMilestoneCapacities converts "milestone=person-days" values, as given
+// to --milestone-capacity, to capacities keyed by milestone title
+func ParseMilestoneCapacities(values map[string]string) (map[string]float64, error) {
+	capacities := make(map[string]float64, len(values))
+	for milestone, value := range values {
+		days, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid capacity %q for milestone %q: expected a positive number of person-days", value, milestone)
+		}
+		capacities[milestone] = days
+	}
+	return capacities, nil
+}
+
+// DetectMilestoneCapacity flags milestones whose open tasks' summed High
+// Estimate exceeds their capacity in person-days. Tasks are counted in
+// scheduling order, and those that take the milestone past its capacity are
+// reported with reason "overcommitted_milestone". Tasks aren't reordered.
+func DetectMilestoneCapacity(tasks []planner.Task, issues map[string]IssueWithProject, capacities map[string]float64) []SchedulingIssue {
+	if len(capacities) == 0 {
+		return nil
+	}
+	refs := make(map[string]string, len(issues))
+	for ref, iwp := range issues {
+		refs[fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)] = ref
+	}
+
+	load := make(map[string]float64)
+	over := make(map[string][]planner.Task)
+	for _, t := range tasks {
+		capacity, ok := capacities[t.PackageID]
+		if !ok || t.Done || t.OnHold {
+			continue
+		}
+		load[t.PackageID] += t.EstimateHigh
+		if load[t.PackageID] > capacity*personDayHours {
+			over[t.PackageID] = append(over[t.PackageID], t)
+		}
+	}
+
+	milestones := make([]string, 0, len(over))
+	for milestone := range over {
+		milestones = append(milestones, milestone)
+	}
+	sort.Strings(milestones)
+
+	var overcommitted []SchedulingIssue
+	for _, milestone := range milestones {
+		days := load[milestone] / personDayHours
+		capacity := capacities[milestone]
+		details := []string{
+			fmt.Sprintf("Milestone: %s (capacity %g person-days)", milestone, capacity),
+			fmt.Sprintf("Scheduled: %g person-days (%g over)", days, days-capacity),
+		}
+		for _, t := range over[milestone] {
+			ref, ok := refs[t.ID]
+			if !ok {
+				continue
+			}
+			iwp := issues[ref]
+			overcommitted = append(overcommitted, SchedulingIssue{
+				IssueRef: ref,
+				IssueNum: iwp.IssueNum,
+				Owner:    iwp.Owner,
+				Repo:     iwp.Repo,
+				Reason:   "overcommitted_milestone",
+				Details:  details,
+			})
+		}
+	}
+	return overcommitted
+}
@@ -0,0 +1,48 @@
+package p2
+
+import (
+	"testing"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestDetectMilestoneCapacity_FlagsTasksPastCapacity(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, Milestone: "v1.0"},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, Milestone: "v1.0"},
+		"github.com/owner/repo/issues/3": {Owner: "owner", Repo: "repo", IssueNum: 3, Milestone: "v1.0"},
+		"github.com/owner/repo/issues/4": {Owner: "owner", Repo: "repo", IssueNum: 4, Milestone: "v2.0"},
+	}
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", PackageID: "v1.0", EstimateHigh: 8},
+		{ID: "owner/repo#2", PackageID: "v1.0", EstimateHigh: 12, Done: true},
+		{ID: "owner/repo#3", PackageID: "v1.0", EstimateHigh: 12},
+		{ID: "owner/repo#4", PackageID: "v2.0", EstimateHigh: 40},
+	}
+
+	got := DetectMilestoneCapacity(tasks, issues, map[string]float64{"v1.0": 2})
+	if len(got) != 1 {
+		t.Fatalf("expected 1 overcommitted issue, got %+v", got)
+	}
+	si := got[0]
+	if si.IssueNum != 3 || si.Reason != "overcommitted_milestone" {
+		t.Errorf("expected #3 to be overcommitted_milestone, got %+v", si)
+	}
+	want := []string{
+		"Milestone: v1.0 (capacity 2 person-days)",
+		"Scheduled: 2.5 person-days (0.5 over)",
+	}
+	if len(si.Details) != len(want) || si.Details[0] != want[0] || si.Details[1] != want[1] {
+		t.Errorf("expected details %v, got %v", want, si.Details)
+	}
+}
+
+func TestParseMilestoneCapacities_RejectsNonPositive(t *testing.T) {
+	if _, err := ParseMilestoneCapacities(map[string]string{"v1.0": "0"}); err == nil {
+		t.Error("expected error for zero capacity")
+	}
+	caps, err := ParseMilestoneCapacities(map[string]string{"v1.0": " 7.5"})
+	if err != nil || caps["v1.0"] != 7.5 {
+		t.Errorf("expected v1.0=7.5, got %v (%v)", caps, err)
+	}
+}
@@ -0,0 +1,88 @@
+package p2
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigValue is a flag setting read from a config file
+type ConfigValue struct {
+	Key   string
+	Value string
+}
+
+// LoadConfigValues reads the flag settings from a config file such as
+// .p2scheduler.yml. Every top-level key other than "fields" names a flag:
+//
+//	base-date: 2025-01-06
+//	default-low: 2
+//	repo-filter: [owner/api, owner/web]
+//	status-behavior:
+//	  Blocked: hold
+//
+// Lists are joined with commas and maps become key=value pairs, the forms the
+// corresponding flags accept. Values are returned sorted by key. A missing
+// file yields no values.
+func LoadConfigValues(path string) ([]ConfigValue, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	var values []ConfigValue
+	for key, node := range raw {
+		if key == "fields" {
+			continue
+		}
+		value, err := configValueString(&node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q in %s: %w", key, path, err)
+		}
+		values = append(values, ConfigValue{Key: key, Value: value})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	return values, nil
+}
+
+// configValueString formats a YAML value as a flag value, keeping scalars as
+// written so dates and numbers aren't reformatted
+func configValueString(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value, nil
+	case yaml.SequenceNode:
+		parts := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", fmt.Errorf("nested lists and maps are not supported")
+			}
+			parts = append(parts, item.Value)
+		}
+		return strings.Join(parts, ","), nil
+	case yaml.MappingNode:
+		var parts []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if v.Kind != yaml.ScalarNode {
+				return "", fmt.Errorf("nested lists and maps are not supported")
+			}
+			parts = append(parts, k.Value+"="+v.Value)
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value")
+	}
+}
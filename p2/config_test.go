@@ -0,0 +1,52 @@
+package p2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigValues_FlattensValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".p2scheduler.yml")
+	content := `fields:
+  low-estimate: Est. Low
+base-date: 2025-01-06
+default-low: 2
+repo-filter: [owner/api, owner/web]
+status-behavior:
+  Blocked: hold
+  Waiting: hold
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := LoadConfigValues(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []ConfigValue{
+		{Key: "base-date", Value: "2025-01-06"},
+		{Key: "default-low", Value: "2"},
+		{Key: "repo-filter", Value: "owner/api,owner/web"},
+		{Key: "status-behavior", Value: "Blocked=hold,Waiting=hold"},
+	}
+	if len(values) != len(want) {
+		t.Fatalf("expected %v, got %v", want, values)
+	}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("value %d: expected %+v, got %+v", i, want[i], values[i])
+		}
+	}
+}
+
+func TestLoadConfigValues_MissingFile(t *testing.T) {
+	values, err := LoadConfigValues(filepath.Join(t.TempDir(), "missing.yml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(values) != 0 {
+		t.Errorf("expected no values, got %v", values)
+	}
+}
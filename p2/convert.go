@@ -73,39 +73,16 @@ type packageInfo struct {
 	firstOrder int
 }
 
-// IssuesToTasks converts GitHub issues to planner tasks.
-// If privacy is non-nil, private repo information is redacted in log output.
-func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) ([]planner.Task, []recfile.User, []SchedulingIssue) {
-	gen := lseq.NewGenerator("scheduler")
-	userSet := make(map[string]bool)
-	var tasks []planner.Task
-	var schedIssues []SchedulingIssue
-
-	// Build a set of on-hold issues for dependency checking
-	onHoldIssues := make(map[string]bool)
-	for ref, iwp := range issues {
-		if iwp.SchedulingStatus == "On Hold" || iwp.IsDraft {
-			onHoldIssues[ref] = true
-		}
-	}
-
-	// Convert map to slice and sort by order to preserve GitHub Project ordering
-	type refIssue struct {
-		ref string
-		iwp IssueWithProject
-	}
-	sortedIssues := make([]refIssue, 0, len(issues))
-	for ref, iwp := range issues {
-		sortedIssues = append(sortedIssues, refIssue{ref: ref, iwp: iwp})
-	}
-	sort.Slice(sortedIssues, func(i, j int) bool {
-		return sortedIssues[i].iwp.Order < sortedIssues[j].iwp.Order
-	})
-
-	// Build package ordering: due date (earliest first), then semver if present, then project order.
+// orderPackages returns the milestone packages of sortedIssues (in project
+// order) ordered by due date (earliest first), then semver if present, then
+// the position of each package's first issue. Packages with a due date come
+// before those without, and all packages come before unmilestoned work, so
+// this ordering is the only way a due date affects the schedule: the planner
+// has no deadline constraint, and late work is reported as at risk instead.
+func orderPackages(sortedIssues []IssueWithProject) []*packageInfo {
 	packageInfos := make(map[string]*packageInfo)
-	for i, ri := range sortedIssues {
-		pkgID := ri.iwp.Milestone
+	for i, iwp := range sortedIssues {
+		pkgID := iwp.Milestone
 		if pkgID == "" {
 			continue
 		}
@@ -120,10 +97,10 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 			packageInfos[pkgID] = info
 		}
 
-		if ri.iwp.MilestoneDueDate != nil {
-			if !info.hasDueDate || ri.iwp.MilestoneDueDate.Before(info.dueDate) {
+		if iwp.MilestoneDueDate != nil {
+			if !info.hasDueDate || iwp.MilestoneDueDate.Before(info.dueDate) {
 				info.hasDueDate = true
-				info.dueDate = *ri.iwp.MilestoneDueDate
+				info.dueDate = *iwp.MilestoneDueDate
 			}
 		}
 	}
@@ -152,6 +129,231 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 		}
 		return a.firstOrder < b.firstOrder
 	})
+	return orderedPackages
+}
+
+// taskID returns the planner task ID for an issue: "draft:<project item ID>"
+// for draft issues, otherwise "owner/repo#N"
+func taskID(iwp IssueWithProject) string {
+	if iwp.IsDraft {
+		return fmt.Sprintf("draft:%s", iwp.ProjectItemID)
+	}
+	return fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
+}
+
+// onHoldGraph records, for each converted issue, the in-project blockers it
+// depends on and the first on-hold blocker it was cut off from
+type onHoldGraph struct {
+	taskIDs    map[string]string
+	blockers   map[string][]string
+	directHold map[string]string
+}
+
+// path returns the chain of task IDs from an on-hold issue to ref, following
+// unsatisfied dependencies, or nil if no on-hold issue is upstream of ref
+func (g onHoldGraph) path(ref string, visiting map[string]bool) []string {
+	if held, ok := g.directHold[ref]; ok {
+		return []string{held, g.taskIDs[ref]}
+	}
+	if visiting[ref] {
+		return nil
+	}
+	visiting[ref] = true
+	for _, blocker := range g.blockers[ref] {
+		if p := g.path(blocker, visiting); p != nil {
+			return append(p, g.taskIDs[ref])
+		}
+	}
+	return nil
+}
+
+// StatusBehavior is how IssuesToTasks treats issues with a given Scheduling Status
+type StatusBehavior string
+
+const (
+	// StatusHold treats the issue as on hold: it is not scheduled and
+	// dependents report an onhold_dependency.
+	StatusHold StatusBehavior = "hold"
+	// StatusSchedule schedules the issue normally.
+	StatusSchedule StatusBehavior = "schedule"
+	// StatusRequireEstimate schedules the issue but always reports a
+	// missing_estimate, even when both estimates are set.
+	StatusRequireEstimate StatusBehavior = "require-estimate"
+	// StatusFreeze schedules the issue normally, so dependents account for
+	// it, but never writes or clears its fields.
+	StatusFreeze StatusBehavior = "freeze"
+)
+
+// ParseStatusBehaviors validates a map of status value to behavior name
+func ParseStatusBehaviors(m map[string]string) (map[string]StatusBehavior, error) {
+	behaviors := make(map[string]StatusBehavior, len(m))
+	for status, name := range m {
+		switch b := StatusBehavior(strings.ToLower(strings.TrimSpace(name))); b {
+		case StatusHold, StatusSchedule, StatusRequireEstimate, StatusFreeze:
+			behaviors[status] = b
+		default:
+			return nil, fmt.Errorf("invalid behavior %q for status %q (expected hold, schedule, require-estimate, or freeze)", name, status)
+		}
+	}
+	return behaviors, nil
+}
+
+// ConvertOptions controls optional behavior of IssuesToTasksWithOptions.
+// The zero value matches IssuesToTasks.
+type ConvertOptions struct {
+	// SkipUnassigned omits unassigned issues from the task list instead of
+	// scheduling them against a synthetic "unassigned" user.
+	SkipUnassigned bool
+
+	// Availability supplies per-user and per-team working hours.
+	// Users without configured hours get DefaultWorkingHours.
+	Availability *Availability
+
+	// StatusBehaviors maps Scheduling Status values to behaviors.
+	// OnHoldStatuses are treated as StatusHold unless overridden here.
+	StatusBehaviors map[string]StatusBehavior
+
+	// OnHoldStatuses are the Scheduling Status values that mean on hold.
+	// Nil means DefaultOnHoldStatuses.
+	OnHoldStatuses []string
+
+	// EstimateMultiplier scales every task's estimates for what-if planning.
+	// Zero is treated as 1. Project fields are not changed.
+	EstimateMultiplier float64
+
+	// GroupUnmilestoned assigns issues without a milestone to the synthetic
+	// NoMilestonePackage, ordered after all real milestones.
+	GroupUnmilestoned bool
+
+	// DefaultEstimateLow and DefaultEstimateHigh are applied to open issues
+	// with neither estimate set. Zero uses the package defaults (1-4).
+	// Such issues are still reported as missing_estimate.
+	DefaultEstimateLow  float64
+	DefaultEstimateHigh float64
+
+	// Assignees lists every assignee of issues with more than one, keyed like
+	// the issues map. IssueWithProject only carries the first.
+	Assignees map[string][]string
+
+	// RoundRobinAssignees schedules each open multi-assignee issue against its
+	// assignees in turn, instead of always against the first.
+	RoundRobinAssignees bool
+
+	// KeepEstimatesOnClose leaves the estimates of closed issues in place,
+	// so only their dates are cleared.
+	KeepEstimatesOnClose bool
+
+	// Privacy redacts private owners and repos in the log lines of
+	// PrepareUpdatesWithOptions. Nil redacts nothing.
+	Privacy *PrivacyFilter
+}
+
+// defaultEstimate returns the estimate applied to unestimated open issues
+func (opts ConvertOptions) defaultEstimate() (low, high float64) {
+	low, high = DefaultEstimateLow, DefaultEstimateHigh
+	if opts.DefaultEstimateLow != 0 {
+		low = opts.DefaultEstimateLow
+	}
+	if opts.DefaultEstimateHigh != 0 {
+		high = opts.DefaultEstimateHigh
+	}
+	return low, high
+}
+
+// NoMilestonePackage is the synthetic package ID used by GroupUnmilestoned
+const NoMilestonePackage = "(no milestone)"
+
+// IsHeld reports whether issues with a Scheduling Status of status are treated as on hold
+func (opts ConvertOptions) IsHeld(status string) bool {
+	return opts.statusBehavior(status) == StatusHold
+}
+
+// IsFrozen reports whether issues with a Scheduling Status of status must not have their fields written
+func (opts ConvertOptions) IsFrozen(status string) bool {
+	return opts.statusBehavior(status) == StatusFreeze
+}
+
+// DefaultOnHoldStatuses are the Scheduling Status values treated as on hold
+// when ConvertOptions.OnHoldStatuses is nil
+var DefaultOnHoldStatuses = []string{"On Hold"}
+
+// statusBehavior returns the configured behavior for a Scheduling Status value
+func (opts ConvertOptions) statusBehavior(status string) StatusBehavior {
+	if b, ok := opts.StatusBehaviors[status]; ok {
+		return b
+	}
+	onHold := opts.OnHoldStatuses
+	if onHold == nil {
+		onHold = DefaultOnHoldStatuses
+	}
+	for _, s := range onHold {
+		if status == s {
+			return StatusHold
+		}
+	}
+	return StatusSchedule
+}
+
+// IssuesToTasks converts GitHub issues to planner tasks.
+// If privacy is non-nil, private repo information is redacted in log output.
+func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) ([]planner.Task, []recfile.User, []SchedulingIssue) {
+	return IssuesToTasksWithOptions(issues, privacy, ConvertOptions{})
+}
+
+// IssuesToTasksWithOptions converts GitHub issues to planner tasks using opts.
+// If privacy is non-nil, private repo information is redacted in log output.
+func IssuesToTasksWithOptions(issues map[string]IssueWithProject, privacy *PrivacyFilter, opts ConvertOptions) ([]planner.Task, []recfile.User, []SchedulingIssue) {
+	gen := lseq.NewGenerator("scheduler")
+	userSet := make(map[string]bool)
+	rotation := make(map[string]int)
+	var tasks []planner.Task
+	var schedIssues []SchedulingIssue
+
+	// Dependency edges kept for transitive on-hold detection
+	holdGraph := onHoldGraph{
+		taskIDs:    make(map[string]string),
+		blockers:   make(map[string][]string),
+		directHold: make(map[string]string),
+	}
+
+	// Build a set of on-hold issues for dependency checking
+	onHoldIssues := make(map[string]bool)
+	for ref, iwp := range issues {
+		if opts.statusBehavior(iwp.SchedulingStatus) == StatusHold || iwp.IsDraft {
+			onHoldIssues[ref] = true
+		}
+	}
+
+	// Build a set of unassigned issues that will not be scheduled
+	skippedIssues := make(map[string]bool)
+	if opts.SkipUnassigned {
+		for ref, iwp := range issues {
+			if iwp.Assignee == "" {
+				skippedIssues[ref] = true
+			}
+		}
+	}
+
+	// Convert map to slice and sort by order to preserve GitHub Project ordering
+	type refIssue struct {
+		ref string
+		iwp IssueWithProject
+	}
+	sortedIssues := make([]refIssue, 0, len(issues))
+	for ref, iwp := range issues {
+		sortedIssues = append(sortedIssues, refIssue{ref: ref, iwp: iwp})
+	}
+	sort.Slice(sortedIssues, func(i, j int) bool {
+		return orderLess(sortedIssues[i].iwp, sortedIssues[j].iwp)
+	})
+
+	// Build package ordering: due date (earliest first), then semver if present, then project order.
+	sortedIWPs := make([]IssueWithProject, len(sortedIssues))
+	for i, ri := range sortedIssues {
+		sortedIWPs[i] = ri.iwp
+	}
+	logDuplicateOrders(sortedIWPs)
+	orderedPackages := orderPackages(sortedIWPs)
 
 	packageOrder := make(map[string]int, len(orderedPackages))
 	for i, info := range orderedPackages {
@@ -163,16 +365,23 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 		ref := ri.ref
 		iwp := ri.iwp
 
-		// Determine task ID based on whether it's a draft or regular issue
-		var taskID string
-		if iwp.IsDraft {
-			taskID = fmt.Sprintf("draft:%s", iwp.ProjectItemID)
-		} else {
-			taskID = fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
+		// Unassigned issues are not scheduled when SkipUnassigned is set
+		if skippedIssues[ref] {
+			if !onHoldIssues[ref] && !strings.EqualFold(iwp.State, "closed") {
+				schedIssues = append(schedIssues, SchedulingIssue{
+					IssueRef: ref,
+					IssueNum: iwp.IssueNum,
+					Owner:    iwp.Owner,
+					Repo:     iwp.Repo,
+					Reason:   "no_assignee",
+				})
+			}
+			logrus.WithFields(privacy.LogFields(iwp.Owner, iwp.Repo, iwp.IssueNum)).Debug("Skipping unassigned issue")
+			continue
 		}
 
 		task := planner.Task{
-			ID:       taskID,
+			ID:       taskID(iwp),
 			Sequence: lseq.SequentialString(i, "scheduler"),
 			Name:     iwp.Title,
 			Ref:      []string{ref},
@@ -187,12 +396,23 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 
 		// Default estimates if not set
 		if iwp.LowEstimate == nil && iwp.HighEstimate == nil && !task.Done {
-			task.EstimateLow = 1
-			task.EstimateHigh = 4
+			task.EstimateLow, task.EstimateHigh = opts.defaultEstimate()
+		}
+
+		// Scale estimates for scenario planning
+		if opts.EstimateMultiplier != 0 {
+			task.EstimateLow *= opts.EstimateMultiplier
+			task.EstimateHigh *= opts.EstimateMultiplier
 		}
 
 		// Extract assignee (use "unassigned" for tasks with no assignee)
-		if iwp.Assignee != "" {
+		if assignees := opts.Assignees[ref]; opts.RoundRobinAssignees && len(assignees) > 1 && !task.Done {
+			// Take turns among issues sharing the same set of assignees
+			key := strings.Join(assignees, ",")
+			task.User = assignees[rotation[key]%len(assignees)]
+			rotation[key]++
+			userSet[task.User] = true
+		} else if iwp.Assignee != "" {
 			task.User = iwp.Assignee
 			userSet[iwp.Assignee] = true
 		} else {
@@ -206,7 +426,7 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 		}
 
 		// Check for on-hold via Scheduling Status field
-		if iwp.SchedulingStatus == "On Hold" {
+		if opts.statusBehavior(iwp.SchedulingStatus) == StatusHold {
 			task.OnHold = true
 		}
 
@@ -214,6 +434,9 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 		pkgID := iwp.Milestone
 		if pkgID != "" {
 			task.PackageID = pkgID
+		} else if opts.GroupUnmilestoned {
+			// Group milestone-less issues in a synthetic package ordered last
+			task.PackageID = NoMilestonePackage
 		}
 
 		// Ensure milestone packages are ordered above unpackaged tasks
@@ -228,18 +451,29 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 		// Track scheduling issues for this task
 		var missingDeps []string
 		var onHoldDeps []string
+		var unassignedDeps []string
+		selfDependent := false
+
+		log := logrus.WithFields(privacy.LogFields(iwp.Owner, iwp.Repo, iwp.IssueNum))
 
 		// Map blockedBy to DependsOn (only if the blocking task exists in our data)
 		for _, blocker := range iwp.BlockedBy {
 			depID := fmt.Sprintf("%s/%s#%d", blocker.Owner, blocker.Repo, blocker.Number)
 			issueKey := fmt.Sprintf("github.com/%s/%s/issues/%d", blocker.Owner, blocker.Repo, blocker.Number)
 
+			// An issue blocked by itself would never become ready
+			if strings.EqualFold(depID, task.ID) {
+				selfDependent = true
+				log.Debug("Skipping dependency: the issue references itself")
+				continue
+			}
+
 			blockerIssue, exists := issues[issueKey]
 			if !exists {
 				// Skip closed dependencies - they're already satisfied
 				// (even if not in the project, e.g., archived issues)
 				if strings.EqualFold(blocker.State, "closed") {
-					logrus.Debugf("Skipping dependency %s for %s: blocker is closed (not in project)", depID, task.ID)
+					log.Debugf("Skipping dependency %s: blocker is closed (not in project)", depID)
 					continue
 				}
 				// Missing dependency - not in the project
@@ -250,30 +484,52 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 					logDepID = privacy.RedactDepID(depID)
 					logRepo = privacy.RedactRepo(blocker.Owner, blocker.Repo)
 				}
-				logrus.Warnf("Skipping dependency %s for %s: task not accessible (grant access to %s)", logDepID, task.ID, logRepo)
+				log.Warnf("Skipping dependency %s: task not accessible (grant access to %s)", logDepID, logRepo)
 				continue
 			}
 
 			// Skip closed dependencies - they're already satisfied
 			if strings.EqualFold(blockerIssue.State, "closed") {
-				logrus.Debugf("Skipping dependency %s for %s: blocker is closed", depID, task.ID)
+				log.Debugf("Skipping dependency %s: blocker is closed", depID)
 				continue
 			}
 
 			// Check if the dependency is on-hold
 			if onHoldIssues[issueKey] {
 				onHoldDeps = append(onHoldDeps, depID)
-				logrus.Debugf("Dependency %s for %s is on-hold", depID, task.ID)
+				log.Debugf("Dependency %s is on-hold", depID)
 				// Don't add on-hold deps to DependsOn - they would block scheduling
 				continue
 			}
 
+			// Check if the dependency was skipped because it is unassigned
+			if skippedIssues[issueKey] {
+				unassignedDeps = append(unassignedDeps, depID)
+				log.Debugf("Dependency %s is unassigned", depID)
+				continue
+			}
+
 			task.DependsOn = append(task.DependsOn, depID)
-			logrus.Debugf("Added dependency: %s depends on %s", task.ID, depID)
+			holdGraph.blockers[ref] = append(holdGraph.blockers[ref], issueKey)
+			log.Debugf("Added dependency on %s", depID)
+		}
+		holdGraph.taskIDs[ref] = task.ID
+		if len(onHoldDeps) > 0 {
+			holdGraph.directHold[ref] = onHoldDeps[0]
 		}
 
 		// Record scheduling issues for non-on-hold, non-closed tasks
 		if !task.OnHold && !task.Done {
+			if selfDependent {
+				schedIssues = append(schedIssues, SchedulingIssue{
+					IssueRef: ref,
+					IssueNum: iwp.IssueNum,
+					Owner:    iwp.Owner,
+					Repo:     iwp.Repo,
+					Reason:   "self_dependency",
+					Details:  []string{task.ID},
+				})
+			}
 			if len(missingDeps) > 0 {
 				schedIssues = append(schedIssues, SchedulingIssue{
 					IssueRef: ref,
@@ -294,6 +550,16 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 					Details:  onHoldDeps,
 				})
 			}
+			if len(unassignedDeps) > 0 {
+				schedIssues = append(schedIssues, SchedulingIssue{
+					IssueRef: ref,
+					IssueNum: iwp.IssueNum,
+					Owner:    iwp.Owner,
+					Repo:     iwp.Repo,
+					Reason:   "unassigned_dependency",
+					Details:  unassignedDeps,
+				})
+			}
 			if iwp.InaccessibleBlockers > 0 {
 				details := []string{fmt.Sprintf("%d blocker(s) from inaccessible repositories", iwp.InaccessibleBlockers)}
 				schedIssues = append(schedIssues, SchedulingIssue{
@@ -313,6 +579,10 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 			if iwp.HighEstimate == nil {
 				missingEstimates = append(missingEstimates, "High Estimate")
 			}
+			// Statuses that require (re-)estimation flag both fields even when set
+			if len(missingEstimates) == 0 && opts.statusBehavior(iwp.SchedulingStatus) == StatusRequireEstimate {
+				missingEstimates = append(missingEstimates, "Low Estimate", "High Estimate")
+			}
 			if len(missingEstimates) > 0 {
 				schedIssues = append(schedIssues, SchedulingIssue{
 					IssueRef: ref,
@@ -339,27 +609,51 @@ func IssuesToTasks(issues map[string]IssueWithProject, privacy *PrivacyFilter) (
 		tasks = append(tasks, task)
 	}
 
+	// Report tasks that are only transitively blocked by on-hold work
+	hasIssue := make(map[string]bool)
+	for _, si := range schedIssues {
+		hasIssue[si.IssueRef] = true
+	}
+	for _, t := range tasks {
+		ref := t.Ref[0]
+		if t.OnHold || t.Done || hasIssue[ref] {
+			continue
+		}
+		path := holdGraph.path(ref, make(map[string]bool))
+		if len(path) < 3 {
+			continue
+		}
+		if privacy != nil {
+			for i := range path {
+				path[i] = privacy.RedactDepID(path[i])
+			}
+		}
+		iwp := issues[ref]
+		schedIssues = append(schedIssues, SchedulingIssue{
+			IssueRef: ref,
+			IssueNum: iwp.IssueNum,
+			Owner:    iwp.Owner,
+			Repo:     iwp.Repo,
+			Reason:   "onhold_dependency",
+			Details:  []string{strings.Join(path, " → ")},
+		})
+		hasIssue[ref] = true
+	}
+
 	// Assign sequences properly
 	for j := range tasks {
 		seq, _ := gen.After(tasks[max(0, j-1)].Sequence)
 		tasks[j].Sequence = seq
 	}
 
-	// Create users with default availability
+	// Create users with configured (or default) availability
 	var users []recfile.User
 	for username := range userSet {
-		users = append(users, recfile.User{
-			ID:             username,
-			MondayHours:    8,
-			TuesdayHours:   8,
-			WednesdayHours: 8,
-			ThursdayHours:  8,
-			FridayHours:    8,
-		})
+		users = append(users, opts.Availability.User(username))
 	}
 
 	// Add default user if no assignees
-	if len(users) == 0 {
+	if len(users) == 0 && !opts.SkipUnassigned {
 		users = append(users, recfile.User{
 			ID:             "unassigned",
 			MondayHours:    8,
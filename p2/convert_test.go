@@ -259,6 +259,54 @@ func TestIssuesToTasks_PackageOrderByDueDate(t *testing.T) {
 	}
 }
 
+func TestIssuesToTasks_DueDatedMilestoneAheadOfSameUserWork(t *testing.T) {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// The due-dated issue is last in project order but must still come first
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			Title:    "Unmilestoned",
+			State:    "open",
+			Assignee: "alice",
+			Order:    0,
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  2,
+			Title:     "Undated Milestone",
+			State:     "open",
+			Assignee:  "alice",
+			Order:     1,
+			Milestone: "Someday",
+		},
+		"github.com/owner/repo/issues/3": {
+			Owner:            "owner",
+			Repo:             "repo",
+			IssueNum:         3,
+			Title:            "Dated Milestone",
+			State:            "open",
+			Assignee:         "alice",
+			Order:            2,
+			Milestone:        "Launch",
+			MilestoneDueDate: &due,
+		},
+	}
+
+	tasks, _, _ := IssuesToTasks(issues, nil)
+
+	orders := make(map[string]int)
+	for _, task := range tasks {
+		orders[task.Name] = task.PackageOrder
+	}
+	if !(orders["Dated Milestone"] < orders["Undated Milestone"] && orders["Undated Milestone"] < orders["Unmilestoned"]) {
+		t.Errorf("expected dated milestone, then undated milestone, then unmilestoned work, got %v", orders)
+	}
+}
+
 func TestIssuesToTasks_PackageOrderBySemver(t *testing.T) {
 	issues := map[string]IssueWithProject{
 		"github.com/owner/repo/issues/1": {
@@ -917,3 +965,413 @@ func TestIssuesToTasks_BothEstimatesNilReportsMissingAndGetsDefaults(t *testing.
 		t.Errorf("expected default high estimate of 4, got %.1f", tasks[0].EstimateHigh)
 	}
 }
+
+func TestIssuesToTasksWithOptions_SkipUnassigned(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			Title:        "Assigned Task",
+			State:        "open",
+			Assignee:     "cwarden",
+			LowEstimate:  ptr(1),
+			HighEstimate: ptr(2),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     2,
+			Title:        "Unassigned Task",
+			State:        "open",
+			LowEstimate:  ptr(1),
+			HighEstimate: ptr(2),
+		},
+	}
+
+	tasks, users, schedIssues := IssuesToTasksWithOptions(issues, nil, ConvertOptions{SkipUnassigned: true})
+
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+	if tasks[0].Name != "Assigned Task" {
+		t.Errorf("expected only 'Assigned Task' to be scheduled, got %q", tasks[0].Name)
+	}
+
+	for _, u := range users {
+		if u.ID == "unassigned" {
+			t.Error("expected no 'unassigned' user to be created")
+		}
+	}
+
+	if len(schedIssues) != 1 {
+		t.Fatalf("expected 1 scheduling issue, got %d: %+v", len(schedIssues), schedIssues)
+	}
+	if schedIssues[0].Reason != "no_assignee" {
+		t.Errorf("expected reason 'no_assignee', got %q", schedIssues[0].Reason)
+	}
+	if schedIssues[0].IssueNum != 2 {
+		t.Errorf("expected issue #2 to be flagged, got #%d", schedIssues[0].IssueNum)
+	}
+}
+
+func TestIssuesToTasksWithOptions_SkipUnassignedDependency(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			Title:        "Unassigned Blocker",
+			State:        "open",
+			LowEstimate:  ptr(1),
+			HighEstimate: ptr(2),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     2,
+			Title:        "Blocked Task",
+			State:        "open",
+			Assignee:     "cwarden",
+			LowEstimate:  ptr(1),
+			HighEstimate: ptr(2),
+			BlockedBy: []github.IssueRef{
+				{Owner: "owner", Repo: "repo", Number: 1},
+			},
+		},
+	}
+
+	tasks, _, schedIssues := IssuesToTasksWithOptions(issues, nil, ConvertOptions{SkipUnassigned: true})
+
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+	if len(tasks[0].DependsOn) != 0 {
+		t.Errorf("expected no DependsOn for skipped blocker, got %v", tasks[0].DependsOn)
+	}
+
+	found := false
+	for _, si := range schedIssues {
+		if si.Reason == "unassigned_dependency" && si.IssueNum == 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected unassigned_dependency issue for #2, got %+v", schedIssues)
+	}
+}
+
+func TestIssuesToTasksWithOptions_StatusBehaviors(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:            "owner",
+			Repo:             "repo",
+			IssueNum:         1,
+			Title:            "Needs Estimate Task",
+			State:            "open",
+			SchedulingStatus: "Needs Estimate",
+			LowEstimate:      ptr(2),
+			HighEstimate:     ptr(4),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:            "owner",
+			Repo:             "repo",
+			IssueNum:         2,
+			Title:            "Blocked Task",
+			State:            "open",
+			SchedulingStatus: "Blocked",
+			LowEstimate:      ptr(2),
+			HighEstimate:     ptr(4),
+		},
+		"github.com/owner/repo/issues/3": {
+			Owner:            "owner",
+			Repo:             "repo",
+			IssueNum:         3,
+			Title:            "Ready Task",
+			State:            "open",
+			SchedulingStatus: "Ready",
+			LowEstimate:      ptr(2),
+			HighEstimate:     ptr(4),
+			BlockedBy: []github.IssueRef{
+				{Owner: "owner", Repo: "repo", Number: 2},
+			},
+		},
+	}
+
+	behaviors, err := ParseStatusBehaviors(map[string]string{
+		"Blocked":        "hold",
+		"Ready":          "schedule",
+		"Needs Estimate": "require-estimate",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tasks, _, schedIssues := IssuesToTasksWithOptions(issues, nil, ConvertOptions{StatusBehaviors: behaviors})
+
+	taskMap := make(map[string]planner.Task)
+	for _, task := range tasks {
+		taskMap[task.Name] = task
+	}
+	if !taskMap["Blocked Task"].OnHold {
+		t.Error("expected 'Blocked Task' to be on hold")
+	}
+	if taskMap["Needs Estimate Task"].OnHold || taskMap["Ready Task"].OnHold {
+		t.Error("expected 'Needs Estimate Task' and 'Ready Task' to be scheduled")
+	}
+
+	reasons := make(map[int]string)
+	for _, si := range schedIssues {
+		reasons[si.IssueNum] = si.Reason
+	}
+	if reasons[1] != "missing_estimate" {
+		t.Errorf("expected 'Needs Estimate' to force missing_estimate, got %q", reasons[1])
+	}
+	if reasons[3] != "onhold_dependency" {
+		t.Errorf("expected dependency on 'Blocked' task to be onhold_dependency, got %q", reasons[3])
+	}
+	if _, ok := reasons[2]; ok {
+		t.Errorf("expected no scheduling issue for on-hold task, got %q", reasons[2])
+	}
+}
+
+func TestIssuesToTasks_DetectsTransitiveOnHoldDependencies(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:            "owner",
+			Repo:             "repo",
+			IssueNum:         1,
+			State:            "open",
+			SchedulingStatus: "On Hold",
+			LowEstimate:      ptr(2),
+			HighEstimate:     ptr(4),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     2,
+			State:        "open",
+			LowEstimate:  ptr(2),
+			HighEstimate: ptr(4),
+			BlockedBy:    []github.IssueRef{{Owner: "owner", Repo: "repo", Number: 1}},
+		},
+		"github.com/owner/repo/issues/3": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     3,
+			State:        "open",
+			LowEstimate:  ptr(2),
+			HighEstimate: ptr(4),
+			BlockedBy:    []github.IssueRef{{Owner: "owner", Repo: "repo", Number: 2}},
+		},
+	}
+
+	_, _, schedIssues := IssuesToTasks(issues, nil)
+
+	byNum := make(map[int]SchedulingIssue)
+	for _, si := range schedIssues {
+		if _, dup := byNum[si.IssueNum]; dup {
+			t.Errorf("expected one scheduling issue for #%d, got several", si.IssueNum)
+		}
+		byNum[si.IssueNum] = si
+	}
+	if len(schedIssues) != 2 {
+		t.Fatalf("expected 2 scheduling issues, got %d: %+v", len(schedIssues), schedIssues)
+	}
+	if si := byNum[2]; si.Reason != "onhold_dependency" || len(si.Details) != 1 || si.Details[0] != "owner/repo#1" {
+		t.Errorf("expected #2 to report direct on-hold dependency owner/repo#1, got %+v", si)
+	}
+	want := "owner/repo#1 → owner/repo#2 → owner/repo#3"
+	if si := byNum[3]; si.Reason != "onhold_dependency" || len(si.Details) != 1 || si.Details[0] != want {
+		t.Errorf("expected #3 to report path %q, got %+v", want, si)
+	}
+}
+
+func TestParseStatusBehaviors_RejectsUnknownBehavior(t *testing.T) {
+	if _, err := ParseStatusBehaviors(map[string]string{"Blocked": "pause"}); err == nil {
+		t.Error("expected error for unknown behavior")
+	}
+}
+
+func TestIssuesToTasksWithOptions_EstimateMultiplier(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			Title:        "Task",
+			State:        "open",
+			LowEstimate:  ptr(2),
+			HighEstimate: ptr(4),
+		},
+	}
+
+	tasks, _, _ := IssuesToTasksWithOptions(issues, nil, ConvertOptions{EstimateMultiplier: 1.5})
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+	if tasks[0].EstimateLow != 3 || tasks[0].EstimateHigh != 6 {
+		t.Errorf("expected scaled estimate 3-6, got %g-%g", tasks[0].EstimateLow, tasks[0].EstimateHigh)
+	}
+	if *issues["github.com/owner/repo/issues/1"].LowEstimate != 2 {
+		t.Error("expected underlying issue estimate to be unchanged")
+	}
+}
+
+func TestIssuesToTasksWithOptions_GroupUnmilestoned(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			State:    "open",
+			Order:    0,
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  2,
+			State:     "open",
+			Milestone: "v1.0.0",
+			Order:     1,
+		},
+		"github.com/owner/repo/issues/3": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 3,
+			State:    "open",
+			Order:    2,
+		},
+	}
+
+	tasks, _, _ := IssuesToTasksWithOptions(issues, nil, ConvertOptions{GroupUnmilestoned: true})
+	byID := make(map[string]planner.Task)
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	for _, id := range []string{"owner/repo#1", "owner/repo#3"} {
+		if byID[id].PackageID != NoMilestonePackage {
+			t.Errorf("expected %s in package %q, got %q", id, NoMilestonePackage, byID[id].PackageID)
+		}
+		if byID[id].PackageOrder <= byID["owner/repo#2"].PackageOrder {
+			t.Errorf("expected %s to be ordered after the v1.0.0 package", id)
+		}
+	}
+	if byID["owner/repo#2"].PackageID != "v1.0.0" {
+		t.Errorf("expected real milestone to be kept, got %q", byID["owner/repo#2"].PackageID)
+	}
+	if issues["github.com/owner/repo/issues/1"].Milestone != "" {
+		t.Error("expected the underlying issue milestone to be unchanged")
+	}
+}
+
+func TestIssuesToTasks_RoundRobinAssignees(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, State: "open", Assignee: "alice", Order: 0},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, State: "open", Assignee: "alice", Order: 1},
+		"github.com/owner/repo/issues/3": {Owner: "owner", Repo: "repo", IssueNum: 3, State: "open", Assignee: "alice", Order: 2},
+		"github.com/owner/repo/issues/4": {Owner: "owner", Repo: "repo", IssueNum: 4, State: "open", Assignee: "carol", Order: 3},
+	}
+	opts := ConvertOptions{
+		Assignees: map[string][]string{
+			"github.com/owner/repo/issues/1": {"alice", "bob"},
+			"github.com/owner/repo/issues/2": {"alice", "bob"},
+			"github.com/owner/repo/issues/3": {"alice", "bob"},
+		},
+		RoundRobinAssignees: true,
+	}
+
+	tasks, users, _ := IssuesToTasksWithOptions(issues, nil, opts)
+
+	want := map[string]string{
+		"owner/repo#1": "alice",
+		"owner/repo#2": "bob",
+		"owner/repo#3": "alice",
+		"owner/repo#4": "carol",
+	}
+	for _, task := range tasks {
+		if task.User != want[task.ID] {
+			t.Errorf("expected %s to be scheduled for %s, got %s", task.ID, want[task.ID], task.User)
+		}
+	}
+	if len(users) != 3 {
+		t.Errorf("expected users alice, bob, and carol, got %+v", users)
+	}
+
+	// Without the option, only the first assignee is used
+	opts.RoundRobinAssignees = false
+	tasks, _, _ = IssuesToTasksWithOptions(issues, nil, opts)
+	for _, task := range tasks {
+		if task.ID == "owner/repo#2" && task.User != "alice" {
+			t.Errorf("expected first assignee without round-robin, got %s", task.User)
+		}
+	}
+}
+
+func TestIssuesToTasks_ConfiguredDefaultEstimate(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, State: "open", Assignee: "alice"},
+	}
+
+	tasks, _, schedIssues := IssuesToTasksWithOptions(issues, nil, ConvertOptions{DefaultEstimateLow: 4, DefaultEstimateHigh: 12})
+
+	if len(tasks) != 1 || tasks[0].EstimateLow != 4 || tasks[0].EstimateHigh != 12 {
+		t.Fatalf("expected default estimate 4-12, got %+v", tasks)
+	}
+	var missing bool
+	for _, si := range schedIssues {
+		if si.Reason == "missing_estimate" {
+			missing = true
+		}
+	}
+	if !missing {
+		t.Error("expected missing_estimate to be reported when the default is applied")
+	}
+
+	// The zero value keeps the built-in 1-4 default
+	tasks, _, _ = IssuesToTasks(issues, nil)
+	if tasks[0].EstimateLow != DefaultEstimateLow || tasks[0].EstimateHigh != DefaultEstimateHigh {
+		t.Errorf("expected built-in default estimate, got %v-%v", tasks[0].EstimateLow, tasks[0].EstimateHigh)
+	}
+}
+
+func TestIssuesToTasks_SelfDependency(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  1,
+			Title:     "Blocked by itself",
+			State:     "open",
+			BlockedBy: []IssueRef{{Owner: "Owner", Repo: "repo", Number: 1}, {Owner: "owner", Repo: "repo", Number: 2}},
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 2,
+			Title:    "Real blocker",
+			State:    "open",
+		},
+	}
+
+	tasks, _, schedIssues := IssuesToTasks(issues, nil)
+	for _, task := range tasks {
+		if task.ID == "owner/repo#1" && (len(task.DependsOn) != 1 || task.DependsOn[0] != "owner/repo#2") {
+			t.Errorf("expected only the real blocker in DependsOn, got %v", task.DependsOn)
+		}
+	}
+
+	var self *SchedulingIssue
+	for i := range schedIssues {
+		if schedIssues[i].Reason == "self_dependency" {
+			self = &schedIssues[i]
+		}
+	}
+	if self == nil {
+		t.Fatalf("expected a self_dependency scheduling issue, got %+v", schedIssues)
+	}
+	if self.IssueNum != 1 || len(self.Details) != 1 || self.Details[0] != "owner/repo#1" {
+		t.Errorf("expected #1 to report referencing owner/repo#1, got %+v", self)
+	}
+}
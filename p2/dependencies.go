@@ -0,0 +1,238 @@
+package p2
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyDependencyLabels parses issue labels matching pattern into blocking relationships.
+// A matching label on issue X naming issue Y records that X blocks Y: X gains a
+// Blocking entry and Y (if present in issues) gains a BlockedBy entry.
+// The pattern must capture the issue number in a group named "num". The "owner"
+// and "repo" groups are optional and default to the labeled issue's repository,
+// so a pattern like `^epic:(?P<num>\d+)$` refers to issues in the same repo.
+func ApplyDependencyLabels(issues map[string]IssueWithProject, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid dependency label pattern: %w", err)
+	}
+	numIdx := re.SubexpIndex("num")
+	if numIdx < 0 {
+		return fmt.Errorf("dependency label pattern must have a named group \"num\"")
+	}
+	ownerIdx := re.SubexpIndex("owner")
+	repoIdx := re.SubexpIndex("repo")
+
+	// Iterate in a stable order so appended relationships are deterministic
+	refs := make([]string, 0, len(issues))
+	for ref := range issues {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	for _, ref := range refs {
+		blocker := issues[ref]
+		if blocker.IsDraft {
+			continue
+		}
+		for _, label := range blocker.Labels {
+			matches := re.FindStringSubmatch(label)
+			if matches == nil {
+				continue
+			}
+			num, err := strconv.Atoi(matches[numIdx])
+			if err != nil {
+				continue
+			}
+			owner, repo := blocker.Owner, blocker.Repo
+			if ownerIdx >= 0 && matches[ownerIdx] != "" {
+				owner = matches[ownerIdx]
+			}
+			if repoIdx >= 0 && matches[repoIdx] != "" {
+				repo = matches[repoIdx]
+			}
+
+			blockedKey := fmt.Sprintf("github.com/%s/%s/issues/%d", owner, repo, num)
+			if blockedKey == ref {
+				// Kept so IssuesToTasks reports a self_dependency
+				selfRef := IssueRef{Owner: owner, Repo: repo, Number: num, State: blocker.State}
+				if !hasIssueRef(blocker.BlockedBy, selfRef) {
+					blocker.BlockedBy = append(blocker.BlockedBy, selfRef)
+				}
+				continue
+			}
+			blocked, exists := issues[blockedKey]
+
+			blockedRef := IssueRef{Owner: owner, Repo: repo, Number: num}
+			if exists {
+				blockedRef.State = blocked.State
+			}
+			if !hasIssueRef(blocker.Blocking, blockedRef) {
+				blocker.Blocking = append(blocker.Blocking, blockedRef)
+			}
+
+			if exists {
+				blockerRef := IssueRef{Owner: blocker.Owner, Repo: blocker.Repo, Number: blocker.IssueNum, State: blocker.State}
+				if !hasIssueRef(blocked.BlockedBy, blockerRef) {
+					blocked.BlockedBy = append(blocked.BlockedBy, blockerRef)
+					issues[blockedKey] = blocked
+				}
+			}
+		}
+		issues[ref] = blocker
+	}
+
+	return nil
+}
+
+// DependencyEdge declares that Blocker blocks Blocked. Both are "owner/repo#N" references.
+type DependencyEdge struct {
+	Blocker string `yaml:"blocker"`
+	Blocked string `yaml:"blocked"`
+}
+
+// dependencyFile is the format of a --deps file
+type dependencyFile struct {
+	Dependencies []DependencyEdge `yaml:"dependencies"`
+}
+
+// LoadDependencyFile reads dependency edges from a YAML or JSON file of the form:
+//
+//	dependencies:
+//	  - blocker: owner/repo#1
+//	    blocked: owner/repo#2
+func LoadDependencyFile(path string) ([]DependencyEdge, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dependency file: %w", err)
+	}
+	var f dependencyFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse dependency file %s: %w", path, err)
+	}
+	return f.Dependencies, nil
+}
+
+// ApplyDependencyEdges merges edges into the BlockedBy (and Blocking) lists of issues.
+// Edges whose blocked issue is not in issues are skipped with a warning; edges whose
+// blocker is missing are still added so IssuesToTasks reports a missing_dependency.
+func ApplyDependencyEdges(issues map[string]IssueWithProject, edges []DependencyEdge) error {
+	for _, edge := range edges {
+		blockerRef, ok := parseIssueRef(edge.Blocker)
+		if !ok {
+			return fmt.Errorf("invalid blocker %q: expected owner/repo#N", edge.Blocker)
+		}
+		blockedRef, ok := parseIssueRef(edge.Blocked)
+		if !ok {
+			return fmt.Errorf("invalid blocked issue %q: expected owner/repo#N", edge.Blocked)
+		}
+
+		blockedKey := issueKey(blockedRef)
+		blocked, exists := issues[blockedKey]
+		if !exists {
+			logrus.Warnf("Skipping dependency %s -> %s: %s is not in the project", edge.Blocker, edge.Blocked, edge.Blocked)
+			continue
+		}
+
+		blockerKey := issueKey(blockerRef)
+		if blocker, exists := issues[blockerKey]; exists {
+			blockerRef.State = blocker.State
+			blockedRef.State = blocked.State
+			if !hasIssueRef(blocker.Blocking, blockedRef) {
+				blocker.Blocking = append(blocker.Blocking, blockedRef)
+				issues[blockerKey] = blocker
+			}
+		}
+
+		// Re-read in case the edge is a self-reference
+		blocked = issues[blockedKey]
+		if !hasIssueRef(blocked.BlockedBy, blockerRef) {
+			blocked.BlockedBy = append(blocked.BlockedBy, blockerRef)
+			issues[blockedKey] = blocked
+		}
+	}
+	return nil
+}
+
+// bodyRefPattern matches one issue reference in an issue body: a full issue
+// URL, "owner/repo#N", or "#N" for the issue's own repository
+const bodyRefPattern = `(?:https://github\.com/([\w.-]+)/([\w.-]+)/issues/(\d+)|([\w.-]+)/([\w.-]+)#(\d+)|#(\d+))\b`
+
+var (
+	bodyRefRe = regexp.MustCompile(bodyRefPattern)
+	bodyDepRe = regexp.MustCompile(`(?i)\b(?:depends\s+on|blocked\s+by):?\s*(` + bodyRefPattern + `(?:\s*(?:,|and|&)?\s*` + bodyRefPattern + `)*)`)
+)
+
+// BodyDependencyEdges extracts dependencies written in issue bodies, such as
+// "Depends on #12" or "Blocked by owner/repo#7, https://github.com/owner/repo/issues/8".
+// Each referenced issue blocks the issue whose body mentions it.
+func BodyDependencyEdges(issues map[string]IssueWithProject) []DependencyEdge {
+	refs := make([]string, 0, len(issues))
+	for ref := range issues {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	var edges []DependencyEdge
+	for _, ref := range refs {
+		iwp := issues[ref]
+		if iwp.IsDraft || iwp.Body == "" {
+			continue
+		}
+		blocked := fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
+		for _, phrase := range bodyDepRe.FindAllStringSubmatch(iwp.Body, -1) {
+			for _, m := range bodyRefRe.FindAllStringSubmatch(phrase[1], -1) {
+				owner, repo, num := iwp.Owner, iwp.Repo, m[7]
+				switch {
+				case m[3] != "":
+					owner, repo, num = m[1], m[2], m[3]
+				case m[6] != "":
+					owner, repo, num = m[4], m[5], m[6]
+				}
+				// Self-references are kept so IssuesToTasks reports a self_dependency
+				edges = append(edges, DependencyEdge{Blocker: fmt.Sprintf("%s/%s#%s", owner, repo, num), Blocked: blocked})
+			}
+		}
+	}
+	return edges
+}
+
+// parseIssueRef parses an "owner/repo#N" reference
+func parseIssueRef(s string) (IssueRef, bool) {
+	s = strings.TrimSpace(s)
+	hashIdx := strings.LastIndex(s, "#")
+	if hashIdx < 0 {
+		return IssueRef{}, false
+	}
+	num, err := strconv.Atoi(s[hashIdx+1:])
+	if err != nil || num <= 0 {
+		return IssueRef{}, false
+	}
+	owner, repo, ok := strings.Cut(s[:hashIdx], "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return IssueRef{}, false
+	}
+	return IssueRef{Owner: owner, Repo: repo, Number: num}, true
+}
+
+// issueKey returns the issues map key for ref
+func issueKey(ref IssueRef) string {
+	return fmt.Sprintf("github.com/%s/%s/issues/%d", ref.Owner, ref.Repo, ref.Number)
+}
+
+// hasIssueRef returns true if refs already contains an entry for the same issue
+func hasIssueRef(refs []IssueRef, target IssueRef) bool {
+	for _, r := range refs {
+		if r.Owner == target.Owner && r.Repo == target.Repo && r.Number == target.Number {
+			return true
+		}
+	}
+	return false
+}
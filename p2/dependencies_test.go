@@ -0,0 +1,305 @@
+package p2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// blocksLabelPattern matches labels of the form "blocks:owner/repo#N"
+const blocksLabelPattern = `^blocks:(?P<owner>[^/\s]+)/(?P<repo>[^#\s]+)#(?P<num>\d+)$`
+
+func TestApplyDependencyLabels_BlocksLabelAddsRelationships(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			Title:    "Blocker",
+			State:    "open",
+			Labels:   []string{"backend", "blocks:owner/repo#5"},
+		},
+		"github.com/owner/repo/issues/5": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 5,
+			Title:    "Blocked",
+			State:    "open",
+		},
+	}
+
+	if err := ApplyDependencyLabels(issues, blocksLabelPattern); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blocker := issues["github.com/owner/repo/issues/1"]
+	if len(blocker.Blocking) != 1 {
+		t.Fatalf("expected 1 Blocking entry, got %d", len(blocker.Blocking))
+	}
+	if b := blocker.Blocking[0]; b.Owner != "owner" || b.Repo != "repo" || b.Number != 5 {
+		t.Errorf("expected Blocking entry owner/repo#5, got %s/%s#%d", b.Owner, b.Repo, b.Number)
+	}
+
+	blocked := issues["github.com/owner/repo/issues/5"]
+	if len(blocked.BlockedBy) != 1 {
+		t.Fatalf("expected 1 BlockedBy entry, got %d", len(blocked.BlockedBy))
+	}
+	if b := blocked.BlockedBy[0]; b.Owner != "owner" || b.Repo != "repo" || b.Number != 1 {
+		t.Errorf("expected BlockedBy entry owner/repo#1, got %s/%s#%d", b.Owner, b.Repo, b.Number)
+	}
+
+	// The reverse dependency should flow through to the planner task
+	tasks, _, _ := IssuesToTasks(issues, nil)
+	for _, task := range tasks {
+		if task.ID != "owner/repo#5" {
+			continue
+		}
+		if len(task.DependsOn) != 1 || task.DependsOn[0] != "owner/repo#1" {
+			t.Errorf("expected owner/repo#5 to depend on owner/repo#1, got %v", task.DependsOn)
+		}
+	}
+}
+
+func TestApplyDependencyLabels_SameRepoPattern(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			State:    "open",
+			Labels:   []string{"epic:2"},
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 2,
+			State:    "open",
+		},
+	}
+
+	if err := ApplyDependencyLabels(issues, `^epic:(?P<num>\d+)$`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blocked := issues["github.com/owner/repo/issues/2"]
+	if len(blocked.BlockedBy) != 1 || blocked.BlockedBy[0].Number != 1 {
+		t.Errorf("expected #2 to be blocked by #1, got %v", blocked.BlockedBy)
+	}
+}
+
+func TestApplyDependencyLabels_NoDuplicateOfNativeRelationship(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			State:    "open",
+			Labels:   []string{"blocks:owner/repo#2"},
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  2,
+			State:     "open",
+			BlockedBy: []IssueRef{{Owner: "owner", Repo: "repo", Number: 1}},
+		},
+	}
+
+	if err := ApplyDependencyLabels(issues, blocksLabelPattern); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := len(issues["github.com/owner/repo/issues/2"].BlockedBy); n != 1 {
+		t.Errorf("expected 1 BlockedBy entry, got %d", n)
+	}
+}
+
+func TestApplyDependencyLabels_PatternRequiresNumGroup(t *testing.T) {
+	if err := ApplyDependencyLabels(map[string]IssueWithProject{}, `^blocks:(\d+)$`); err == nil {
+		t.Error("expected error for pattern without a \"num\" group")
+	}
+}
+
+func TestApplyDependencyEdges_FileEdgeCreatesDependsOn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deps.yaml")
+	content := `dependencies:
+  - blocker: owner/repo#1
+    blocked: owner/repo#2
+  - blocker: infra/tickets#99
+    blocked: owner/repo#2
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	edges, err := LoadDependencyFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(edges) != 2 {
+		t.Fatalf("expected 2 edges, got %d", len(edges))
+	}
+
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			Title:    "Blocker",
+			State:    "open",
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 2,
+			Title:    "Blocked",
+			State:    "open",
+		},
+	}
+
+	if err := ApplyDependencyEdges(issues, edges); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tasks, _, schedIssues := IssuesToTasks(issues, nil)
+	for _, task := range tasks {
+		if task.ID != "owner/repo#2" {
+			continue
+		}
+		if len(task.DependsOn) != 1 || task.DependsOn[0] != "owner/repo#1" {
+			t.Errorf("expected owner/repo#2 to depend on owner/repo#1, got %v", task.DependsOn)
+		}
+	}
+
+	var missing *SchedulingIssue
+	for i := range schedIssues {
+		if schedIssues[i].Reason == "missing_dependency" {
+			missing = &schedIssues[i]
+		}
+	}
+	if missing == nil {
+		t.Fatal("expected unknown blocker to be flagged as missing_dependency")
+	}
+	if missing.IssueNum != 2 || len(missing.Details) != 1 || missing.Details[0] != "infra/tickets#99" {
+		t.Errorf("expected #2 to report missing infra/tickets#99, got %+v", missing)
+	}
+}
+
+func TestApplyDependencyEdges_InvalidReference(t *testing.T) {
+	edges := []DependencyEdge{{Blocker: "not-a-ref", Blocked: "owner/repo#2"}}
+	if err := ApplyDependencyEdges(map[string]IssueWithProject{}, edges); err == nil {
+		t.Error("expected error for invalid reference")
+	}
+}
+
+func TestBodyDependencyEdges_ParsesReferenceForms(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			State:    "open",
+			Body:     "Depends on #2, other/lib#7 and https://github.com/owner/web/issues/9\n\nblocked by: #1",
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 2,
+			State:    "open",
+			Body:     "See #1 for context",
+		},
+	}
+
+	edges := BodyDependencyEdges(issues)
+	want := []DependencyEdge{
+		{Blocker: "owner/repo#2", Blocked: "owner/repo#1"},
+		{Blocker: "other/lib#7", Blocked: "owner/repo#1"},
+		{Blocker: "owner/web#9", Blocked: "owner/repo#1"},
+		{Blocker: "owner/repo#1", Blocked: "owner/repo#1"},
+	}
+	if len(edges) != len(want) {
+		t.Fatalf("expected %v, got %v", want, edges)
+	}
+	for i := range want {
+		if edges[i] != want[i] {
+			t.Errorf("edge %d: expected %+v, got %+v", i, want[i], edges[i])
+		}
+	}
+}
+
+func TestBodyDependencyEdges_DedupedAgainstNativeRelationship(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			State:    "open",
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  2,
+			State:     "open",
+			Body:      "Blocked by #1",
+			BlockedBy: []IssueRef{{Owner: "owner", Repo: "repo", Number: 1}},
+		},
+	}
+
+	if err := ApplyDependencyEdges(issues, BodyDependencyEdges(issues)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(issues["github.com/owner/repo/issues/2"].BlockedBy); n != 1 {
+		t.Errorf("expected 1 BlockedBy entry, got %d", n)
+	}
+	if n := len(issues["github.com/owner/repo/issues/1"].Blocking); n != 1 {
+		t.Errorf("expected 1 Blocking entry, got %d", n)
+	}
+}
+
+func TestApplyDependencyLabels_SelfReferenceReportedAsSelfDependency(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			State:        "open",
+			Labels:       []string{"blocks:owner/repo#1"},
+			LowEstimate:  ptr(1),
+			HighEstimate: ptr(2),
+		},
+	}
+
+	if err := ApplyDependencyLabels(issues, blocksLabelPattern); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSelfDependency(t, issues)
+}
+
+func TestBodyDependencyEdges_SelfReferenceReportedAsSelfDependency(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			State:        "open",
+			Body:         "Depends on #1",
+			LowEstimate:  ptr(1),
+			HighEstimate: ptr(2),
+		},
+	}
+
+	if err := ApplyDependencyEdges(issues, BodyDependencyEdges(issues)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertSelfDependency(t, issues)
+}
+
+// assertSelfDependency checks that IssuesToTasks reports owner/repo#1 as
+// blocking itself
+func assertSelfDependency(t *testing.T, issues map[string]IssueWithProject) {
+	t.Helper()
+	_, _, schedIssues := IssuesToTasks(issues, nil)
+	if len(schedIssues) != 1 || schedIssues[0].IssueNum != 1 || schedIssues[0].Reason != "self_dependency" {
+		t.Errorf("expected #1 to be reported as a self_dependency, got %+v", schedIssues)
+	}
+}
@@ -0,0 +1,200 @@
+package p2
+
+import (
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// EarliestStartField is the optional project date field holding the first day
+// an issue's work can begin
+const EarliestStartField = "Earliest Start"
+
+// ApplyEarliestStarts delays each bar with an entry in notBefore, keyed by task
+// ID, so that it starts no earlier than that date. As with ApplyDependencyLag,
+// a delayed bar keeps its duration. The delay propagates to dependents of a
+// delayed bar and to the same user's bars queued behind it, which start no
+// earlier than its new Expected Completion. It returns the number of bars
+// delayed by their own earliest start.
+func ApplyEarliestStarts(ganttData *planner.GanttData, tasks []planner.Task, notBefore map[string]time.Time) int {
+	if len(notBefore) == 0 {
+		return 0
+	}
+
+	s := newBarShifter(ganttData)
+	s.propagate(tasks, notBefore, nil)
+
+	constrained := 0
+	for id, date := range notBefore {
+		if _, ok := s.barIndex[id]; ok && s.origStart[id].Before(date) && ganttData.Bars[s.barIndex[id]].ExpStartDate.After(s.origStart[id]) {
+			constrained++
+		}
+	}
+	return constrained
+}
+
+// barShifter pushes bars back after scheduling, remembering each bar's dates
+// from when it was created so moved bars can be told apart
+type barShifter struct {
+	ganttData *planner.GanttData
+	barIndex  map[string]int
+	origStart map[string]time.Time
+	origMean  map[string]time.Time
+}
+
+// newBarShifter records the current dates of ganttData's bars
+func newBarShifter(ganttData *planner.GanttData) *barShifter {
+	s := &barShifter{
+		ganttData: ganttData,
+		barIndex:  make(map[string]int, len(ganttData.Bars)),
+		origStart: make(map[string]time.Time, len(ganttData.Bars)),
+		origMean:  make(map[string]time.Time, len(ganttData.Bars)),
+	}
+	for i, bar := range ganttData.Bars {
+		if !bar.IsPackage {
+			s.barIndex[bar.ID] = i
+			s.origStart[bar.ID] = bar.ExpStartDate
+			s.origMean[bar.ID] = bar.MeanDate
+		}
+	}
+	return s
+}
+
+// moved reports whether the bar's Expected Completion is later than recorded
+func (s *barShifter) moved(id string) bool {
+	return s.ganttData.Bars[s.barIndex[id]].MeanDate.After(s.origMean[id])
+}
+
+// propagate delays each bar, keeping its duration, so that it starts no
+// earlier than notBefore[ID], than lags[ID] working days after each blocker's
+// Expected Completion, and than the new Expected Completion of any moved bar
+// of the same user that it was queued behind. Blockers that haven't moved
+// only constrain dependents with a lag, since the scheduler already ordered
+// them. Dependencies within a cycle are ignored.
+func (s *barShifter) propagate(tasks []planner.Task, notBefore map[string]time.Time, lags map[string]int) {
+	bars := s.ganttData.Bars
+	cycles := dependencyCycles(tasks)
+
+	// Repeat until stable so delays flow down dependency chains and queues;
+	// the bound guards against cycles, which the scheduler has already reported
+	for pass := 0; pass <= len(tasks); pass++ {
+		changed := false
+		for _, task := range tasks {
+			idx, ok := s.barIndex[task.ID]
+			if !ok {
+				continue
+			}
+			bar := &bars[idx]
+			if bar.Done || bar.OnHold || bar.ExpStartDate.IsZero() {
+				continue
+			}
+
+			earliest := notBefore[task.ID]
+			lag := lags[task.ID]
+			for _, dep := range task.DependsOn {
+				depIdx, ok := s.barIndex[dep]
+				if !ok || bars[depIdx].MeanDate.IsZero() || (lag == 0 && !s.moved(dep)) || cycles.shared(task.ID, dep) {
+					continue
+				}
+				if start := addWorkingDays(bars[depIdx].MeanDate, lag); start.After(earliest) {
+					earliest = start
+				}
+			}
+			for _, other := range tasks {
+				if other.ID == task.ID || other.User != task.User {
+					continue
+				}
+				if _, ok := s.barIndex[other.ID]; !ok || !s.moved(other.ID) {
+					continue
+				}
+				// Only bars queued behind the other bar wait for it
+				if s.origStart[task.ID].Before(s.origMean[other.ID]) {
+					continue
+				}
+				if mean := bars[s.barIndex[other.ID]].MeanDate; mean.After(earliest) {
+					earliest = mean
+				}
+			}
+
+			if bar.ExpStartDate.Before(earliest) {
+				shift := workingDaysBetween(bar.ExpStartDate, earliest)
+				bar.ExpStartDate = earliest
+				bar.MeanDate = addWorkingDays(bar.MeanDate, shift)
+				bar.End98Date = addWorkingDays(bar.End98Date, shift)
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// taskCycles maps each task ID on a dependency cycle to the cycle it is on
+type taskCycles map[string]int
+
+// shared reports whether tasks a and b are on the same dependency cycle
+func (c taskCycles) shared(a, b string) bool {
+	n, ok := c[a]
+	return ok && c[b] == n
+}
+
+// dependencyCycles finds the tasks on dependency cycles. Cycles that share a
+// task get the same number.
+func dependencyCycles(tasks []planner.Task) taskCycles {
+	deps := make(map[string][]string, len(tasks))
+	for _, task := range tasks {
+		deps[task.ID] = task.DependsOn
+	}
+
+	// Tarjan's strongly connected components
+	index := make(map[string]int, len(tasks))
+	low := make(map[string]int, len(tasks))
+	onStack := make(map[string]bool)
+	var stack []string
+	cycles := make(taskCycles)
+	n := 0
+	var visit func(id string)
+	visit = func(id string) {
+		index[id] = len(index) + 1
+		low[id] = index[id]
+		stack = append(stack, id)
+		onStack[id] = true
+		for _, dep := range deps[id] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			if index[dep] == 0 {
+				visit(dep)
+				low[id] = min(low[id], low[dep])
+			} else if onStack[dep] {
+				low[id] = min(low[id], index[dep])
+			}
+		}
+		if low[id] != index[id] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == id {
+				break
+			}
+		}
+		if len(component) > 1 {
+			n++
+			for _, member := range component {
+				cycles[member] = n
+			}
+		}
+	}
+	for _, task := range tasks {
+		if index[task.ID] == 0 {
+			visit(task.ID)
+		}
+	}
+	return cycles
+}
@@ -0,0 +1,67 @@
+package p2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestApplyEarliestStarts_DelaysTaskAndItsDependents(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2026, time.February, d, 0, 0, 0, 0, time.UTC) }
+
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", User: "alice"},
+		{ID: "owner/repo#2", User: "bob", DependsOn: []string{"owner/repo#1"}},
+		{ID: "owner/repo#3", User: "alice"},
+		{ID: "owner/repo#4", User: "carol"},
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			// Waits for a vendor delivery on Wednesday Feb 11
+			{ID: "owner/repo#1", ExpStartDate: date(2), MeanDate: date(4), End98Date: date(6)},
+			// Depends on #1
+			{ID: "owner/repo#2", ExpStartDate: date(4), MeanDate: date(6), End98Date: date(9)},
+			// Queued behind #1 in alice's work
+			{ID: "owner/repo#3", ExpStartDate: date(4), MeanDate: date(5), End98Date: date(6)},
+			// Unrelated
+			{ID: "owner/repo#4", ExpStartDate: date(2), MeanDate: date(3), End98Date: date(4)},
+		},
+	}
+
+	delayed := ApplyEarliestStarts(&ganttData, tasks, map[string]time.Time{"owner/repo#1": date(11)})
+	if delayed != 1 {
+		t.Errorf("expected 1 issue delayed by its Earliest Start, got %d", delayed)
+	}
+
+	constrained := ganttData.Bars[0]
+	if !constrained.ExpStartDate.Equal(date(11)) || !constrained.MeanDate.Equal(date(13)) || !constrained.End98Date.Equal(date(17)) {
+		t.Errorf("expected #1 to run Feb 11-13 (98%% Feb 17), got %s-%s (98%% %s)",
+			constrained.ExpStartDate.Format("2006-01-02"), constrained.MeanDate.Format("2006-01-02"), constrained.End98Date.Format("2006-01-02"))
+	}
+	if dependent := ganttData.Bars[1]; !dependent.ExpStartDate.Equal(date(13)) {
+		t.Errorf("expected dependent #2 to start when #1 completes, got %s", dependent.ExpStartDate.Format("2006-01-02"))
+	}
+	if queued := ganttData.Bars[2]; !queued.ExpStartDate.Equal(date(13)) {
+		t.Errorf("expected alice's next issue #3 to start when #1 completes, got %s", queued.ExpStartDate.Format("2006-01-02"))
+	}
+	if unrelated := ganttData.Bars[3]; !unrelated.ExpStartDate.Equal(date(2)) {
+		t.Error("expected unrelated #4 to be unchanged")
+	}
+}
+
+func TestApplyEarliestStarts_PastDateIsNoOp(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2026, time.February, d, 0, 0, 0, 0, time.UTC) }
+
+	tasks := []planner.Task{{ID: "owner/repo#1"}}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{{ID: "owner/repo#1", ExpStartDate: date(9), MeanDate: date(10), End98Date: date(11)}},
+	}
+
+	if delayed := ApplyEarliestStarts(&ganttData, tasks, map[string]time.Time{"owner/repo#1": date(2)}); delayed != 0 {
+		t.Errorf("expected no delay, got %d", delayed)
+	}
+	if !ganttData.Bars[0].ExpStartDate.Equal(date(9)) {
+		t.Error("expected bar to be unchanged")
+	}
+}
@@ -0,0 +1,161 @@
+package p2
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EstimateRow is one issue's estimates from an estimates file
+type EstimateRow struct {
+	Owner    string
+	Repo     string
+	IssueNum int
+	Low      float64
+	High     float64
+}
+
+// Ref returns the row's issue as "owner/repo#N"
+func (r EstimateRow) Ref() string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.IssueNum)
+}
+
+// LoadEstimateCSV reads rows of "owner/repo#N,low,high" from path.
+// A header row whose first field isn't an issue reference is skipped.
+func LoadEstimateCSV(path string) ([]EstimateRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read estimates file: %w", err)
+	}
+	defer f.Close()
+	return ParseEstimateCSV(f)
+}
+
+// ParseEstimateCSV parses rows of "owner/repo#N,low,high" from r
+func ParseEstimateCSV(r io.Reader) ([]EstimateRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+
+	var rows []EstimateRow
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid estimates file: %w", err)
+		}
+
+		ref, ok := parseIssueRef(record[0])
+		if !ok {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: invalid issue %q: expected owner/repo#N", line, record[0])
+		}
+		low, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid low estimate %q", line, record[1])
+		}
+		high, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid high estimate %q", line, record[2])
+		}
+		if high < low {
+			return nil, fmt.Errorf("line %d: high estimate %g is less than low estimate %g", line, high, low)
+		}
+		rows = append(rows, EstimateRow{Owner: ref.Owner, Repo: ref.Repo, IssueNum: ref.Number, Low: low, High: high})
+	}
+}
+
+// estimateLabelHoursPerDay converts estimate labels given in days ("3d") to hours
+const estimateLabelHoursPerDay = 8
+
+// ApplyEstimateLabels fills in LowEstimate and HighEstimate from labels
+// matching lowPattern and highPattern, for issues whose project fields leave
+// them unset; project fields always win. Closed issues are skipped, since
+// their estimates are only read to decide whether the fields need clearing.
+// Each pattern must capture the estimate in a group named "hours", optionally
+// suffixed with "h" or "d" (8 hours). An empty pattern is skipped. It returns
+// the number of issues given an estimate from a label.
+func ApplyEstimateLabels(issues map[string]IssueWithProject, lowPattern, highPattern string) (int, error) {
+	lowRe, err := compileEstimateLabelPattern(lowPattern)
+	if err != nil {
+		return 0, err
+	}
+	highRe, err := compileEstimateLabelPattern(highPattern)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for ref, iwp := range issues {
+		if strings.EqualFold(iwp.State, "closed") {
+			continue
+		}
+		updated := false
+		if iwp.LowEstimate == nil {
+			if v, ok := estimateFromLabels(iwp.Labels, lowRe); ok {
+				iwp.LowEstimate = &v
+				updated = true
+			}
+		}
+		if iwp.HighEstimate == nil {
+			if v, ok := estimateFromLabels(iwp.Labels, highRe); ok {
+				iwp.HighEstimate = &v
+				updated = true
+			}
+		}
+		if updated {
+			issues[ref] = iwp
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// compileEstimateLabelPattern compiles an estimate label pattern, returning nil for ""
+func compileEstimateLabelPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid estimate label pattern: %w", err)
+	}
+	if re.SubexpIndex("hours") < 0 {
+		return nil, fmt.Errorf("estimate label pattern must have a named group \"hours\"")
+	}
+	return re, nil
+}
+
+// estimateFromLabels returns the hours of the first label matching re
+func estimateFromLabels(labels []string, re *regexp.Regexp) (float64, bool) {
+	if re == nil {
+		return 0, false
+	}
+	for _, label := range labels {
+		m := re.FindStringSubmatch(label)
+		if m == nil {
+			continue
+		}
+		value := m[re.SubexpIndex("hours")]
+		factor := 1.0
+		if v, ok := strings.CutSuffix(value, "d"); ok {
+			value, factor = v, estimateLabelHoursPerDay
+		} else {
+			value = strings.TrimSuffix(value, "h")
+		}
+		hours, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		return hours * factor, true
+	}
+	return 0, false
+}
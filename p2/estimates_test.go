@@ -0,0 +1,103 @@
+package p2
+
+import (
+	"strings"
+	"testing"
+)
+
+// Patterns for estimate labels such as "est-low:2" and "est-high:3d"
+const (
+	estLowLabelPattern  = `^est-low:(?P<hours>\d+(?:\.\d+)?[hd]?)$`
+	estHighLabelPattern = `^est-high:(?P<hours>\d+(?:\.\d+)?[hd]?)$`
+)
+
+func TestParseEstimateCSV(t *testing.T) {
+	input := "issue,low,high\nowner/repo#1, 2, 4\nowner/other#7,0.5,1\n"
+	rows, err := ParseEstimateCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].Ref() != "owner/repo#1" || rows[0].Low != 2 || rows[0].High != 4 {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Ref() != "owner/other#7" || rows[1].Low != 0.5 {
+		t.Errorf("unexpected second row: %+v", rows[1])
+	}
+}
+
+func TestParseEstimateCSV_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"owner/repo#1,4,2\n",
+		"owner/repo#1,2,4\nnot-a-ref,1,2\n",
+		"owner/repo#1,two,4\n",
+	} {
+		if _, err := ParseEstimateCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestApplyEstimateLabels_FillsUnsetFields(t *testing.T) {
+	fieldLow := 1.0
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			Labels:   []string{"backend", "est-low:2", "est-high:3d"},
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:       "owner",
+			Repo:        "repo",
+			IssueNum:    2,
+			Labels:      []string{"est-low:4h", "est-high:6"},
+			LowEstimate: &fieldLow,
+		},
+		"github.com/owner/repo/issues/3": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 3,
+			Labels:   []string{"frontend"},
+		},
+		"github.com/owner/repo/issues/4": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 4,
+			State:    "closed",
+			Labels:   []string{"est-low:2", "est-high:3"},
+		},
+	}
+
+	n, err := ApplyEstimateLabels(issues, estLowLabelPattern, estHighLabelPattern)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 issues given label estimates, got %d", n)
+	}
+
+	first := issues["github.com/owner/repo/issues/1"]
+	if first.LowEstimate == nil || *first.LowEstimate != 2 || first.HighEstimate == nil || *first.HighEstimate != 24 {
+		t.Errorf("expected estimates 2-24, got %v-%v", first.LowEstimate, first.HighEstimate)
+	}
+	second := issues["github.com/owner/repo/issues/2"]
+	if *second.LowEstimate != 1 || second.HighEstimate == nil || *second.HighEstimate != 6 {
+		t.Errorf("expected project field Low Estimate to win over its label, got %v-%v", *second.LowEstimate, second.HighEstimate)
+	}
+	if third := issues["github.com/owner/repo/issues/3"]; third.LowEstimate != nil || third.HighEstimate != nil {
+		t.Errorf("expected unlabeled issue to stay unestimated, got %+v", third)
+	}
+	// A closed issue's label estimates would look like field values to clear
+	if fourth := issues["github.com/owner/repo/issues/4"]; fourth.LowEstimate != nil || fourth.HighEstimate != nil {
+		t.Errorf("expected closed issue to be skipped, got %v-%v", fourth.LowEstimate, fourth.HighEstimate)
+	}
+}
+
+func TestApplyEstimateLabels_PatternRequiresHoursGroup(t *testing.T) {
+	if _, err := ApplyEstimateLabels(map[string]IssueWithProject{}, `^est-low:(\d+)$`, ""); err == nil {
+		t.Error("expected error for pattern without an \"hours\" group")
+	}
+}
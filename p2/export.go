@@ -0,0 +1,192 @@
+package p2
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// ScheduleRow is one issue of the computed schedule in tabular form
+type ScheduleRow struct {
+	Owner              string
+	Repo               string
+	IssueNum           int
+	Title              string
+	Assignee           string
+	ExpectedStart      time.Time
+	ExpectedCompletion time.Time
+	Completion98       time.Time
+	Milestone          string
+	OnHold             bool
+	Done               bool
+}
+
+// Status returns "closed", "on hold", or "scheduled"
+func (r ScheduleRow) Status() string {
+	switch {
+	case r.Done:
+		return "closed"
+	case r.OnHold:
+		return "on hold"
+	default:
+		return "scheduled"
+	}
+}
+
+// scheduleHeader is the column header shared by the tabular exports
+var scheduleHeader = []string{
+	"Owner",
+	"Repo",
+	"Issue",
+	"Title",
+	"Assignee",
+	"Expected Start",
+	"Expected Completion",
+	"98% Completion",
+	"Milestone",
+	"On Hold",
+	"Done",
+	"Status",
+}
+
+// fields returns the row's column values in scheduleHeader order
+func (r ScheduleRow) fields() []string {
+	return []string{
+		r.Owner,
+		r.Repo,
+		strconv.Itoa(r.IssueNum),
+		r.Title,
+		r.Assignee,
+		formatDate(r.ExpectedStart),
+		formatDate(r.ExpectedCompletion),
+		formatDate(r.Completion98),
+		r.Milestone,
+		strconv.FormatBool(r.OnHold),
+		strconv.FormatBool(r.Done),
+		r.Status(),
+	}
+}
+
+// formatDate formats t as YYYY-MM-DD, or "" if t is zero
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// ScheduleRows builds one row per scheduled (non-package, non-draft) issue.
+// Closed and on-hold issues have blank dates. If privacy is non-nil, the
+// repo and title of private repos other than the current one are redacted.
+func ScheduleRows(ganttData planner.GanttData, issues map[string]IssueWithProject, privacy *PrivacyFilter) []ScheduleRow {
+	return ScheduleRowsWithOptions(ganttData, issues, privacy, ConvertOptions{})
+}
+
+// ScheduleRowsWithOptions is ScheduleRows with the on-hold statuses and
+// Scheduling Status behaviors of opts deciding which issues are on hold
+func ScheduleRowsWithOptions(ganttData planner.GanttData, issues map[string]IssueWithProject, privacy *PrivacyFilter, opts ConvertOptions) []ScheduleRow {
+	taskToIssue := make(map[string]IssueWithProject)
+	for _, iwp := range issues {
+		if iwp.IsDraft {
+			continue
+		}
+		taskID := fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
+		taskToIssue[taskID] = iwp
+	}
+
+	var rows []ScheduleRow
+	for _, bar := range ganttData.Bars {
+		if bar.IsPackage {
+			continue
+		}
+		iwp, ok := taskToIssue[bar.ID]
+		if !ok {
+			continue
+		}
+
+		row := ScheduleRow{
+			Owner:     iwp.Owner,
+			Repo:      iwp.Repo,
+			IssueNum:  iwp.IssueNum,
+			Title:     iwp.Title,
+			Assignee:  iwp.Assignee,
+			Milestone: iwp.Milestone,
+			OnHold:    bar.OnHold || opts.IsHeld(iwp.SchedulingStatus),
+			Done:      bar.Done || strings.EqualFold(iwp.State, "closed"),
+		}
+		if !row.OnHold && !row.Done {
+			row.ExpectedStart = bar.ExpStartDate
+			row.ExpectedCompletion = bar.MeanDate
+			row.Completion98 = bar.End98Date
+		}
+		if privacy != nil && privacy.ShouldRedact(iwp.Owner, iwp.Repo) {
+			row.Owner = "[private]"
+			row.Repo = "[private]"
+			row.Title = ""
+			row.Milestone = ""
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteTSV writes rows as tab-separated values with a header line.
+// Fields are not quoted; tabs and newlines within values are replaced
+// with spaces so the output pastes cleanly into a spreadsheet.
+func WriteTSV(w io.Writer, rows []ScheduleRow) error {
+	if _, err := fmt.Fprintln(w, strings.Join(scheduleHeader, "\t")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		fields := row.fields()
+		for i, f := range fields {
+			fields[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(f)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes rows as comma-separated values with a header line,
+// quoting fields as needed
+func WriteCSV(w io.Writer, rows []ScheduleRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(scheduleHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(row.fields()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown writes rows as a Markdown table
+func WriteMarkdown(w io.Writer, rows []ScheduleRow) error {
+	sep := make([]string, len(scheduleHeader))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	if _, err := fmt.Fprintf(w, "| %s |\n|%s|\n", strings.Join(scheduleHeader, " | "), strings.Join(sep, "|")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		fields := row.fields()
+		for i, f := range fields {
+			fields[i] = strings.NewReplacer("|", "\\|", "\r", " ", "\n", " ").Replace(f)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(fields, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
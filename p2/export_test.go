@@ -0,0 +1,157 @@
+package p2
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func testExportData() (planner.GanttData, map[string]IssueWithProject) {
+	issues := map[string]IssueWithProject{
+		"github.com/myorg/myrepo/issues/1": {
+			Owner:     "myorg",
+			Repo:      "myrepo",
+			IssueNum:  1,
+			Title:     "Active\tTask",
+			State:     "open",
+			Assignee:  "cwarden",
+			Milestone: "v1.0.0",
+		},
+		"github.com/myorg/myrepo/issues/2": {
+			Owner:    "myorg",
+			Repo:     "myrepo",
+			IssueNum: 2,
+			Title:    "Closed Task",
+			State:    "closed",
+		},
+		"github.com/myorg/secret/issues/3": {
+			Owner:     "myorg",
+			Repo:      "secret",
+			IssueNum:  3,
+			Title:     "Secret Task",
+			State:     "open",
+			IsPrivate: true,
+		},
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			{ID: "v1.0.0", Name: "v1.0.0", IsPackage: true},
+			{ID: "myorg/myrepo#1", Name: "Active Task", ExpStartDate: testStart, MeanDate: testMean, End98Date: testEnd98},
+			{ID: "myorg/myrepo#2", Name: "Closed Task", Done: true, ExpStartDate: testStart, MeanDate: testMean, End98Date: testEnd98},
+			{ID: "myorg/secret#3", Name: "Secret Task", ExpStartDate: otherStart, MeanDate: otherMean, End98Date: otherEnd98},
+		},
+	}
+	return ganttData, issues
+}
+
+func TestWriteTSV_HeaderAndTabSeparatedFields(t *testing.T) {
+	ganttData, issues := testExportData()
+	privacy := NewPrivacyFilter("myorg/myrepo", issues)
+
+	var buf bytes.Buffer
+	if err := WriteTSV(&buf, ScheduleRows(ganttData, issues, privacy)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header plus 3 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+
+	wantHeader := "Owner\tRepo\tIssue\tTitle\tAssignee\tExpected Start\tExpected Completion\t98% Completion\tMilestone\tOn Hold\tDone\tStatus"
+	if lines[0] != wantHeader {
+		t.Errorf("unexpected header:\n got: %q\nwant: %q", lines[0], wantHeader)
+	}
+
+	active := strings.Split(lines[1], "\t")
+	if len(active) != 12 {
+		t.Fatalf("expected 12 tab-separated fields, got %d: %q", len(active), lines[1])
+	}
+	if active[3] != "Active Task" {
+		t.Errorf("expected tab in title to be replaced, got %q", active[3])
+	}
+	if active[6] != "2026-02-10" {
+		t.Errorf("expected Expected Completion 2026-02-10, got %q", active[6])
+	}
+	if strings.Contains(lines[1], `"`) {
+		t.Error("TSV fields should not be quoted")
+	}
+
+	closed := strings.Split(lines[2], "\t")
+	if closed[5] != "" || closed[11] != "closed" {
+		t.Errorf("expected closed row to have blank dates and closed status, got %q", lines[2])
+	}
+
+	secret := strings.Split(lines[3], "\t")
+	if secret[1] != "[private]" || secret[3] != "" {
+		t.Errorf("expected private repo row to be redacted, got %q", lines[3])
+	}
+}
+
+func TestWriteCSV_QuotedFieldsWithRedaction(t *testing.T) {
+	ganttData, issues := testExportData()
+	privacy := NewPrivacyFilter("myorg/myrepo", issues)
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, ScheduleRows(ganttData, issues, privacy)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected header plus 3 rows, got %d", len(records))
+	}
+	if records[0][0] != "Owner" || records[0][11] != "Status" {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	if records[1][3] != "Active\tTask" || records[1][5] != "2026-02-05" || records[1][8] != "v1.0.0" {
+		t.Errorf("unexpected active row: %v", records[1])
+	}
+	if records[2][5] != "" || records[2][11] != "closed" {
+		t.Errorf("expected closed row to have blank dates and closed status, got %v", records[2])
+	}
+	if records[3][1] != "[private]" || records[3][3] != "" {
+		t.Errorf("expected private repo row to be redacted, got %v", records[3])
+	}
+}
+
+func TestWriteMarkdown_TableWithRedaction(t *testing.T) {
+	ganttData, issues := testExportData()
+	privacy := NewPrivacyFilter("myorg/myrepo", issues)
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, ScheduleRows(ganttData, issues, privacy)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header, separator, and 3 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "| Owner | Repo | Issue | Title |") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if strings.Contains(buf.String(), "Secret Task") {
+		t.Error("expected private title to be redacted")
+	}
+}
+
+func TestScheduleRowsWithOptions_CustomOnHoldStatus(t *testing.T) {
+	ganttData, issues := testExportData()
+	active := issues["github.com/myorg/myrepo/issues/1"]
+	active.SchedulingStatus = "Parked"
+	issues["github.com/myorg/myrepo/issues/1"] = active
+
+	rows := ScheduleRowsWithOptions(ganttData, issues, nil, ConvertOptions{OnHoldStatuses: []string{"Parked"}})
+	for _, row := range rows {
+		if row.IssueNum == 1 && (!row.OnHold || !row.ExpectedStart.IsZero()) {
+			t.Errorf("expected the parked issue to be on hold with blank dates, got %+v", row)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package p2
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldNames maps the project fields the scheduler reads and writes to the
+// names they have in a particular project
+type FieldNames struct {
+	LowEstimate        string `yaml:"low-estimate"`
+	HighEstimate       string `yaml:"high-estimate"`
+	ExpectedStart      string `yaml:"expected-start"`
+	ExpectedCompletion string `yaml:"expected-completion"`
+	Completion98       string `yaml:"completion-98"`
+}
+
+// DefaultFieldNames are the field names set up by the p2 GitHub App
+var DefaultFieldNames = FieldNames{
+	LowEstimate:        "Low Estimate",
+	HighEstimate:       "High Estimate",
+	ExpectedStart:      "Expected Start",
+	ExpectedCompletion: "Expected Completion",
+	Completion98:       "98% Completion",
+}
+
+// WithDefaults returns f with unset names replaced by DefaultFieldNames
+func (f FieldNames) WithDefaults() FieldNames {
+	if f.LowEstimate == "" {
+		f.LowEstimate = DefaultFieldNames.LowEstimate
+	}
+	if f.HighEstimate == "" {
+		f.HighEstimate = DefaultFieldNames.HighEstimate
+	}
+	if f.ExpectedStart == "" {
+		f.ExpectedStart = DefaultFieldNames.ExpectedStart
+	}
+	if f.ExpectedCompletion == "" {
+		f.ExpectedCompletion = DefaultFieldNames.ExpectedCompletion
+	}
+	if f.Completion98 == "" {
+		f.Completion98 = DefaultFieldNames.Completion98
+	}
+	return f
+}
+
+// Set sets the name for role, one of the yaml keys of FieldNames (e.g. "low-estimate")
+func (f *FieldNames) Set(role, name string) error {
+	switch role {
+	case "low-estimate":
+		f.LowEstimate = name
+	case "high-estimate":
+		f.HighEstimate = name
+	case "expected-start":
+		f.ExpectedStart = name
+	case "expected-completion":
+		f.ExpectedCompletion = name
+	case "completion-98":
+		f.Completion98 = name
+	default:
+		return fmt.Errorf("unknown field role %q: expected low-estimate, high-estimate, expected-start, expected-completion, or completion-98", role)
+	}
+	return nil
+}
+
+// schedulerConfig is the format of a .p2scheduler.yml file. Flag defaults
+// sit beside fields and are read by LoadConfigValues.
+type schedulerConfig struct {
+	Fields FieldNames           `yaml:"fields"`
+	Flags  map[string]yaml.Node `yaml:",inline"`
+}
+
+// LoadFieldNames reads field names from the "fields" section of a config file
+// such as .p2scheduler.yml:
+//
+//	fields:
+//	  low-estimate: Est. Low
+//	  expected-start: Forecast Start
+//
+// A missing file yields DefaultFieldNames. Names not in the file keep their
+// defaults. An unknown key under fields is an error.
+func LoadFieldNames(path string) (FieldNames, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultFieldNames, nil
+	}
+	if err != nil {
+		return FieldNames{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var config schedulerConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return FieldNames{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return config.Fields.WithDefaults(), nil
+}
@@ -0,0 +1,85 @@
+package p2
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFieldNames_OverridesAndDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".p2scheduler.yml")
+	content := `fields:
+  low-estimate: Est. Low
+  high-estimate: Est. High
+  expected-start: Forecast Start
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := LoadFieldNames(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names.LowEstimate != "Est. Low" || names.HighEstimate != "Est. High" || names.ExpectedStart != "Forecast Start" {
+		t.Errorf("expected configured names, got %+v", names)
+	}
+	if names.ExpectedCompletion != "Expected Completion" || names.Completion98 != "98% Completion" {
+		t.Errorf("expected unset names to keep defaults, got %+v", names)
+	}
+}
+
+func TestLoadFieldNames_MissingFileUsesDefaults(t *testing.T) {
+	names, err := LoadFieldNames(filepath.Join(t.TempDir(), "missing.yml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names != DefaultFieldNames {
+		t.Errorf("expected defaults, got %+v", names)
+	}
+}
+
+func TestLoadFieldNames_UnknownFieldKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".p2scheduler.yml")
+	content := `base-date: 2025-01-06
+fields:
+  low-estimat: Est. Low
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadFieldNames(path)
+	if err == nil {
+		t.Fatal("expected error for misspelled field key")
+	}
+	if !strings.Contains(err.Error(), "low-estimat") {
+		t.Errorf("expected error to name the bad key, got %v", err)
+	}
+}
+
+func TestLoadFieldNames_EmptyFileUsesDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".p2scheduler.yml")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := LoadFieldNames(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names != DefaultFieldNames {
+		t.Errorf("expected defaults, got %+v", names)
+	}
+}
+
+func TestFieldNames_SetUnknownRole(t *testing.T) {
+	var names FieldNames
+	if err := names.Set("due-date", "Deadline"); err == nil {
+		t.Error("expected error for unknown role")
+	}
+	if err := names.Set("completion-98", "P98"); err != nil || names.Completion98 != "P98" {
+		t.Errorf("expected completion-98 to be set, got %+v (err=%v)", names, err)
+	}
+}
@@ -0,0 +1,115 @@
+package p2
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteDependencyDOT writes the blocked-by graph of issues as a Graphviz DOT
+// digraph. Each edge points from a blocker to the issue it blocks. Closed and
+// on-hold issues are filled grey and yellow, and issues named in a "cycle"
+// scheduling issue are outlined in red. Blockers that are not in issues are
+// drawn dashed. If privacy is non-nil, titles of private repos other than the
+// current one are omitted and their references redacted.
+func WriteDependencyDOT(w io.Writer, issues map[string]IssueWithProject, schedIssues []SchedulingIssue, privacy *PrivacyFilter) error {
+	return WriteDependencyDOTWithOptions(w, issues, schedIssues, privacy, ConvertOptions{})
+}
+
+// WriteDependencyDOTWithOptions is WriteDependencyDOT with the on-hold
+// statuses and Scheduling Status behaviors of opts deciding which issues are
+// on hold
+func WriteDependencyDOTWithOptions(w io.Writer, issues map[string]IssueWithProject, schedIssues []SchedulingIssue, privacy *PrivacyFilter, opts ConvertOptions) error {
+	inCycle := make(map[string]bool)
+	for _, si := range schedIssues {
+		if si.Reason != "cycle" {
+			continue
+		}
+		inCycle[fmt.Sprintf("%s/%s#%d", si.Owner, si.Repo, si.IssueNum)] = true
+		for _, id := range si.Details {
+			inCycle[id] = true
+		}
+	}
+
+	refs := make([]string, 0, len(issues))
+	for ref, iwp := range issues {
+		if !iwp.IsDraft {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+
+	// Node IDs are assigned sequentially so redacted references stay distinct
+	nodeIDs := make(map[string]string)
+	nodeID := func(taskID string) string {
+		if id, ok := nodeIDs[taskID]; ok {
+			return id
+		}
+		id := "n" + strconv.Itoa(len(nodeIDs))
+		nodeIDs[taskID] = id
+		return id
+	}
+	redact := func(taskID string) string {
+		if privacy == nil {
+			return taskID
+		}
+		return privacy.RedactDepID(taskID)
+	}
+
+	if _, err := fmt.Fprintln(w, "digraph dependencies {\n\trankdir=LR;\n\tnode [shape=box];"); err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		iwp := issues[ref]
+		taskID := fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
+		label := redact(taskID)
+		title := iwp.Title
+		if privacy != nil {
+			title = privacy.RedactTitle(iwp.Owner, iwp.Repo, title)
+		}
+		if title != "" {
+			label += ": " + title
+		}
+
+		attrs := []string{"label=" + strconv.Quote(label)}
+		switch {
+		case strings.EqualFold(iwp.State, "closed"):
+			attrs = append(attrs, `style=filled`, `fillcolor="#dddddd"`)
+		case opts.IsHeld(iwp.SchedulingStatus):
+			attrs = append(attrs, `style=filled`, `fillcolor="#fff2a8"`)
+		}
+		if inCycle[taskID] {
+			attrs = append(attrs, `color=red`, `penwidth=2`)
+		}
+		if _, err := fmt.Fprintf(w, "\t%s [%s];\n", nodeID(taskID), strings.Join(attrs, ", ")); err != nil {
+			return err
+		}
+	}
+
+	known := make(map[string]bool, len(nodeIDs))
+	for taskID := range nodeIDs {
+		known[taskID] = true
+	}
+	for _, ref := range refs {
+		iwp := issues[ref]
+		blocked := nodeID(fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum))
+		for _, dep := range iwp.BlockedBy {
+			depID := fmt.Sprintf("%s/%s#%d", dep.Owner, dep.Repo, dep.Number)
+			if !known[depID] {
+				known[depID] = true
+				if _, err := fmt.Fprintf(w, "\t%s [label=%s, style=dashed];\n", nodeID(depID), strconv.Quote(redact(depID))); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "\t%s -> %s;\n", nodeID(depID), blocked); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
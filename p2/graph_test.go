@@ -0,0 +1,70 @@
+package p2
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDependencyDOT(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner: "owner", Repo: "repo", IssueNum: 1, Title: "Blocker", State: "closed",
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner: "owner", Repo: "repo", IssueNum: 2, Title: "Blocked", State: "open",
+			SchedulingStatus: "On Hold",
+			BlockedBy: []IssueRef{
+				{Owner: "owner", Repo: "repo", Number: 1},
+				{Owner: "infra", Repo: "tickets", Number: 9},
+			},
+		},
+		"github.com/owner/secret/issues/3": {
+			Owner: "owner", Repo: "secret", IssueNum: 3, Title: "Hidden", State: "open", IsPrivate: true,
+		},
+	}
+	schedIssues := []SchedulingIssue{{Owner: "owner", Repo: "secret", IssueNum: 3, Reason: "cycle"}}
+	privacy := NewPrivacyFilter("owner/repo", issues)
+
+	var buf bytes.Buffer
+	if err := WriteDependencyDOT(&buf, issues, schedIssues, privacy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`n0 [label="owner/repo#1: Blocker", style=filled, fillcolor="#dddddd"];`,
+		`n1 [label="owner/repo#2: Blocked", style=filled, fillcolor="#fff2a8"];`,
+		`n2 [label="[private]#3", color=red, penwidth=2];`,
+		`n0 -> n1;`,
+		`n3 [label="infra/tickets#9", style=dashed];`,
+		`n3 -> n1;`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Hidden") {
+		t.Errorf("expected private title to be redacted, got:\n%s", out)
+	}
+}
+
+func TestWriteDependencyDOTWithOptions_CustomOnHoldStatus(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, Title: "Parked", State: "open", SchedulingStatus: "Parked"},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, Title: "Old status", State: "open", SchedulingStatus: "On Hold"},
+	}
+
+	var buf bytes.Buffer
+	opts := ConvertOptions{OnHoldStatuses: []string{"Parked"}}
+	if err := WriteDependencyDOTWithOptions(&buf, issues, nil, nil, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `n0 [label="owner/repo#1: Parked", style=filled, fillcolor="#fff2a8"];`) {
+		t.Errorf("expected the parked issue to be styled on hold, got:\n%s", out)
+	}
+	if !strings.Contains(out, `n1 [label="owner/repo#2: Old status"];`) {
+		t.Errorf("expected \"On Hold\" not to be styled when not configured, got:\n%s", out)
+	}
+}
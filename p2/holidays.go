@@ -0,0 +1,77 @@
+package p2
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Holiday is a day with no availability. It applies to every user unless
+// User is set.
+type Holiday struct {
+	Date time.Time `yaml:"date"`
+	User string    `yaml:"user"`
+}
+
+// LoadHolidays reads holidays from a CSV file with a date column and an
+// optional user column:
+//
+//	date,user
+//	2025-12-25
+//	2025-12-26,alice
+//
+// A header row is skipped if its first column isn't a date.
+func LoadHolidays(path string) ([]Holiday, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	var holidays []Holiday
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse holidays file %s: %w", path, err)
+		}
+		field := strings.TrimSpace(record[0])
+		if field == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", field)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("invalid date %q on line %d of %s: expected YYYY-MM-DD", field, line, path)
+		}
+		h := Holiday{Date: date}
+		if len(record) > 1 {
+			h.User = strings.TrimSpace(record[1])
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, nil
+}
+
+// windowsFor returns user's holidays as zero-percent windows, followed by their
+// ramp windows. Holidays come first so they win over an overlapping ramp.
+func (a *Availability) windowsFor(user string) []RampWindow {
+	var windows []RampWindow
+	for _, h := range a.Holidays {
+		if h.User == "" || h.User == user {
+			windows = append(windows, RampWindow{From: h.Date, Until: h.Date})
+		}
+	}
+	return append(windows, a.Ramps[user]...)
+}
@@ -0,0 +1,103 @@
+package p2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestLoadHolidays_HeaderAndUserColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.csv")
+	content := "date,user\n2025-12-25\n2025-12-26, alice\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	holidays, err := LoadHolidays(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(holidays) != 2 {
+		t.Fatalf("expected 2 holidays, got %d", len(holidays))
+	}
+	if h := holidays[0]; formatDate(h.Date) != "2025-12-25" || h.User != "" {
+		t.Errorf("expected org-wide holiday on 2025-12-25, got %+v", h)
+	}
+	if h := holidays[1]; formatDate(h.Date) != "2025-12-26" || h.User != "alice" {
+		t.Errorf("expected alice's holiday on 2025-12-26, got %+v", h)
+	}
+}
+
+func TestLoadHolidays_InvalidDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.csv")
+	if err := os.WriteFile(path, []byte("2025-12-25\n12/26/2025\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHolidays(path); err == nil {
+		t.Error("expected error for a date that isn't YYYY-MM-DD")
+	}
+}
+
+func TestApplyRamps_HolidayWeekShiftsDates(t *testing.T) {
+	var holidays []Holiday
+	for d := 22; d <= 26; d++ {
+		holidays = append(holidays, Holiday{Date: time.Date(2025, 12, d, 0, 0, 0, 0, time.UTC)})
+	}
+	holidays = append(holidays, Holiday{Date: time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC), User: "bob"})
+	avail := &Availability{Holidays: holidays}
+
+	// A four-day task that would finish during the holiday week
+	start := time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC)
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", User: "alice"},
+		{ID: "owner/repo#2", User: "alice"},
+	}
+	ganttData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: start, MeanDate: addWorkingDays(start, 4), End98Date: addWorkingDays(start, 6)},
+		{ID: "owner/repo#2", ExpStartDate: addWorkingDays(start, 4), MeanDate: addWorkingDays(start, 6), End98Date: addWorkingDays(start, 8)},
+	}}
+
+	ApplyRamps(&ganttData, tasks, avail)
+
+	first, second := ganttData.Bars[0], ganttData.Bars[1]
+	if want := "2026-01-01"; formatDate(first.MeanDate) != want {
+		t.Errorf("expected Expected Completion to move past the holiday week to %s, got %s", want, formatDate(first.MeanDate))
+	}
+	if want := "2026-01-05"; formatDate(first.End98Date) != want {
+		t.Errorf("expected 98%% Completion to shift by the same 5 working days to %s, got %s", want, formatDate(first.End98Date))
+	}
+	if !second.ExpStartDate.Equal(first.MeanDate) {
+		t.Errorf("expected queued task to start at %s, got %s", formatDate(first.MeanDate), formatDate(second.ExpStartDate))
+	}
+}
+
+func TestApplyRamps_HolidayDelaysOtherUsersDependents(t *testing.T) {
+	avail := &Availability{Holidays: []Holiday{
+		{Date: time.Date(2025, 12, 22, 0, 0, 0, 0, time.UTC), User: "alice"},
+		{Date: time.Date(2025, 12, 23, 0, 0, 0, 0, time.UTC), User: "alice"},
+	}}
+
+	start := time.Date(2025, 12, 18, 0, 0, 0, 0, time.UTC)
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", User: "alice"},
+		// bob isn't on holiday, but his issue waits for alice's
+		{ID: "owner/repo#2", User: "bob", DependsOn: []string{"owner/repo#1"}},
+	}
+	ganttData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: start, MeanDate: addWorkingDays(start, 3), End98Date: addWorkingDays(start, 5)},
+		{ID: "owner/repo#2", ExpStartDate: addWorkingDays(start, 3), MeanDate: addWorkingDays(start, 5), End98Date: addWorkingDays(start, 7)},
+	}}
+
+	ApplyRamps(&ganttData, tasks, avail)
+
+	blocker, dependent := ganttData.Bars[0], ganttData.Bars[1]
+	if want := "2025-12-25"; formatDate(blocker.MeanDate) != want {
+		t.Fatalf("expected alice's issue to complete %s after her holidays, got %s", want, formatDate(blocker.MeanDate))
+	}
+	if !dependent.ExpStartDate.Equal(blocker.MeanDate) {
+		t.Errorf("expected bob's dependent to start at %s, got %s", formatDate(blocker.MeanDate), formatDate(dependent.ExpStartDate))
+	}
+}
@@ -0,0 +1,87 @@
+package p2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsEscaper escapes TEXT values per RFC 5545
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r", "", "\n", `\n`)
+
+// WriteICS writes updates as an iCalendar feed with one all-day event per
+// scheduled issue, spanning Expected Start through Expected Completion and
+// noting the 98% Completion date in its description. Updates that clear dates
+// are skipped. now is used as each event's DTSTAMP. If privacy is non-nil,
+// titles of private repos other than the current one are omitted and their
+// references redacted.
+func WriteICS(w io.Writer, updates []DateUpdate, privacy *PrivacyFilter, now time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//octoberswimmer//p2-github-scheduler//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	for _, u := range updates {
+		if u.ClearDates || u.ExpectedStart.IsZero() || u.ExpectedCompletion.IsZero() {
+			continue
+		}
+		ref := fmt.Sprintf("%s/%s#%d", u.Owner, u.Repo, u.IssueNum)
+		// Hash the reference so UIDs don't reveal private repository names
+		uid := sha256.Sum256([]byte(ref))
+		title := u.Name
+		if privacy != nil {
+			ref = privacy.RedactDepID(ref)
+			title = privacy.RedactTitle(u.Owner, u.Repo, title)
+		}
+		summary := ref
+		if title != "" {
+			summary += ": " + title
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+hex.EncodeToString(uid[:8])+"@p2-github-scheduler",
+			"DTSTAMP:"+now.UTC().Format("20060102T150405Z"),
+			"DTSTART;VALUE=DATE:"+u.ExpectedStart.Format("20060102"),
+			// DTEND is exclusive, so the event covers the completion day
+			"DTEND;VALUE=DATE:"+truncateDay(u.ExpectedCompletion).AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+icsEscaper.Replace(summary),
+		)
+		if !u.Completion98.IsZero() {
+			lines = append(lines, "DESCRIPTION:"+icsEscaper.Replace("98% Completion: "+formatDate(u.Completion98)))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICSLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foldICSLine splits line into 75-octet segments joined by CRLF and a space,
+// without breaking UTF-8 sequences
+func foldICSLine(line string) string {
+	var sb strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = 74
+	}
+	sb.WriteString(line)
+	return sb.String()
+}
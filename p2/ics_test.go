@@ -0,0 +1,69 @@
+package p2
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICS(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	updates := []DateUpdate{
+		{
+			Owner: "owner", Repo: "repo", IssueNum: 1, Name: "Build, test; ship",
+			ExpectedStart:      start,
+			ExpectedCompletion: start.AddDate(0, 0, 3),
+			Completion98:       start.AddDate(0, 0, 8),
+		},
+		{Owner: "owner", Repo: "repo", IssueNum: 2, Name: "Closed", ClearDates: true, ClearReason: "closed"},
+		{
+			Owner: "owner", Repo: "secret", IssueNum: 3, Name: "Hidden",
+			ExpectedStart:      start,
+			ExpectedCompletion: start,
+		},
+	}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/secret/issues/3": {Owner: "owner", Repo: "secret", IssueNum: 3, IsPrivate: true},
+	}
+	privacy := NewPrivacyFilter("owner/repo", issues)
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, updates, privacy, start); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	if got := strings.Count(out, "BEGIN:VEVENT"); got != 2 {
+		t.Errorf("expected 2 events, got %d:\n%s", got, out)
+	}
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"SUMMARY:owner/repo#1: Build\\, test\\; ship\r\n",
+		"DTSTART;VALUE=DATE:20260302\r\n",
+		"DTEND;VALUE=DATE:20260306\r\n",
+		"DESCRIPTION:98% Completion: 2026-03-10\r\n",
+		"SUMMARY:[private]#3\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Closed") || strings.Contains(out, "secret") || strings.Contains(out, "Hidden") {
+		t.Errorf("expected cleared and private details to be omitted, got:\n%s", out)
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 60)
+	folded := foldICSLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("expected folded lines of at most 75 octets, got %d", len(part))
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Errorf("expected unfolding to restore the line, got %q", folded)
+	}
+}
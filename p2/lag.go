@@ -0,0 +1,73 @@
+package p2
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// lagLabelPattern matches labels like "lag:2" that set the lag, in working
+// days, between an issue and each of its blockers
+var lagLabelPattern = regexp.MustCompile(`^lag:(\d+)$`)
+
+// DependencyLags returns the lag in working days to apply after each blocker's
+// completion, keyed by the dependent's task ID. A "lag:N" label on the
+// dependent overrides defaultLag for all of its dependencies. Tasks with no
+// lag are omitted.
+func DependencyLags(issues map[string]IssueWithProject, defaultLag int) map[string]int {
+	lags := make(map[string]int)
+	for _, iwp := range issues {
+		if iwp.IsDraft {
+			continue
+		}
+		lag := defaultLag
+		for _, label := range iwp.Labels {
+			if m := lagLabelPattern.FindStringSubmatch(label); m != nil {
+				lag, _ = strconv.Atoi(m[1])
+			}
+		}
+		if lag > 0 {
+			lags[fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)] = lag
+		}
+	}
+	return lags
+}
+
+// ApplyDependencyLag delays dependents so that none starts until lag working
+// days after its blockers' Expected Completion. A delayed bar keeps its
+// duration: its Expected Completion and 98% Completion shift by the same
+// number of working days. As with ApplyEarliestStarts, delays propagate to
+// every dependent of a delayed bar, with or without a lag of its own, and to
+// the same user's bars queued behind it.
+func ApplyDependencyLag(ganttData *planner.GanttData, tasks []planner.Task, lags map[string]int) {
+	if len(lags) == 0 {
+		return
+	}
+	newBarShifter(ganttData).propagate(tasks, nil, lags)
+}
+
+// addWorkingDays returns t moved forward n weekdays
+func addWorkingDays(t time.Time, n int) time.Time {
+	for n > 0 {
+		t = t.AddDate(0, 0, 1)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+			n--
+		}
+	}
+	return t
+}
+
+// workingDaysBetween returns the number of weekdays after from up to and including to
+func workingDaysBetween(from, to time.Time) int {
+	n := 0
+	for d := from; d.Before(to); {
+		d = d.AddDate(0, 0, 1)
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			n++
+		}
+	}
+	return n
+}
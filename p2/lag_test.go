@@ -0,0 +1,98 @@
+package p2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestApplyDependencyLag_DependentStartsAfterLag(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2026, time.February, d, 0, 0, 0, 0, time.UTC) }
+
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, State: "open"},
+		"github.com/owner/repo/issues/2": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  2,
+			State:     "open",
+			Labels:    []string{"lag:2"},
+			BlockedBy: []IssueRef{{Owner: "owner", Repo: "repo", Number: 1}},
+		},
+	}
+	tasks, _, _ := IssuesToTasks(issues, nil)
+
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			// Blocker completes Friday Feb 6
+			{ID: "owner/repo#1", ExpStartDate: date(2), MeanDate: date(6), End98Date: date(9)},
+			// Dependent was scheduled to start right away on Monday Feb 9
+			{ID: "owner/repo#2", ExpStartDate: date(9), MeanDate: date(11), End98Date: date(13)},
+		},
+	}
+
+	ApplyDependencyLag(&ganttData, tasks, DependencyLags(issues, 0))
+
+	dependent := ganttData.Bars[1]
+	if !dependent.ExpStartDate.Equal(date(10)) {
+		t.Errorf("expected dependent to start Tuesday Feb 10, two working days after Feb 6, got %s", dependent.ExpStartDate.Format("2006-01-02"))
+	}
+	if !dependent.MeanDate.Equal(date(12)) || !dependent.End98Date.Equal(date(16)) {
+		t.Errorf("expected completion dates shifted one working day, got %s / %s",
+			dependent.MeanDate.Format("2006-01-02"), dependent.End98Date.Format("2006-01-02"))
+	}
+	if blocker := ganttData.Bars[0]; !blocker.ExpStartDate.Equal(date(2)) {
+		t.Error("expected blocker to be unchanged")
+	}
+}
+
+func TestApplyDependencyLag_DelayCarriesDownChainAndQueue(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2026, time.February, d, 0, 0, 0, 0, time.UTC) }
+
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", User: "alice"},
+		{ID: "owner/repo#2", User: "bob", DependsOn: []string{"owner/repo#1"}},
+		// No lag of its own, but waits for #2
+		{ID: "owner/repo#3", User: "carol", DependsOn: []string{"owner/repo#2"}},
+		// Queued behind #2 in bob's work
+		{ID: "owner/repo#4", User: "bob"},
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			{ID: "owner/repo#1", ExpStartDate: date(2), MeanDate: date(6), End98Date: date(9)},
+			{ID: "owner/repo#2", ExpStartDate: date(9), MeanDate: date(11), End98Date: date(13)},
+			{ID: "owner/repo#3", ExpStartDate: date(11), MeanDate: date(12), End98Date: date(13)},
+			{ID: "owner/repo#4", ExpStartDate: date(11), MeanDate: date(13), End98Date: date(16)},
+		},
+	}
+
+	ApplyDependencyLag(&ganttData, tasks, map[string]int{"owner/repo#2": 2})
+
+	// #2 moves from Feb 9 to Feb 10 and completes Feb 12
+	if lagged := ganttData.Bars[1]; !lagged.ExpStartDate.Equal(date(10)) || !lagged.MeanDate.Equal(date(12)) {
+		t.Fatalf("expected #2 to run Feb 10-12, got %s-%s", lagged.ExpStartDate.Format("2006-01-02"), lagged.MeanDate.Format("2006-01-02"))
+	}
+	if dependent := ganttData.Bars[2]; !dependent.ExpStartDate.Equal(date(12)) {
+		t.Errorf("expected zero-lag dependent #3 to start when #2 completes, got %s", dependent.ExpStartDate.Format("2006-01-02"))
+	}
+	if queued := ganttData.Bars[3]; !queued.ExpStartDate.Equal(date(12)) {
+		t.Errorf("expected bob's next issue #4 to start when #2 completes, got %s", queued.ExpStartDate.Format("2006-01-02"))
+	}
+}
+
+func TestDependencyLags_DefaultAndLabelOverride(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, Labels: []string{"lag:0"}},
+		"github.com/owner/repo/issues/3": {Owner: "owner", Repo: "repo", IssueNum: 3, Labels: []string{"lag:5"}},
+	}
+
+	lags := DependencyLags(issues, 1)
+	if lags["owner/repo#1"] != 1 || lags["owner/repo#3"] != 5 {
+		t.Errorf("unexpected lags: %v", lags)
+	}
+	if _, ok := lags["owner/repo#2"]; ok {
+		t.Errorf("expected lag:0 to override the default, got %v", lags)
+	}
+}
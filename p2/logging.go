@@ -0,0 +1,18 @@
+package p2
+
+import "github.com/sirupsen/logrus"
+
+// IssueFields returns log fields identifying an issue, so log lines can be
+// filtered by owner, repo, and issue number
+func IssueFields(owner, repo string, issueNum int) logrus.Fields {
+	return logrus.Fields{"owner": owner, "repo": repo, "issue": issueNum}
+}
+
+// LogFields returns IssueFields with the owner and repo redacted like RedactRepo.
+// A nil PrivacyFilter redacts nothing.
+func (pf *PrivacyFilter) LogFields(owner, repo string, issueNum int) logrus.Fields {
+	if pf != nil && pf.ShouldRedact(owner, repo) {
+		owner, repo = "[private]", "[private]"
+	}
+	return IssueFields(owner, repo, issueNum)
+}
@@ -0,0 +1,68 @@
+package p2
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// orderLess reports whether a comes before b in project order. Projects can
+// report equal positions, so ties are broken by task ID to schedule the same
+// way on every run.
+func orderLess(a, b IssueWithProject) bool {
+	if a.Order != b.Order {
+		return a.Order < b.Order
+	}
+	return taskID(a) < taskID(b)
+}
+
+// logDuplicateOrders logs, at debug level, each issue of sorted (ordered by
+// orderLess) that shares its project order with the one before it
+func logDuplicateOrders(sorted []IssueWithProject) {
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Order == sorted[i-1].Order {
+			logrus.Debugf("%s and %s have the same project order %d; ordering them by ID", taskID(sorted[i-1]), taskID(sorted[i]), sorted[i].Order)
+		}
+	}
+}
+
+// ApplyFieldOrder reorders issues by a numeric project field. values maps
+// project item IDs to the field's value. Issues with a value come first,
+// lowest value first; issues without one follow. Ties keep their existing
+// relative Order.
+func ApplyFieldOrder(issues map[string]IssueWithProject, values map[string]float64) {
+	refs := make([]string, 0, len(issues))
+	for ref := range issues {
+		refs = append(refs, ref)
+	}
+
+	value := func(ref string) (float64, bool) {
+		iwp := issues[ref]
+		if iwp.Project == nil {
+			return 0, false
+		}
+		v, ok := values[iwp.Project.ItemID]
+		return v, ok
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		vi, oki := value(refs[i])
+		vj, okj := value(refs[j])
+		if oki != okj {
+			return oki
+		}
+		if oki && vi != vj {
+			return vi < vj
+		}
+		if issues[refs[i]].Order != issues[refs[j]].Order {
+			return issues[refs[i]].Order < issues[refs[j]].Order
+		}
+		return refs[i] < refs[j]
+	})
+
+	for i, ref := range refs {
+		iwp := issues[ref]
+		iwp.Order = i
+		issues[ref] = iwp
+	}
+}
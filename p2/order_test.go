@@ -0,0 +1,52 @@
+package p2
+
+import (
+	"testing"
+
+	"github.com/octoberswimmer/p2/github"
+)
+
+func TestApplyFieldOrder_RepoIssuesSortByField(t *testing.T) {
+	item := func(id string) *github.ProjectItemInfo { return &github.ProjectItemInfo{ItemID: id} }
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, Order: 0, Project: item("item-1")},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, Order: 1, Project: item("item-2")},
+		"github.com/owner/repo/issues/3": {Owner: "owner", Repo: "repo", IssueNum: 3, Order: 2, Project: item("item-3")},
+		"github.com/owner/repo/issues/4": {Owner: "owner", Repo: "repo", IssueNum: 4, Order: 3, Project: item("item-4")},
+	}
+	values := map[string]float64{
+		"item-3": 1,
+		"item-1": 5,
+		"item-4": 2,
+	}
+
+	ApplyFieldOrder(issues, values)
+
+	want := map[int]int{3: 0, 4: 1, 1: 2, 2: 3}
+	for _, iwp := range issues {
+		if iwp.Order != want[iwp.IssueNum] {
+			t.Errorf("expected #%d at order %d, got %d", iwp.IssueNum, want[iwp.IssueNum], iwp.Order)
+		}
+	}
+}
+
+func TestIssuesToTasks_EqualOrderIsStable(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/web/issues/7": {Owner: "owner", Repo: "web", IssueNum: 7, State: "open", Assignee: "alice", Order: 1},
+		"github.com/owner/api/issues/9": {Owner: "owner", Repo: "api", IssueNum: 9, State: "open", Assignee: "alice", Order: 1},
+		"github.com/owner/api/issues/3": {Owner: "owner", Repo: "api", IssueNum: 3, State: "open", Assignee: "alice", Order: 0},
+	}
+
+	want := []string{"owner/api#3", "owner/api#9", "owner/web#7"}
+	for run := 0; run < 20; run++ {
+		tasks, _, _ := IssuesToTasks(issues, nil)
+		if len(tasks) != len(want) {
+			t.Fatalf("expected %d tasks, got %d", len(want), len(tasks))
+		}
+		for i, task := range tasks {
+			if task.ID != want[i] {
+				t.Fatalf("run %d: expected task order %v, got %s at %d", run, want, task.ID, i)
+			}
+		}
+	}
+}
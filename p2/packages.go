@@ -0,0 +1,113 @@
+package p2
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// PackageExplanation describes the attributes that determined a milestone
+// package's scheduling order
+type PackageExplanation struct {
+	// Rank is the package's position in the final order, starting at 1
+	Rank    int
+	ID      string
+	DueDate *time.Time
+	// Semver is the parsed version, or "" if the milestone isn't a version
+	Semver string
+	// FirstOrder is the project position of the package's first issue
+	FirstOrder int
+	// DecidedBy names the attribute that placed the package after the previous
+	// one: "due date", "semver", "project order", or "no milestone" for the
+	// NoMilestonePackage ("" for the first package)
+	DecidedBy string
+}
+
+// ExplainPackages returns the milestone packages of issues in the order
+// IssuesToTasksWithOptions schedules them with opts, with the attributes
+// behind that order. With opts.GroupUnmilestoned, the NoMilestonePackage comes
+// last if any issue lacks a milestone.
+func ExplainPackages(issues map[string]IssueWithProject, opts ConvertOptions) []PackageExplanation {
+	sorted := make([]IssueWithProject, 0, len(issues))
+	for _, iwp := range issues {
+		sorted = append(sorted, iwp)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return orderLess(sorted[i], sorted[j])
+	})
+
+	ordered := orderPackages(sorted)
+	explanations := make([]PackageExplanation, len(ordered))
+	for i, info := range ordered {
+		exp := PackageExplanation{
+			Rank:       i + 1,
+			ID:         info.id,
+			FirstOrder: info.firstOrder,
+		}
+		if info.hasDueDate {
+			due := info.dueDate
+			exp.DueDate = &due
+		}
+		if info.hasSemver {
+			exp.Semver = fmt.Sprintf("%d.%d.%d", info.semver.major, info.semver.minor, info.semver.patch)
+		}
+		if i > 0 {
+			exp.DecidedBy = packageDecider(ordered[i-1], info)
+		}
+		explanations[i] = exp
+	}
+
+	if opts.GroupUnmilestoned {
+		for i, iwp := range sorted {
+			if iwp.Milestone != "" {
+				continue
+			}
+			exp := PackageExplanation{
+				Rank:       len(explanations) + 1,
+				ID:         NoMilestonePackage,
+				FirstOrder: i,
+			}
+			if len(explanations) > 0 {
+				exp.DecidedBy = "no milestone"
+			}
+			explanations = append(explanations, exp)
+			break
+		}
+	}
+	return explanations
+}
+
+// packageDecider returns which orderPackages rule placed b after a
+func packageDecider(a, b *packageInfo) string {
+	if a.hasDueDate != b.hasDueDate || (a.hasDueDate && !a.dueDate.Equal(b.dueDate)) {
+		return "due date"
+	}
+	if a.hasSemver != b.hasSemver || (a.hasSemver && compareSemver(a.semver, b.semver) != 0) {
+		return "semver"
+	}
+	return "project order"
+}
+
+// WritePackageExplanations writes one line per package in rank order
+func WritePackageExplanations(w io.Writer, explanations []PackageExplanation) error {
+	for _, exp := range explanations {
+		due := "none"
+		if exp.DueDate != nil {
+			due = exp.DueDate.Format("2006-01-02")
+		}
+		semver := exp.Semver
+		if semver == "" {
+			semver = "none"
+		}
+		decidedBy := ""
+		if exp.DecidedBy != "" {
+			decidedBy = fmt.Sprintf(" (after previous by %s)", exp.DecidedBy)
+		}
+		if _, err := fmt.Fprintf(w, "%d. %s: due=%s semver=%s first_order=%d%s\n",
+			exp.Rank, exp.ID, due, semver, exp.FirstOrder, decidedBy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package p2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExplainPackages_FinalOrderWithDecidingAttributes(t *testing.T) {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, Milestone: "Backlog", Order: 0},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, Milestone: "v2.0.0", Order: 1},
+		"github.com/owner/repo/issues/3": {Owner: "owner", Repo: "repo", IssueNum: 3, Milestone: "v1.1.0", Order: 2},
+		"github.com/owner/repo/issues/4": {Owner: "owner", Repo: "repo", IssueNum: 4, Milestone: "Launch", MilestoneDueDate: &due, Order: 3},
+		"github.com/owner/repo/issues/5": {Owner: "owner", Repo: "repo", IssueNum: 5, Milestone: "Cleanup", Order: 4},
+	}
+
+	explanations := ExplainPackages(issues, ConvertOptions{})
+
+	want := []struct {
+		id        string
+		semver    string
+		hasDue    bool
+		decidedBy string
+	}{
+		{"Launch", "", true, ""},
+		{"v1.1.0", "1.1.0", false, "due date"},
+		{"v2.0.0", "2.0.0", false, "semver"},
+		{"Backlog", "", false, "semver"},
+		{"Cleanup", "", false, "project order"},
+	}
+	if len(explanations) != len(want) {
+		t.Fatalf("expected %d packages, got %d: %+v", len(want), len(explanations), explanations)
+	}
+	for i, w := range want {
+		exp := explanations[i]
+		if exp.Rank != i+1 || exp.ID != w.id || exp.Semver != w.semver || (exp.DueDate != nil) != w.hasDue || exp.DecidedBy != w.decidedBy {
+			t.Errorf("rank %d: expected %s semver=%q due=%v decided by %q, got %+v", i+1, w.id, w.semver, w.hasDue, w.decidedBy, exp)
+		}
+	}
+	if explanations[3].FirstOrder != 0 || explanations[4].FirstOrder != 4 {
+		t.Errorf("expected first orders 0 and 4 for Backlog and Cleanup, got %d and %d", explanations[3].FirstOrder, explanations[4].FirstOrder)
+	}
+}
+
+func TestExplainPackages_GroupUnmilestonedLast(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, Order: 0},
+		"github.com/owner/repo/issues/2": {Owner: "owner", Repo: "repo", IssueNum: 2, Milestone: "v1.0.0", Order: 1},
+	}
+
+	if explanations := ExplainPackages(issues, ConvertOptions{}); len(explanations) != 1 {
+		t.Errorf("expected only the milestone without grouping, got %+v", explanations)
+	}
+
+	explanations := ExplainPackages(issues, ConvertOptions{GroupUnmilestoned: true})
+	if len(explanations) != 2 {
+		t.Fatalf("expected the milestone and the no-milestone package, got %+v", explanations)
+	}
+	last := explanations[1]
+	if last.Rank != 2 || last.ID != NoMilestonePackage || last.FirstOrder != 0 || last.DecidedBy != "no milestone" {
+		t.Errorf("expected %s ranked last, got %+v", NoMilestonePackage, last)
+	}
+}
@@ -0,0 +1,234 @@
+package p2
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// TeamField is the optional project single-select field naming the team that
+// works on an issue
+const TeamField = "Team"
+
+// NoTeam is the partition of issues without a team
+const NoTeam = "(no team)"
+
+// TaskPartition is a set of tasks scheduled independently of other partitions
+type TaskPartition struct {
+	Team  string
+	Tasks []planner.Task
+}
+
+// TaskTeams returns the team of every issue, keyed by task ID. teams maps
+// issue refs (keys of issues) to team names; issues without one are in NoTeam.
+func TaskTeams(issues map[string]IssueWithProject, teams map[string]string) map[string]string {
+	teamOf := make(map[string]string, len(issues))
+	for ref, iwp := range issues {
+		team := teams[ref]
+		if team == "" {
+			team = NoTeam
+		}
+		teamOf[taskID(iwp)] = team
+	}
+	return teamOf
+}
+
+// PartitionTasks splits tasks by team, keeping their order within each team.
+// Dependencies on other teams' tasks are dropped, since each partition is
+// scheduled on its own; ApplyEarliestStarts with CrossPartitionStarts puts
+// them back afterwards. Partitions are sorted by team, with NoTeam last.
+func PartitionTasks(tasks []planner.Task, teamOf map[string]string) []TaskPartition {
+	byTeam := make(map[string]*TaskPartition)
+	var teams []string
+	for _, task := range tasks {
+		team := teamOf[task.ID]
+		if team == "" {
+			team = NoTeam
+		}
+		part, ok := byTeam[team]
+		if !ok {
+			part = &TaskPartition{Team: team}
+			byTeam[team] = part
+			teams = append(teams, team)
+		}
+		var deps []string
+		for _, dep := range task.DependsOn {
+			if depTeam, ok := teamOf[dep]; !ok || depTeam == team {
+				deps = append(deps, dep)
+			}
+		}
+		task.DependsOn = deps
+		part.Tasks = append(part.Tasks, task)
+	}
+	sortTeams(teams)
+
+	partitions := make([]TaskPartition, len(teams))
+	for i, team := range teams {
+		partitions[i] = *byTeam[team]
+	}
+	return partitions
+}
+
+// SharedAssignee is a user with open work in more than one partition
+type SharedAssignee struct {
+	User  string
+	Teams []string
+}
+
+// SharedAssignees returns the users with open work in more than one
+// partition, sorted by user. Each partition is scheduled with the full
+// capacity of every user, so these users are booked once per team.
+// Unassigned tasks are not reported.
+func SharedAssignees(partitions []TaskPartition) []SharedAssignee {
+	teamsOf := make(map[string][]string)
+	for _, part := range partitions {
+		seen := make(map[string]bool)
+		for _, task := range part.Tasks {
+			if task.Done || task.OnHold || task.User == "unassigned" || seen[task.User] {
+				continue
+			}
+			seen[task.User] = true
+			teamsOf[task.User] = append(teamsOf[task.User], part.Team)
+		}
+	}
+
+	var shared []SharedAssignee
+	for user, teams := range teamsOf {
+		if len(teams) > 1 {
+			shared = append(shared, SharedAssignee{User: user, Teams: teams})
+		}
+	}
+	sort.Slice(shared, func(i, j int) bool { return shared[i].User < shared[j].User })
+	return shared
+}
+
+// sortTeams sorts team names alphabetically with NoTeam last
+func sortTeams(teams []string) {
+	sort.Slice(teams, func(i, j int) bool {
+		if (teams[i] == NoTeam) != (teams[j] == NoTeam) {
+			return teams[j] == NoTeam
+		}
+		return teams[i] < teams[j]
+	})
+}
+
+// MergeGanttData combines separately computed schedules. A package bar that
+// appears in several spans from its earliest start to its latest completion.
+func MergeGanttData(parts []planner.GanttData) planner.GanttData {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	var merged planner.GanttData
+	packages := make(map[string]int)
+	for _, part := range parts {
+		for _, bar := range part.Bars {
+			if !bar.IsPackage {
+				merged.Bars = append(merged.Bars, bar)
+				continue
+			}
+			idx, ok := packages[bar.ID]
+			if !ok {
+				packages[bar.ID] = len(merged.Bars)
+				merged.Bars = append(merged.Bars, bar)
+				continue
+			}
+			pkg := &merged.Bars[idx]
+			if !bar.ExpStartDate.IsZero() && (pkg.ExpStartDate.IsZero() || bar.ExpStartDate.Before(pkg.ExpStartDate)) {
+				pkg.ExpStartDate = bar.ExpStartDate
+			}
+			if bar.MeanDate.After(pkg.MeanDate) {
+				pkg.MeanDate = bar.MeanDate
+			}
+			if bar.End98Date.After(pkg.End98Date) {
+				pkg.End98Date = bar.End98Date
+			}
+		}
+	}
+	return merged
+}
+
+// CrossPartitionStarts returns, for each task that depends on another team's
+// tasks, the latest Expected Completion among those blockers, keyed by task ID.
+// Dependencies within a cycle are skipped; no partition sees a cycle that
+// crosses teams, so check the unpartitioned tasks with the scheduler.
+func CrossPartitionStarts(ganttData planner.GanttData, tasks []planner.Task, teamOf map[string]string) map[string]time.Time {
+	cycles := dependencyCycles(tasks)
+	mean := make(map[string]time.Time, len(ganttData.Bars))
+	for _, bar := range ganttData.Bars {
+		if !bar.IsPackage {
+			mean[bar.ID] = bar.MeanDate
+		}
+	}
+	starts := make(map[string]time.Time)
+	for _, task := range tasks {
+		for _, dep := range task.DependsOn {
+			depTeam, ok := teamOf[dep]
+			if !ok || depTeam == teamOf[task.ID] || cycles.shared(task.ID, dep) {
+				continue
+			}
+			if m := mean[dep]; m.After(starts[task.ID]) {
+				starts[task.ID] = m
+			}
+		}
+	}
+	return starts
+}
+
+// TeamSummary is the scheduled work of one team
+type TeamSummary struct {
+	Team      string
+	Scheduled int
+	// Finish is the latest Expected Completion of the team's scheduled work
+	Finish time.Time
+}
+
+// TeamSummaries returns the scheduled work of each team in ganttData, sorted
+// as PartitionTasks sorts partitions
+func TeamSummaries(ganttData planner.GanttData, teamOf map[string]string) []TeamSummary {
+	byTeam := make(map[string]*TeamSummary)
+	var teams []string
+	for _, bar := range ganttData.Bars {
+		if bar.IsPackage || bar.Done || bar.OnHold || bar.ExpStartDate.IsZero() {
+			continue
+		}
+		team := teamOf[bar.ID]
+		if team == "" {
+			team = NoTeam
+		}
+		s, ok := byTeam[team]
+		if !ok {
+			s = &TeamSummary{Team: team}
+			byTeam[team] = s
+			teams = append(teams, team)
+		}
+		s.Scheduled++
+		if bar.MeanDate.After(s.Finish) {
+			s.Finish = bar.MeanDate
+		}
+	}
+	sortTeams(teams)
+
+	summaries := make([]TeamSummary, len(teams))
+	for i, team := range teams {
+		summaries[i] = *byTeam[team]
+	}
+	return summaries
+}
+
+// WriteTeamSummaries writes one line per team, continuing WriteSummary's
+// rollup, e.g. "  Team Backend: 12 issues, done 2026-03-04"
+func WriteTeamSummaries(w io.Writer, summaries []TeamSummary) error {
+	lines := make([]string, len(summaries))
+	for i, s := range summaries {
+		lines[i] = fmt.Sprintf("  Team %s: %d issues, done %s", s.Team, s.Scheduled, s.Finish.Format("2006-01-02"))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
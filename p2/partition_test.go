@@ -0,0 +1,169 @@
+package p2
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestPartitionTasks_DropsCrossTeamDependencies(t *testing.T) {
+	tasks := []planner.Task{
+		{ID: "owner/api#1", User: "alice"},
+		{ID: "owner/web#2", User: "bob", DependsOn: []string{"owner/api#1"}},
+		{ID: "owner/web#3", User: "bob", DependsOn: []string{"owner/web#2"}},
+		{ID: "owner/docs#4", User: "carol"},
+	}
+	teamOf := map[string]string{
+		"owner/api#1":  "Backend",
+		"owner/web#2":  "Frontend",
+		"owner/web#3":  "Frontend",
+		"owner/docs#4": NoTeam,
+	}
+
+	parts := PartitionTasks(tasks, teamOf)
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 partitions, got %d", len(parts))
+	}
+	if parts[0].Team != "Backend" || parts[1].Team != "Frontend" || parts[2].Team != NoTeam {
+		t.Errorf("expected Backend, Frontend, %s; got %s, %s, %s", NoTeam, parts[0].Team, parts[1].Team, parts[2].Team)
+	}
+
+	frontend := parts[1].Tasks
+	if len(frontend) != 2 {
+		t.Fatalf("expected 2 Frontend tasks, got %d", len(frontend))
+	}
+	if len(frontend[0].DependsOn) != 0 {
+		t.Errorf("expected cross-team dependency of owner/web#2 to be dropped, got %v", frontend[0].DependsOn)
+	}
+	if len(frontend[1].DependsOn) != 1 || frontend[1].DependsOn[0] != "owner/web#2" {
+		t.Errorf("expected owner/web#3 to keep its same-team dependency, got %v", frontend[1].DependsOn)
+	}
+	if len(tasks[1].DependsOn) != 1 {
+		t.Error("expected input tasks to be left unchanged")
+	}
+}
+
+func TestCrossPartitionStarts_WaitsForOtherTeam(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2026, time.February, d, 0, 0, 0, 0, time.UTC) }
+
+	tasks := []planner.Task{
+		{ID: "owner/api#1", User: "alice"},
+		{ID: "owner/web#2", User: "bob", DependsOn: []string{"owner/api#1"}},
+		{ID: "owner/web#3", User: "bob"},
+	}
+	teamOf := map[string]string{
+		"owner/api#1": "Backend",
+		"owner/web#2": "Frontend",
+		"owner/web#3": "Frontend",
+	}
+	// Scheduled independently, Frontend starts #2 right away
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			{ID: "owner/api#1", ExpStartDate: date(2), MeanDate: date(10), End98Date: date(12)},
+			{ID: "owner/web#2", ExpStartDate: date(2), MeanDate: date(4), End98Date: date(5)},
+			{ID: "owner/web#3", ExpStartDate: date(4), MeanDate: date(5), End98Date: date(6)},
+		},
+	}
+
+	starts := CrossPartitionStarts(ganttData, tasks, teamOf)
+	if len(starts) != 1 || !starts["owner/web#2"].Equal(date(10)) {
+		t.Fatalf("expected owner/web#2 to wait until Feb 10, got %v", starts)
+	}
+
+	ApplyEarliestStarts(&ganttData, tasks, starts)
+	if blocked := ganttData.Bars[1]; !blocked.ExpStartDate.Equal(date(10)) {
+		t.Errorf("expected owner/web#2 to start when owner/api#1 completes, got %s", blocked.ExpStartDate.Format("2006-01-02"))
+	}
+	if backend := ganttData.Bars[0]; !backend.ExpStartDate.Equal(date(2)) {
+		t.Error("expected Backend's schedule to be unchanged")
+	}
+}
+
+func TestCrossPartitionStarts_SkipsCrossTeamCycles(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2026, time.February, d, 0, 0, 0, 0, time.UTC) }
+
+	tasks := []planner.Task{
+		{ID: "owner/api#1", User: "alice", DependsOn: []string{"owner/web#2"}},
+		{ID: "owner/web#2", User: "bob", DependsOn: []string{"owner/api#1"}},
+		{ID: "owner/web#3", User: "carol", DependsOn: []string{"owner/api#1"}},
+	}
+	teamOf := map[string]string{
+		"owner/api#1": "Backend",
+		"owner/web#2": "Frontend",
+		"owner/web#3": "Frontend",
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			{ID: "owner/api#1", ExpStartDate: date(2), MeanDate: date(4), End98Date: date(5)},
+			{ID: "owner/web#2", ExpStartDate: date(2), MeanDate: date(4), End98Date: date(5)},
+			{ID: "owner/web#3", ExpStartDate: date(2), MeanDate: date(3), End98Date: date(4)},
+		},
+	}
+
+	starts := CrossPartitionStarts(ganttData, tasks, teamOf)
+	if len(starts) != 1 || !starts["owner/web#3"].Equal(date(4)) {
+		t.Fatalf("expected only owner/web#3 to wait for the other team, got %v", starts)
+	}
+
+	// A delay reaching the cycle moves each member once rather than chasing
+	// the other round the loop
+	starts["owner/api#1"] = date(9)
+	ApplyEarliestStarts(&ganttData, tasks, starts)
+	if api := ganttData.Bars[0]; !api.ExpStartDate.Equal(date(9)) {
+		t.Errorf("expected owner/api#1 to start Feb 9, got %s", api.ExpStartDate.Format("2006-01-02"))
+	}
+	if web := ganttData.Bars[1]; !web.ExpStartDate.Equal(date(2)) {
+		t.Errorf("expected owner/web#2 to keep its start, got %s", web.ExpStartDate.Format("2006-01-02"))
+	}
+}
+
+func TestSharedAssignees(t *testing.T) {
+	partitions := []TaskPartition{
+		{Team: "Backend", Tasks: []planner.Task{{ID: "owner/api#1", User: "alice"}, {ID: "owner/api#2", User: "bob"}, {ID: "owner/api#3", User: "unassigned"}}},
+		{Team: "Frontend", Tasks: []planner.Task{{ID: "owner/web#4", User: "alice"}, {ID: "owner/web#5", User: "bob", Done: true}, {ID: "owner/web#6", User: "unassigned"}}},
+		{Team: NoTeam, Tasks: []planner.Task{{ID: "owner/docs#7", User: "alice"}}},
+	}
+
+	shared := SharedAssignees(partitions)
+	if len(shared) != 1 || shared[0].User != "alice" {
+		t.Fatalf("expected only alice to be shared, got %+v", shared)
+	}
+	if got := strings.Join(shared[0].Teams, ", "); got != "Backend, Frontend, "+NoTeam {
+		t.Errorf("expected alice in Backend, Frontend and %s, got %s", NoTeam, got)
+	}
+}
+
+func TestTeamSummaries(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2026, time.February, d, 0, 0, 0, 0, time.UTC) }
+
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			{ID: "owner/docs#4", ExpStartDate: date(2), MeanDate: date(3)},
+			{ID: "owner/web#2", ExpStartDate: date(2), MeanDate: date(6)},
+			{ID: "owner/web#3", ExpStartDate: date(6), MeanDate: date(9)},
+			{ID: "owner/api#1", ExpStartDate: date(2), MeanDate: date(5)},
+			{ID: "owner/api#5", Done: true},
+			{ID: "owner/api", IsPackage: true, ExpStartDate: date(2), MeanDate: date(5)},
+		},
+	}
+	teamOf := map[string]string{
+		"owner/api#1": "Backend",
+		"owner/api#5": "Backend",
+		"owner/web#2": "Frontend",
+		"owner/web#3": "Frontend",
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTeamSummaries(&buf, TeamSummaries(ganttData, teamOf)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "  Team Backend: 1 issues, done 2026-02-05\n" +
+		"  Team Frontend: 2 issues, done 2026-02-09\n" +
+		"  Team (no team): 1 issues, done 2026-02-03\n"
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
@@ -114,6 +114,14 @@ func TestRedactTitle_current_repo(t *testing.T) {
 	}
 }
 
+func TestRedactTitle_public_repo(t *testing.T) {
+	pf := newTestFilter()
+	got := pf.RedactTitle("myorg", "public", "Public issue")
+	if got != "Public issue" {
+		t.Errorf("expected title, got %q", got)
+	}
+}
+
 func TestRedactDepID_private_repo(t *testing.T) {
 	pf := newTestFilter()
 	got := pf.RedactDepID("myorg/secret#2")
@@ -145,3 +153,19 @@ func TestRedactDepID_invalid_format(t *testing.T) {
 		t.Errorf("expected unchanged string, got %q", got)
 	}
 }
+
+func TestLogFields_other_private_repo(t *testing.T) {
+	pf := newTestFilter()
+	fields := pf.LogFields("myorg", "secret", 5)
+	if fields["owner"] != "[private]" || fields["repo"] != "[private]" || fields["issue"] != 5 {
+		t.Errorf("expected redacted owner and repo with issue 5, got %v", fields)
+	}
+	fields = pf.LogFields("myorg", "public", 3)
+	if fields["owner"] != "myorg" || fields["repo"] != "public" {
+		t.Errorf("expected public repo fields unchanged, got %v", fields)
+	}
+	var none *PrivacyFilter
+	if fields := none.LogFields("myorg", "secret", 5); fields["repo"] != "secret" {
+		t.Errorf("expected nil filter to redact nothing, got %v", fields)
+	}
+}
@@ -0,0 +1,57 @@
+package p2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Default estimates applied to open issues with neither estimate set
+const (
+	DefaultEstimateLow  = 1
+	DefaultEstimateHigh = 4
+)
+
+// DefaultEstimateWarningThreshold is the fraction of open issues with the
+// default estimate above which DefaultEstimateWarning reports a problem
+const DefaultEstimateWarningThreshold = 0.8
+
+// DefaultEstimateWarning returns a run-level data-quality warning when more
+// than threshold (0-1) of the open, non-draft issues have the default
+// estimate, either because none was set or because it was set to exactly the
+// default. Such a board usually hasn't been estimated, so its schedule is
+// not meaningful. Returns "" when there is nothing to report.
+func DefaultEstimateWarning(issues map[string]IssueWithProject, threshold float64) string {
+	return DefaultEstimateWarningFor(issues, threshold, DefaultEstimateLow, DefaultEstimateHigh)
+}
+
+// DefaultEstimateWarningFor is DefaultEstimateWarning for a configured
+// default estimate of low-high
+func DefaultEstimateWarningFor(issues map[string]IssueWithProject, threshold, low, high float64) string {
+	var total, defaulted int
+	for _, iwp := range issues {
+		if iwp.IsDraft || strings.EqualFold(iwp.State, "closed") {
+			continue
+		}
+		total++
+		if hasDefaultEstimate(iwp, low, high) {
+			defaulted++
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	if float64(defaulted)/float64(total) <= threshold {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d open issues (%.0f%%) have the default %g-%g estimate; the schedule is unlikely to be meaningful until issues are estimated",
+		defaulted, total, 100*float64(defaulted)/float64(total), low, high)
+}
+
+// hasDefaultEstimate reports whether iwp is unestimated or estimated at exactly low-high
+func hasDefaultEstimate(iwp IssueWithProject, low, high float64) bool {
+	if iwp.LowEstimate == nil && iwp.HighEstimate == nil {
+		return true
+	}
+	return iwp.LowEstimate != nil && iwp.HighEstimate != nil &&
+		*iwp.LowEstimate == low && *iwp.HighEstimate == high
+}
@@ -0,0 +1,52 @@
+package p2
+
+import (
+	"fmt"
+	"testing"
+)
+
+func qualityTestIssues(n int, estimate func(i int) (*float64, *float64)) map[string]IssueWithProject {
+	issues := make(map[string]IssueWithProject, n)
+	for i := 1; i <= n; i++ {
+		low, high := estimate(i)
+		issues[fmt.Sprintf("github.com/owner/repo/issues/%d", i)] = IssueWithProject{
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     i,
+			State:        "open",
+			LowEstimate:  low,
+			HighEstimate: high,
+		}
+	}
+	return issues
+}
+
+func TestDefaultEstimateWarning_MostlyDefaultBoard(t *testing.T) {
+	issues := qualityTestIssues(10, func(i int) (*float64, *float64) {
+		switch {
+		case i == 1:
+			return ptr(2), ptr(8)
+		case i%2 == 0:
+			return ptr(1), ptr(4)
+		default:
+			return nil, nil
+		}
+	})
+
+	if msg := DefaultEstimateWarning(issues, DefaultEstimateWarningThreshold); msg == "" {
+		t.Error("expected a warning for a board of mostly default estimates")
+	}
+}
+
+func TestDefaultEstimateWarning_WellEstimatedBoard(t *testing.T) {
+	issues := qualityTestIssues(10, func(i int) (*float64, *float64) {
+		if i == 1 {
+			return nil, nil
+		}
+		return ptr(float64(i)), ptr(float64(2 * i))
+	})
+
+	if msg := DefaultEstimateWarning(issues, DefaultEstimateWarningThreshold); msg != "" {
+		t.Errorf("expected no warning for a well-estimated board, got %q", msg)
+	}
+}
@@ -0,0 +1,91 @@
+package p2
+
+import (
+	"sort"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// ApplyRamps stretches the bars of users with ramp windows so that work done
+// during a window progresses at the window's percentage of a full day. A
+// stretched bar's 98% Completion moves by the same number of working days as
+// its Expected Completion, and the user's later bars that were queued behind it
+// start no earlier than its new Expected Completion. Bars that overlap an
+// earlier bar of the same user are stretched but not pushed back. As with
+// ApplyEarliestStarts, dependents of a stretched bar then start no earlier
+// than its new Expected Completion. Holidays are applied as zero-percent
+// windows.
+func ApplyRamps(ganttData *planner.GanttData, tasks []planner.Task, a *Availability) {
+	if a == nil || (len(a.Ramps) == 0 && len(a.Holidays) == 0) {
+		return
+	}
+	shifter := newBarShifter(ganttData)
+
+	userOf := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		userOf[t.ID] = t.User
+	}
+
+	byUser := make(map[string][]int)
+	for i, bar := range ganttData.Bars {
+		if bar.IsPackage || bar.Done || bar.OnHold || bar.ExpStartDate.IsZero() {
+			continue
+		}
+		user := userOf[bar.ID]
+		if len(a.windowsFor(user)) > 0 {
+			byUser[user] = append(byUser[user], i)
+		}
+	}
+
+	for user, indexes := range byUser {
+		windows := a.windowsFor(user)
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return ganttData.Bars[indexes[i]].ExpStartDate.Before(ganttData.Bars[indexes[j]].ExpStartDate)
+		})
+
+		var prevOrigEnd, prevNewEnd time.Time
+		for _, idx := range indexes {
+			bar := &ganttData.Bars[idx]
+			origEnd := bar.MeanDate
+			days := workingDaysBetween(bar.ExpStartDate, bar.MeanDate)
+
+			// Start after the previous bar if this one was queued behind it
+			if !prevNewEnd.IsZero() && !bar.ExpStartDate.Before(prevOrigEnd) && bar.ExpStartDate.Before(prevNewEnd) {
+				bar.ExpStartDate = prevNewEnd
+			}
+
+			mean := bar.ExpStartDate
+			for effort := 0.0; effort < float64(days); {
+				mean = addWorkingDays(mean, 1)
+				effort += rampFactor(windows, mean)
+			}
+
+			if mean.After(origEnd) {
+				shift := workingDaysBetween(origEnd, mean)
+				bar.End98Date = addWorkingDays(bar.End98Date, shift)
+				bar.MeanDate = mean
+			}
+			prevOrigEnd, prevNewEnd = origEnd, bar.MeanDate
+		}
+	}
+
+	shifter.propagate(tasks, nil, nil)
+}
+
+// rampFactor returns the fraction of a full day available on day. Overlapping
+// windows use the first match; days outside every window are full days.
+func rampFactor(windows []RampWindow, day time.Time) float64 {
+	d := truncateDay(day)
+	for _, w := range windows {
+		if d.Before(truncateDay(w.From)) || d.After(truncateDay(w.Until)) {
+			continue
+		}
+		if w.Percent <= 0 {
+			// Treat a zero window as unavailable without stalling forever
+			return 0.01
+		}
+		return w.Percent / 100
+	}
+	return 1
+}
@@ -0,0 +1,107 @@
+package p2
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestApplyRamps_EarlyTasksTakeLonger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.yaml")
+	content := `ramps:
+  carol:
+    - from: 2025-03-03
+      until: 2025-03-14
+      percent: 50
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	avail, err := LoadAvailability(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Two equivalent four-day tasks, one during the ramp and one after it
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2025, 3, 24, 0, 0, 0, 0, time.UTC)
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", User: "carol"},
+		{ID: "owner/repo#2", User: "carol"},
+	}
+	ganttData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: monday, MeanDate: monday.AddDate(0, 0, 4), End98Date: monday.AddDate(0, 0, 7)},
+		{ID: "owner/repo#2", ExpStartDate: later, MeanDate: later.AddDate(0, 0, 4), End98Date: later.AddDate(0, 0, 7)},
+	}}
+
+	ApplyRamps(&ganttData, tasks, avail)
+
+	early, late := ganttData.Bars[0], ganttData.Bars[1]
+	earlyDays := workingDaysBetween(early.ExpStartDate, early.MeanDate)
+	lateDays := workingDaysBetween(late.ExpStartDate, late.MeanDate)
+	if earlyDays <= lateDays {
+		t.Errorf("expected ramping task to take longer, got %d working days vs %d", earlyDays, lateDays)
+	}
+	if earlyDays != 8 {
+		t.Errorf("expected four days of work at 50%% to take 8 working days, got %d", earlyDays)
+	}
+	if lateDays != 4 {
+		t.Errorf("expected task after ramp-up to keep 4 working days, got %d", lateDays)
+	}
+	if want := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC); !early.End98Date.Equal(want) {
+		t.Errorf("expected 98%% Completion to shift to %s, got %s", formatDate(want), formatDate(early.End98Date))
+	}
+}
+
+func TestApplyRamps_QueuedTaskStartsAfterStretchedTask(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	friday := monday.AddDate(0, 0, 4)
+	avail := &Availability{Ramps: map[string][]RampWindow{
+		"carol": {{From: monday, Until: monday.AddDate(0, 0, 11), Percent: 50}},
+	}}
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", User: "carol"},
+		{ID: "owner/repo#2", User: "carol"},
+	}
+	ganttData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: monday, MeanDate: friday, End98Date: friday},
+		{ID: "owner/repo#2", ExpStartDate: friday, MeanDate: friday.AddDate(0, 0, 3), End98Date: friday.AddDate(0, 0, 3)},
+	}}
+
+	ApplyRamps(&ganttData, tasks, avail)
+
+	if !ganttData.Bars[1].ExpStartDate.Equal(ganttData.Bars[0].MeanDate) {
+		t.Errorf("expected queued task to start at %s, got %s",
+			formatDate(ganttData.Bars[0].MeanDate), formatDate(ganttData.Bars[1].ExpStartDate))
+	}
+}
+
+func TestApplyRamps_DependentStartsAfterStretchedBlocker(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	friday := monday.AddDate(0, 0, 4)
+	avail := &Availability{Ramps: map[string][]RampWindow{
+		"carol": {{From: monday, Until: monday.AddDate(0, 0, 11), Percent: 50}},
+	}}
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", User: "carol"},
+		// Another user's issue blocked by carol's
+		{ID: "owner/repo#2", User: "dave", DependsOn: []string{"owner/repo#1"}},
+	}
+	ganttData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: monday, MeanDate: friday, End98Date: friday},
+		{ID: "owner/repo#2", ExpStartDate: friday, MeanDate: friday.AddDate(0, 0, 3), End98Date: friday.AddDate(0, 0, 3)},
+	}}
+
+	ApplyRamps(&ganttData, tasks, avail)
+
+	blocker, dependent := ganttData.Bars[0], ganttData.Bars[1]
+	if !blocker.MeanDate.After(friday) {
+		t.Fatalf("expected carol's ramping task to be stretched, got %s", formatDate(blocker.MeanDate))
+	}
+	if !dependent.ExpStartDate.Equal(blocker.MeanDate) {
+		t.Errorf("expected dependent to start at %s, got %s", formatDate(blocker.MeanDate), formatDate(dependent.ExpStartDate))
+	}
+}
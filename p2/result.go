@@ -0,0 +1,178 @@
+package p2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// Result summarizes a scheduling run for custom output templates
+type Result struct {
+	// Updates are the planned date field changes
+	Updates []DateUpdate
+	// SchedulingIssues are all reported scheduling problems and warnings
+	SchedulingIssues []SchedulingIssue
+	// AtRisk are the at_risk warnings among SchedulingIssues
+	AtRisk []SchedulingIssue
+	// ProjectedEnd is the latest 98% Completion of any open, scheduled task
+	ProjectedEnd time.Time
+	// Timings records how long each phase of the run took
+	Timings Timings
+}
+
+// Timings records the duration of each phase of a run
+type Timings struct {
+	Fetch    time.Duration
+	Schedule time.Duration
+}
+
+// NewResult builds a Result from a run's outputs. If privacy is non-nil,
+// titles and dependency details from other private repos are redacted.
+func NewResult(ganttData planner.GanttData, updates []DateUpdate, schedIssues []SchedulingIssue, timings Timings, privacy *PrivacyFilter) Result {
+	if privacy != nil {
+		redactedUpdates := make([]DateUpdate, len(updates))
+		for i, u := range updates {
+			u.Name = privacy.RedactTitle(u.Owner, u.Repo, u.Name)
+			redactedUpdates[i] = u
+		}
+		updates = redactedUpdates
+
+		redactedIssues := make([]SchedulingIssue, len(schedIssues))
+		for i, si := range schedIssues {
+			redactedIssues[i] = privacy.RedactSchedulingIssue(si)
+		}
+		schedIssues = redactedIssues
+	}
+
+	result := Result{
+		Updates:          updates,
+		SchedulingIssues: schedIssues,
+		ProjectedEnd:     ProjectedEnd(ganttData),
+		Timings:          timings,
+	}
+	for _, si := range schedIssues {
+		if si.Reason == "at_risk" {
+			result.AtRisk = append(result.AtRisk, si)
+		}
+	}
+	return result
+}
+
+// ProjectedEnd returns the latest 98% Completion among open, scheduled tasks
+func ProjectedEnd(ganttData planner.GanttData) time.Time {
+	var end time.Time
+	for _, bar := range ganttData.Bars {
+		if bar.IsPackage || bar.Done || bar.OnHold {
+			continue
+		}
+		if bar.End98Date.After(end) {
+			end = bar.End98Date
+		}
+	}
+	return end
+}
+
+// RenderTemplate executes the Go text/template in path against result and
+// writes the output to w. Templates can use the "date" function to format a
+// time.Time as YYYY-MM-DD.
+func RenderTemplate(w io.Writer, path string, result Result) error {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(template.FuncMap{
+		"date": formatDate,
+	}).ParseFiles(path)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := tmpl.Execute(w, result); err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", path, err)
+	}
+	return nil
+}
+
+// jsonUpdate is the JSON form of a DateUpdate. Dates are null when cleared.
+type jsonUpdate struct {
+	Owner              string  `json:"owner"`
+	Repo               string  `json:"repo"`
+	Number             int     `json:"number"`
+	Title              string  `json:"title"`
+	ExpectedStart      *string `json:"expected_start"`
+	ExpectedCompletion *string `json:"expected_completion"`
+	Completion98       *string `json:"completion_98"`
+	ClearReason        string  `json:"clear_reason,omitempty"`
+}
+
+// jsonIssue is the JSON form of a SchedulingIssue
+type jsonIssue struct {
+	Owner   string   `json:"owner"`
+	Repo    string   `json:"repo"`
+	Number  int      `json:"number"`
+	Reason  string   `json:"reason"`
+	Details []string `json:"details"`
+}
+
+// WriteResultJSON writes result's updates, scheduling issues, at-risk
+// warnings, and projected end to w as JSON
+func WriteResultJSON(w io.Writer, result Result) error {
+	report := struct {
+		Updates          []jsonUpdate `json:"updates"`
+		SchedulingIssues []jsonIssue  `json:"scheduling_issues"`
+		AtRisk           []jsonIssue  `json:"at_risk"`
+		ProjectedEnd     *string      `json:"projected_end"`
+	}{
+		Updates:          []jsonUpdate{},
+		SchedulingIssues: jsonIssues(result.SchedulingIssues),
+		AtRisk:           jsonIssues(result.AtRisk),
+		ProjectedEnd:     jsonDate(result.ProjectedEnd),
+	}
+	for _, u := range result.Updates {
+		ju := jsonUpdate{
+			Owner:       u.Owner,
+			Repo:        u.Repo,
+			Number:      u.IssueNum,
+			Title:       u.Name,
+			ClearReason: u.ClearReason,
+		}
+		if !u.ClearDates {
+			ju.ExpectedStart = jsonDate(u.ExpectedStart)
+			ju.ExpectedCompletion = jsonDate(u.ExpectedCompletion)
+			ju.Completion98 = jsonDate(u.Completion98)
+		}
+		report.Updates = append(report.Updates, ju)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// jsonIssues converts scheduling issues to their JSON form
+func jsonIssues(schedIssues []SchedulingIssue) []jsonIssue {
+	converted := []jsonIssue{}
+	for _, si := range schedIssues {
+		details := si.Details
+		if details == nil {
+			details = []string{}
+		}
+		converted = append(converted, jsonIssue{
+			Owner:   si.Owner,
+			Repo:    si.Repo,
+			Number:  si.IssueNum,
+			Reason:  si.Reason,
+			Details: details,
+		})
+	}
+	return converted
+}
+
+// jsonDate formats t as YYYY-MM-DD, or nil if t is zero
+func jsonDate(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	s := t.Format("2006-01-02")
+	return &s
+}
@@ -0,0 +1,93 @@
+package p2
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestRenderTemplate_ProjectedEndAndUpdateCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.tmpl")
+	tmpl := `Projected end: {{date .ProjectedEnd}}, {{len .Updates}} updates, {{len .AtRisk}} at risk`
+	if err := os.WriteFile(path, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			{ID: "v1.0.0", IsPackage: true, End98Date: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "owner/repo#1", ExpStartDate: testStart, MeanDate: testMean, End98Date: testEnd98},
+			{ID: "owner/repo#2", ExpStartDate: otherStart, MeanDate: otherMean, End98Date: otherEnd98},
+			{ID: "owner/repo#3", Done: true, End98Date: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	updates := []DateUpdate{{IssueNum: 1}, {IssueNum: 2}}
+	schedIssues := []SchedulingIssue{
+		{IssueNum: 1, Reason: "at_risk"},
+		{IssueNum: 2, Reason: "missing_estimate"},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderTemplate(&buf, path, NewResult(ganttData, updates, schedIssues, Timings{}, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "Projected end: " + otherEnd98.Format("2006-01-02") + ", 2 updates, 1 at risk"
+	if buf.String() != want {
+		t.Errorf("unexpected output:\n got: %q\nwant: %q", buf.String(), want)
+	}
+}
+
+func TestWriteResultJSON(t *testing.T) {
+	mean := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	updates := []DateUpdate{
+		{Owner: "owner", Repo: "repo", IssueNum: 1, Name: "Scheduled",
+			ExpectedStart: mean.AddDate(0, 0, -4), ExpectedCompletion: mean, Completion98: mean.AddDate(0, 0, 3)},
+		{Owner: "owner", Repo: "repo", IssueNum: 2, Name: "Closed", ClearDates: true, ClearReason: "closed"},
+	}
+	schedIssues := []SchedulingIssue{
+		{IssueRef: "github.com/owner/repo/issues/1", IssueNum: 1, Owner: "owner", Repo: "repo", Reason: "at_risk", Details: []string{"Due: 2025-03-05"}},
+		{IssueRef: "github.com/owner/repo/issues/3", IssueNum: 3, Owner: "owner", Repo: "repo", Reason: "cycle"},
+	}
+	ganttData := planner.GanttData{Bars: []planner.GanttBar{{ID: "owner/repo#1", End98Date: mean.AddDate(0, 0, 3)}}}
+
+	var buf bytes.Buffer
+	if err := WriteResultJSON(&buf, NewResult(ganttData, updates, schedIssues, Timings{}, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report struct {
+		Updates []struct {
+			Number             int     `json:"number"`
+			ExpectedCompletion *string `json:"expected_completion"`
+			ClearReason        string  `json:"clear_reason"`
+		} `json:"updates"`
+		SchedulingIssues []struct {
+			Number  int      `json:"number"`
+			Reason  string   `json:"reason"`
+			Details []string `json:"details"`
+		} `json:"scheduling_issues"`
+		AtRisk       []struct{} `json:"at_risk"`
+		ProjectedEnd string     `json:"projected_end"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(report.Updates) != 2 || report.Updates[0].ExpectedCompletion == nil || *report.Updates[0].ExpectedCompletion != "2025-03-07" {
+		t.Errorf("expected scheduled update with completion 2025-03-07, got %s", buf.String())
+	}
+	if report.Updates[1].ExpectedCompletion != nil || report.Updates[1].ClearReason != "closed" {
+		t.Errorf("expected cleared update with null dates and reason, got %s", buf.String())
+	}
+	if len(report.SchedulingIssues) != 2 || report.SchedulingIssues[1].Reason != "cycle" || report.SchedulingIssues[1].Details == nil {
+		t.Errorf("unexpected scheduling issues: %s", buf.String())
+	}
+	if len(report.AtRisk) != 1 || report.ProjectedEnd != "2025-03-10" {
+		t.Errorf("expected 1 at-risk issue and projected end 2025-03-10, got %s", buf.String())
+	}
+}
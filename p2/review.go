@@ -0,0 +1,68 @@
+package p2
+
+import (
+	"fmt"
+	"time"
+)
+
+// FilterUpdates returns the updates for which accept returns true,
+// preserving their order. accept is called once per update, in order.
+func FilterUpdates(updates []DateUpdate, accept func(DateUpdate) bool) []DateUpdate {
+	var accepted []DateUpdate
+	for _, u := range updates {
+		if accept(u) {
+			accepted = append(accepted, u)
+		}
+	}
+	return accepted
+}
+
+// DateDiff describes how one date field changes in an update
+type DateDiff struct {
+	Field string
+	Old   *time.Time // nil when the field is being set for the first time
+	New   *time.Time // nil when the field is being cleared
+}
+
+// String formats d as "Field: old -> new", "+ Field: new" for an addition, or
+// "- Field: old" for a removal
+func (d DateDiff) String() string {
+	switch {
+	case d.Old == nil:
+		return fmt.Sprintf("+ %s: %s", d.Field, d.New.Format("2006-01-02"))
+	case d.New == nil:
+		return fmt.Sprintf("- %s: %s", d.Field, d.Old.Format("2006-01-02"))
+	default:
+		return fmt.Sprintf("%s: %s -> %s", d.Field, d.Old.Format("2006-01-02"), d.New.Format("2006-01-02"))
+	}
+}
+
+// UpdateDiffs returns the date fields that u changes on iwp, in field order,
+// labelled with their names in fieldNames. Unchanged fields are omitted.
+func UpdateDiffs(iwp IssueWithProject, u DateUpdate, fieldNames FieldNames) []DateDiff {
+	fields := []struct {
+		name    string
+		current *time.Time
+		next    time.Time
+	}{
+		{fieldNames.ExpectedStart, iwp.ExpectedStart, u.ExpectedStart},
+		{fieldNames.ExpectedCompletion, iwp.ExpectedCompletion, u.ExpectedCompletion},
+		{fieldNames.Completion98, iwp.Completion98, u.Completion98},
+	}
+	var diffs []DateDiff
+	for _, f := range fields {
+		next := f.next
+		if u.ClearDates {
+			next = time.Time{}
+		}
+		if sameDate(f.current, next) {
+			continue
+		}
+		d := DateDiff{Field: f.name, Old: f.current}
+		if !next.IsZero() {
+			d.New = &next
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
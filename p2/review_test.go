@@ -0,0 +1,67 @@
+package p2
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterUpdates_AppliesOnlyAcceptedUpdates(t *testing.T) {
+	updates := []DateUpdate{
+		{Owner: "owner", Repo: "repo", IssueNum: 1},
+		{Owner: "owner", Repo: "repo", IssueNum: 2},
+		{Owner: "owner", Repo: "repo", IssueNum: 3},
+		{Owner: "owner", Repo: "repo", IssueNum: 4},
+	}
+	decisions := map[int]bool{1: true, 2: false, 3: true, 4: false}
+
+	var asked []int
+	accepted := FilterUpdates(updates, func(u DateUpdate) bool {
+		asked = append(asked, u.IssueNum)
+		return decisions[u.IssueNum]
+	})
+
+	if len(asked) != 4 || asked[0] != 1 || asked[3] != 4 {
+		t.Errorf("expected a decision for each update in order, got %v", asked)
+	}
+	if len(accepted) != 2 || accepted[0].IssueNum != 1 || accepted[1].IssueNum != 3 {
+		t.Errorf("expected only #1 and #3 to be applied, got %+v", accepted)
+	}
+}
+
+func TestFilterUpdates_SkipAll(t *testing.T) {
+	updates := []DateUpdate{{IssueNum: 1}, {IssueNum: 2}}
+	if accepted := FilterUpdates(updates, func(DateUpdate) bool { return false }); len(accepted) != 0 {
+		t.Errorf("expected no updates, got %d", len(accepted))
+	}
+}
+
+func TestUpdateDiffs_ShowsOnlyChangedFields(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC) }
+	start, completion := day(1), day(5)
+	iwp := IssueWithProject{ExpectedStart: &start, ExpectedCompletion: &completion}
+
+	u := DateUpdate{ExpectedStart: day(1), ExpectedCompletion: day(8), Completion98: day(12)}
+	var lines []string
+	for _, d := range UpdateDiffs(iwp, u, DefaultFieldNames) {
+		lines = append(lines, d.String())
+	}
+	want := []string{"Expected Completion: 2026-02-05 -> 2026-02-08", "+ 98% Completion: 2026-02-12"}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected %q, got %q", want, lines)
+	}
+
+	cleared := UpdateDiffs(iwp, DateUpdate{ClearDates: true, ClearReason: "closed"}, DefaultFieldNames)
+	if len(cleared) != 2 || cleared[0].String() != "- Expected Start: 2026-02-01" || cleared[1].String() != "- Expected Completion: 2026-02-05" {
+		t.Errorf("expected both dates to be removed, got %v", cleared)
+	}
+}
+
+func TestUpdateDiffs_UsesConfiguredFieldNames(t *testing.T) {
+	names := DefaultFieldNames
+	names.ExpectedCompletion = "Target Date"
+	diffs := UpdateDiffs(IssueWithProject{}, DateUpdate{ExpectedCompletion: time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC)}, names)
+	if len(diffs) != 1 || diffs[0].String() != "+ Target Date: 2026-02-08" {
+		t.Errorf("expected the diff to name the configured field, got %v", diffs)
+	}
+}
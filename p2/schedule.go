@@ -2,6 +2,7 @@ package p2
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,8 +18,36 @@ func sameDate(existing *time.Time, new time.Time) bool {
 	return existing.Format("2006-01-02") == new.Format("2006-01-02")
 }
 
+// SortUpdates sorts updates by owner, repo, and issue number so they are
+// applied and logged in a deterministic order
+func SortUpdates(updates []DateUpdate) {
+	sort.SliceStable(updates, func(i, j int) bool {
+		a, b := updates[i], updates[j]
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		return a.IssueNum < b.IssueNum
+	})
+}
+
 // DetectAtRiskIssues identifies issues where expected completion is after the due date
 func DetectAtRiskIssues(updates []DateUpdate, issues map[string]IssueWithProject) []SchedulingIssue {
+	return DetectAtRiskIssuesWithOptions(updates, issues, AtRiskOptions{})
+}
+
+// AtRiskOptions controls DetectAtRiskIssuesWithOptions
+type AtRiskOptions struct {
+	// WorkingDays moves due dates and completions that fall on a weekend to
+	// the following Monday before comparing, so a Monday completion against a
+	// Saturday due date is on time
+	WorkingDays bool
+}
+
+// DetectAtRiskIssuesWithOptions identifies issues where expected completion is after the due date
+func DetectAtRiskIssuesWithOptions(updates []DateUpdate, issues map[string]IssueWithProject, opts AtRiskOptions) []SchedulingIssue {
 	var atRiskIssues []SchedulingIssue
 
 	for _, update := range updates {
@@ -40,7 +69,11 @@ func DetectAtRiskIssues(updates []DateUpdate, issues map[string]IssueWithProject
 		}
 
 		// Check if expected completion is after due date
-		if !update.ExpectedCompletion.IsZero() && update.ExpectedCompletion.After(*iwp.DueDate) {
+		dueDate, completion := *iwp.DueDate, update.ExpectedCompletion
+		if opts.WorkingDays {
+			dueDate, completion = nextWorkingDay(dueDate), nextWorkingDay(completion)
+		}
+		if !update.ExpectedCompletion.IsZero() && completion.After(dueDate) {
 			details := []string{
 				fmt.Sprintf("Due Date: %s", iwp.DueDate.Format("2006-01-02")),
 				fmt.Sprintf("Expected Completion: %s", update.ExpectedCompletion.Format("2006-01-02")),
@@ -59,7 +92,90 @@ func DetectAtRiskIssues(updates []DateUpdate, issues map[string]IssueWithProject
 	return atRiskIssues
 }
 
-// ExtractCycleIssues checks scheduler results for dependency cycles and adds them to scheduling issues
+// DetectPackageOvercommit identifies issues whose expected completion is after
+// their milestone's due date, meaning the milestone has more work than fits
+func DetectPackageOvercommit(updates []DateUpdate, issues map[string]IssueWithProject) []SchedulingIssue {
+	var overcommitted []SchedulingIssue
+
+	for _, update := range updates {
+		if update.ClearDates || update.ExpectedCompletion.IsZero() {
+			continue
+		}
+
+		ref := fmt.Sprintf("github.com/%s/%s/issues/%d", update.Owner, update.Repo, update.IssueNum)
+		iwp, ok := issues[ref]
+		if !ok || iwp.Milestone == "" || iwp.MilestoneDueDate == nil {
+			continue
+		}
+
+		if update.ExpectedCompletion.After(*iwp.MilestoneDueDate) {
+			overcommitted = append(overcommitted, SchedulingIssue{
+				IssueRef: ref,
+				IssueNum: iwp.IssueNum,
+				Owner:    iwp.Owner,
+				Repo:     iwp.Repo,
+				Reason:   "package_overcommit",
+				Details: []string{
+					fmt.Sprintf("Milestone: %s (due %s)", iwp.Milestone, iwp.MilestoneDueDate.Format("2006-01-02")),
+					fmt.Sprintf("Expected Completion: %s", update.ExpectedCompletion.Format("2006-01-02")),
+				},
+			})
+		}
+	}
+
+	return overcommitted
+}
+
+// ReconcileSignals consolidates overlapping warnings so each issue is notified
+// once. When an issue is both at risk of missing its own due date and in an
+// overcommitted milestone, the at_risk signal is kept (it is more specific)
+// with the milestone detail merged in, and the package_overcommit signal is dropped.
+func ReconcileSignals(schedIssues []SchedulingIssue) []SchedulingIssue {
+	overcommit := make(map[string]SchedulingIssue)
+	atRisk := make(map[string]bool)
+	for _, si := range schedIssues {
+		switch si.Reason {
+		case "package_overcommit":
+			overcommit[si.IssueRef] = si
+		case "at_risk":
+			atRisk[si.IssueRef] = true
+		}
+	}
+
+	var reconciled []SchedulingIssue
+	for _, si := range schedIssues {
+		switch {
+		case si.Reason == "package_overcommit" && atRisk[si.IssueRef]:
+			continue
+		case si.Reason == "at_risk":
+			if oc, ok := overcommit[si.IssueRef]; ok {
+				details := append([]string(nil), si.Details...)
+				for _, d := range oc.Details {
+					if strings.HasPrefix(d, "Milestone: ") {
+						details = append(details, d)
+					}
+				}
+				si.Details = details
+			}
+		}
+		reconciled = append(reconciled, si)
+	}
+	return reconciled
+}
+
+// nextWorkingDay returns t, or the following Monday if t falls on a weekend
+func nextWorkingDay(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, 2)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// ExtractCycleIssues checks scheduler results for dependency cycles and adds them to scheduling issues.
+// Every task named in a reported cycle path gets a notice, even if its own entry carries no Cycle.
 func ExtractCycleIssues(entries planner.ScheduledEntries, issues map[string]IssueWithProject, existing []SchedulingIssue) []SchedulingIssue {
 	// Build a set of issues that already have scheduling issues (avoid duplicates)
 	hasIssue := make(map[string]bool)
@@ -67,41 +183,144 @@ func ExtractCycleIssues(entries planner.ScheduledEntries, issues map[string]Issu
 		hasIssue[si.IssueRef] = true
 	}
 
+	taskRefs := make(map[string]string, len(issues))
+	for ref, iwp := range issues {
+		taskRefs[taskID(iwp)] = ref
+	}
+
 	for _, entry := range entries.Entries {
 		if entry.IsPackage || len(entry.Cycle) == 0 {
 			continue
 		}
 
-		// Find the issue for this entry
-		for ref, iwp := range issues {
-			taskID := fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
-			if taskID == entry.ID && !hasIssue[ref] {
-				existing = append(existing, SchedulingIssue{
-					IssueRef: ref,
-					IssueNum: iwp.IssueNum,
-					Owner:    iwp.Owner,
-					Repo:     iwp.Repo,
-					Reason:   "cycle",
-					Details:  entry.Cycle,
-				})
-				hasIssue[ref] = true
-				break
+		// Report cycles through split tasks against their issues
+		cycle := unsplitCycle(entry.Cycle)
+		entryID := entry.ID
+		if id, ok := splitTaskID(entryID); ok {
+			entryID = id
+		}
+		members := append([]string{entryID}, cycle...)
+		for _, id := range members {
+			ref, ok := taskRefs[id]
+			if !ok || hasIssue[ref] {
+				continue
 			}
+			iwp := issues[ref]
+			existing = append(existing, SchedulingIssue{
+				IssueRef: ref,
+				IssueNum: iwp.IssueNum,
+				Owner:    iwp.Owner,
+				Repo:     iwp.Repo,
+				Reason:   "cycle",
+				Details:  cycle,
+			})
+			hasIssue[ref] = true
 		}
 	}
 
 	return existing
 }
 
+// unsplitCycle maps the split part IDs in a cycle path back to their task IDs,
+// collapsing consecutive parts of the same task into one step
+func unsplitCycle(cycle []string) []string {
+	path := make([]string, 0, len(cycle))
+	for _, id := range cycle {
+		if orig, ok := splitTaskID(id); ok {
+			id = orig
+		}
+		if len(path) > 0 && path[len(path)-1] == id {
+			continue
+		}
+		path = append(path, id)
+	}
+	return path
+}
+
+// ClearAllUpdates returns updates that clear the scheduling fields of every
+// issue that has any set, regardless of the schedule. On-hold issues keep their
+// estimates as they would in a normal run; all other issues are cleared with
+// reason "archived", which also clears their estimates.
+func ClearAllUpdates(issues map[string]IssueWithProject) []DateUpdate {
+	return ClearAllUpdatesWithOptions(issues, ConvertOptions{})
+}
+
+// ClearAllUpdatesWithOptions is ClearAllUpdates with the Scheduling Status
+// behaviors and on-hold statuses of opts. Held issues are cleared with their
+// status (lowercased, e.g. "parked") as the ClearReason, and frozen issues
+// are left alone. Other fields of opts are ignored.
+func ClearAllUpdatesWithOptions(issues map[string]IssueWithProject, opts ConvertOptions) []DateUpdate {
+	var updates []DateUpdate
+	for _, iwp := range issues {
+		if iwp.Project == nil || opts.IsFrozen(iwp.SchedulingStatus) {
+			continue
+		}
+		hasEstimates := iwp.LowEstimate != nil || iwp.HighEstimate != nil
+		reason := "archived"
+		if opts.IsHeld(iwp.SchedulingStatus) && !strings.EqualFold(iwp.State, "closed") {
+			reason = strings.ToLower(iwp.SchedulingStatus)
+			hasEstimates = false
+		}
+		if !iwp.HasSchedulingDates && !hasEstimates {
+			continue
+		}
+		updates = append(updates, DateUpdate{
+			Owner:       iwp.Owner,
+			Repo:        iwp.Repo,
+			RepoKey:     fmt.Sprintf("%s/%s", iwp.Owner, iwp.Repo),
+			IssueNum:    iwp.IssueNum,
+			Name:        iwp.Title,
+			Project:     iwp.Project,
+			ClearDates:  true,
+			ClearReason: reason,
+		})
+	}
+	SortUpdates(updates)
+	return updates
+}
+
+// ClearDateUpdates returns updates that clear the calculated dates of every
+// issue that has any set, with reason "uninstalled", leaving estimates in place
+func ClearDateUpdates(issues map[string]IssueWithProject) []DateUpdate {
+	var updates []DateUpdate
+	for _, iwp := range issues {
+		if iwp.Project == nil || !iwp.HasSchedulingDates {
+			continue
+		}
+		updates = append(updates, DateUpdate{
+			Owner:       iwp.Owner,
+			Repo:        iwp.Repo,
+			RepoKey:     fmt.Sprintf("%s/%s", iwp.Owner, iwp.Repo),
+			IssueNum:    iwp.IssueNum,
+			Name:        iwp.Title,
+			Project:     iwp.Project,
+			ClearDates:  true,
+			ClearReason: "uninstalled",
+		})
+	}
+	SortUpdates(updates)
+	return updates
+}
+
 // PrepareUpdates determines date updates to apply based on scheduling results
 func PrepareUpdates(ganttData planner.GanttData, issues map[string]IssueWithProject, unschedulable map[string]bool) []DateUpdate {
+	return PrepareUpdatesWithOptions(ganttData, issues, unschedulable, ConvertOptions{})
+}
+
+// PrepareUpdatesWithOptions is PrepareUpdates with the Scheduling Status
+// behaviors of opts: issues whose status is held have their dates cleared like
+// on-hold issues, with the status (lowercased, e.g. "blocked") as the
+// ClearReason, and issues whose status is frozen are never updated. With
+// opts.KeepEstimatesOnClose, closed issues are only updated when they have
+// dates to clear. Other fields of opts are ignored.
+func PrepareUpdatesWithOptions(ganttData planner.GanttData, issues map[string]IssueWithProject, unschedulable map[string]bool, opts ConvertOptions) []DateUpdate {
 	var updates []DateUpdate
 
 	// Track which issues we've processed for clearing
 	// Use full task ID to avoid collisions between repos with same issue numbers
 	processed := make(map[string]bool)
 
-	// First pass: check all issues directly for on-hold or closed status
+	// First pass: check all issues directly for frozen, on-hold or closed status
 	// This doesn't depend on the scheduler - just GitHub data
 	for _, iwp := range issues {
 		if iwp.Project == nil {
@@ -110,8 +329,14 @@ func PrepareUpdates(ganttData planner.GanttData, issues map[string]IssueWithProj
 
 		taskID := fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
 
+		// Frozen issues keep whatever dates and estimates they have
+		if opts.IsFrozen(iwp.SchedulingStatus) {
+			processed[taskID] = true
+			continue
+		}
+
 		// Check for on-hold via Scheduling Status field
-		isOnHold := iwp.SchedulingStatus == "On Hold"
+		isOnHold := opts.statusBehavior(iwp.SchedulingStatus) == StatusHold
 		isClosed := strings.EqualFold(iwp.State, "closed")
 
 		if isOnHold || isClosed {
@@ -119,14 +344,16 @@ func PrepareUpdates(ganttData planner.GanttData, issues map[string]IssueWithProj
 			processed[taskID] = true
 
 			// On-hold: only clear if dates are set (estimates remain)
-			// Closed: clear if dates OR estimates are set
+			// Closed: clear if dates OR estimates are set, unless estimates are kept
 			if isOnHold && !iwp.HasSchedulingDates {
 				continue
 			}
-			if isClosed && !iwp.HasSchedulingDates && iwp.LowEstimate == nil && iwp.HighEstimate == nil {
+			hasEstimates := iwp.LowEstimate != nil || iwp.HighEstimate != nil
+			if isClosed && !iwp.HasSchedulingDates && (!hasEstimates || opts.KeepEstimatesOnClose) {
 				continue
 			}
-			reason := "on hold"
+			// Held issues report their own status, e.g. "on hold" or "parked"
+			reason := strings.ToLower(iwp.SchedulingStatus)
 			if isClosed {
 				reason = "closed"
 			}
@@ -159,7 +386,7 @@ func PrepareUpdates(ganttData planner.GanttData, issues map[string]IssueWithProj
 
 		taskID := fmt.Sprintf("%s/%s#%d", iwp.Owner, iwp.Repo, iwp.IssueNum)
 
-		// Skip if already processed (on-hold or closed)
+		// Skip if already processed (frozen, on-hold or closed)
 		if processed[taskID] {
 			continue
 		}
@@ -194,11 +421,11 @@ func PrepareUpdates(ganttData planner.GanttData, issues map[string]IssueWithProj
 		}
 
 		if iwp.Project == nil {
-			logrus.Debugf("Issue %s is not in a project", bar.ID)
+			logrus.WithFields(opts.Privacy.LogFields(iwp.Owner, iwp.Repo, iwp.IssueNum)).Debug("Issue is not in a project")
 			continue
 		}
 
-		// Skip if already processed (on-hold, closed, or unschedulable)
+		// Skip if already processed (frozen, on-hold, closed, or unschedulable)
 		if processed[bar.ID] {
 			continue
 		}
@@ -213,7 +440,7 @@ func PrepareUpdates(ganttData planner.GanttData, issues map[string]IssueWithProj
 		if sameDate(iwp.ExpectedStart, bar.ExpStartDate) &&
 			sameDate(iwp.ExpectedCompletion, bar.MeanDate) &&
 			sameDate(iwp.Completion98, bar.End98Date) {
-			logrus.Debugf("Dates unchanged for %s, skipping", bar.ID)
+			logrus.WithFields(opts.Privacy.LogFields(iwp.Owner, iwp.Repo, iwp.IssueNum)).Debug("Dates unchanged, skipping")
 			continue
 		}
 
@@ -1,11 +1,16 @@
 package p2
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/octoberswimmer/p2/github"
 	"github.com/octoberswimmer/p2/planner"
+	"github.com/sirupsen/logrus"
 )
 
 var (
@@ -748,6 +753,48 @@ func TestExtractCycleIssues_NoDuplicates(t *testing.T) {
 	}
 }
 
+func TestExtractCycleIssues_IncludesDraftMembers(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			Title:    "Task in cycle",
+		},
+		"draft:PVTI_abc123": {
+			Title:         "Draft in cycle",
+			IsDraft:       true,
+			ProjectItemID: "PVTI_abc123",
+		},
+	}
+
+	// Only the regular issue's entry carries the cycle path
+	entries := planner.ScheduledEntries{
+		Entries: []planner.ScheduledEntry{
+			{ID: "owner/repo#1", Name: "Task in cycle", Cycle: []string{"owner/repo#1", "draft:PVTI_abc123", "owner/repo#1"}},
+			{ID: "draft:PVTI_abc123", Name: "Draft in cycle"},
+		},
+	}
+
+	schedIssues := ExtractCycleIssues(entries, issues, nil)
+
+	if len(schedIssues) != 2 {
+		t.Fatalf("expected 2 scheduling issues, got %d: %+v", len(schedIssues), schedIssues)
+	}
+	found := false
+	for _, si := range schedIssues {
+		if si.IssueRef == "draft:PVTI_abc123" {
+			found = true
+			if si.Reason != "cycle" || len(si.Details) != 3 {
+				t.Errorf("expected draft cycle notice with path, got %+v", si)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected draft issue in the cycle to get a scheduling notice")
+	}
+}
+
 func TestPrepareUpdates_UnschedulableIssuesClearDates(t *testing.T) {
 	projectInfo := &github.ProjectItemInfo{
 		ProjectID: "proj-1",
@@ -1147,3 +1194,413 @@ func TestDetectAtRiskIssues_expected_completion_equal_to_due_date_is_not_at_risk
 		t.Fatalf("expected 0 at-risk issues when completion equals due date, got %d", len(atRiskIssues))
 	}
 }
+
+func TestReconcileSignals_AtRiskInOvercommittedMilestone(t *testing.T) {
+	due := time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC)
+	milestoneDue := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:            "owner",
+			Repo:             "repo",
+			IssueNum:         1,
+			State:            "open",
+			DueDate:          &due,
+			Milestone:        "v1.0.0",
+			MilestoneDueDate: &milestoneDue,
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:            "owner",
+			Repo:             "repo",
+			IssueNum:         2,
+			State:            "open",
+			Milestone:        "v1.0.0",
+			MilestoneDueDate: &milestoneDue,
+		},
+	}
+	updates := []DateUpdate{
+		{Owner: "owner", Repo: "repo", IssueNum: 1, ExpectedCompletion: time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC)},
+		{Owner: "owner", Repo: "repo", IssueNum: 2, ExpectedCompletion: time.Date(2026, 2, 13, 0, 0, 0, 0, time.UTC)},
+	}
+
+	signals := append(DetectAtRiskIssues(updates, issues), DetectPackageOvercommit(updates, issues)...)
+	if len(signals) != 3 {
+		t.Fatalf("expected 3 raw signals, got %d: %+v", len(signals), signals)
+	}
+
+	reconciled := ReconcileSignals(signals)
+	if len(reconciled) != 2 {
+		t.Fatalf("expected 2 signals after reconciliation, got %d: %+v", len(reconciled), reconciled)
+	}
+
+	perIssue := make(map[int][]SchedulingIssue)
+	for _, si := range reconciled {
+		perIssue[si.IssueNum] = append(perIssue[si.IssueNum], si)
+	}
+	if len(perIssue[1]) != 1 || perIssue[1][0].Reason != "at_risk" {
+		t.Fatalf("expected a single at_risk signal for #1, got %+v", perIssue[1])
+	}
+	found := false
+	for _, d := range perIssue[1][0].Details {
+		if d == "Milestone: v1.0.0 (due 2026-02-09)" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected consolidated signal to mention the milestone, got %v", perIssue[1][0].Details)
+	}
+	if len(perIssue[2]) != 1 || perIssue[2][0].Reason != "package_overcommit" {
+		t.Errorf("expected package_overcommit for #2, got %+v", perIssue[2])
+	}
+}
+
+func TestSortUpdates_PrepareUpdatesStableRegardlessOfMapOrder(t *testing.T) {
+	projectInfo := &github.ProjectItemInfo{ProjectID: "proj-1", ItemID: "item-1"}
+	issues := make(map[string]IssueWithProject)
+	var bars []planner.GanttBar
+	for _, repo := range []string{"zeta", "alpha"} {
+		for _, num := range []int{10, 2, 1} {
+			issues[fmt.Sprintf("github.com/owner/%s/issues/%d", repo, num)] = IssueWithProject{
+				Owner:              "owner",
+				Repo:               repo,
+				IssueNum:           num,
+				State:              "closed",
+				Project:            projectInfo,
+				HasSchedulingDates: true,
+			}
+		}
+	}
+	issues["github.com/other/repo/issues/5"] = IssueWithProject{
+		Owner:    "other",
+		Repo:     "repo",
+		IssueNum: 5,
+		State:    "open",
+		Project:  projectInfo,
+	}
+	bars = append(bars, planner.GanttBar{ID: "other/repo#5", ExpStartDate: testStart, MeanDate: testMean, End98Date: testEnd98})
+	ganttData := planner.GanttData{Bars: bars}
+
+	want := []string{
+		"other/repo#5",
+		"owner/alpha#1", "owner/alpha#2", "owner/alpha#10",
+		"owner/zeta#1", "owner/zeta#2", "owner/zeta#10",
+	}
+	for run := 0; run < 20; run++ {
+		updates := PrepareUpdates(ganttData, issues, nil)
+		SortUpdates(updates)
+		if len(updates) != len(want) {
+			t.Fatalf("expected %d updates, got %d", len(want), len(updates))
+		}
+		for i, u := range updates {
+			if got := fmt.Sprintf("%s/%s#%d", u.Owner, u.Repo, u.IssueNum); got != want[i] {
+				t.Fatalf("run %d: expected update %d to be %s, got %s", run, i, want[i], got)
+			}
+		}
+	}
+}
+
+func TestDetectAtRiskIssuesWithOptions_WeekendDueDate(t *testing.T) {
+	saturday := time.Date(2026, 2, 7, 0, 0, 0, 0, time.UTC)
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 1,
+			State:    "open",
+			DueDate:  &saturday,
+		},
+	}
+	monday := []DateUpdate{{Owner: "owner", Repo: "repo", IssueNum: 1, ExpectedCompletion: time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)}}
+	tuesday := []DateUpdate{{Owner: "owner", Repo: "repo", IssueNum: 1, ExpectedCompletion: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)}}
+
+	if atRisk := DetectAtRiskIssuesWithOptions(monday, issues, AtRiskOptions{WorkingDays: true}); len(atRisk) != 0 {
+		t.Errorf("expected Monday completion against Saturday due date to be on time, got %+v", atRisk)
+	}
+	if atRisk := DetectAtRiskIssues(monday, issues); len(atRisk) != 1 {
+		t.Errorf("expected calendar comparison to flag Monday completion, got %d", len(atRisk))
+	}
+	if atRisk := DetectAtRiskIssuesWithOptions(tuesday, issues, AtRiskOptions{WorkingDays: true}); len(atRisk) != 1 {
+		t.Errorf("expected Tuesday completion to be at risk, got %d", len(atRisk))
+	}
+}
+
+func TestClearAllUpdates_ClearsEveryIssueWithSchedulingData(t *testing.T) {
+	projectInfo := &github.ProjectItemInfo{ProjectID: "proj-1", ItemID: "item-1"}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner: "owner", Repo: "repo", IssueNum: 1, State: "open",
+			Project: projectInfo, HasSchedulingDates: true,
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner: "owner", Repo: "repo", IssueNum: 2, State: "open",
+			Project: projectInfo, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+		"github.com/owner/repo/issues/3": {
+			Owner: "owner", Repo: "repo", IssueNum: 3, State: "closed",
+			Project: projectInfo, HasSchedulingDates: true,
+		},
+		"github.com/owner/repo/issues/4": {
+			Owner: "owner", Repo: "repo", IssueNum: 4, State: "open", SchedulingStatus: "On Hold",
+			Project: projectInfo, HasSchedulingDates: true, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+		// On hold with only estimates: estimates are kept, so nothing to clear
+		"github.com/owner/repo/issues/5": {
+			Owner: "owner", Repo: "repo", IssueNum: 5, State: "open", SchedulingStatus: "On Hold",
+			Project: projectInfo, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+		// No scheduling data
+		"github.com/owner/repo/issues/6": {
+			Owner: "owner", Repo: "repo", IssueNum: 6, State: "open", Project: projectInfo,
+		},
+	}
+
+	updates := ClearAllUpdates(issues)
+
+	want := map[int]string{1: "archived", 2: "archived", 3: "archived", 4: "on hold"}
+	if len(updates) != len(want) {
+		t.Fatalf("expected %d updates, got %d: %+v", len(want), len(updates), updates)
+	}
+	for _, u := range updates {
+		if !u.ClearDates {
+			t.Errorf("expected #%d to clear dates", u.IssueNum)
+		}
+		if u.ClearReason != want[u.IssueNum] {
+			t.Errorf("expected #%d reason %q, got %q", u.IssueNum, want[u.IssueNum], u.ClearReason)
+		}
+	}
+}
+
+func TestClearDateUpdates_OnlyIssuesWithDates(t *testing.T) {
+	projectInfo := &github.ProjectItemInfo{ProjectID: "proj-1", ItemID: "item-1"}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner: "owner", Repo: "repo", IssueNum: 1, State: "closed",
+			Project: projectInfo, HasSchedulingDates: true, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner: "owner", Repo: "repo", IssueNum: 2, State: "open",
+			Project: projectInfo, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+	}
+
+	updates := ClearDateUpdates(issues)
+	if len(updates) != 1 || updates[0].IssueNum != 1 || !updates[0].ClearDates {
+		t.Fatalf("expected only #1 to have its dates cleared, got %+v", updates)
+	}
+	if updates[0].ClearReason == "closed" || updates[0].ClearReason == "archived" {
+		t.Errorf("expected a reason that keeps estimates, got %q", updates[0].ClearReason)
+	}
+}
+
+func TestPrepareUpdatesWithOptions_HeldStatusClearsWithStatusReason(t *testing.T) {
+	projectInfo := &github.ProjectItemInfo{ProjectID: "proj-1", ItemID: "item-1"}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner: "owner", Repo: "repo", IssueNum: 1, State: "open", SchedulingStatus: "Blocked",
+			Project: projectInfo, HasSchedulingDates: true, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner: "owner", Repo: "repo", IssueNum: 2, State: "open", SchedulingStatus: "On Hold",
+			Project: projectInfo, HasSchedulingDates: true,
+		},
+	}
+	opts := ConvertOptions{StatusBehaviors: map[string]StatusBehavior{"Blocked": StatusHold}}
+
+	updates := PrepareUpdatesWithOptions(planner.GanttData{}, issues, nil, opts)
+
+	reasons := make(map[int]string)
+	for _, u := range updates {
+		if !u.ClearDates {
+			t.Errorf("expected #%d to clear dates", u.IssueNum)
+		}
+		reasons[u.IssueNum] = u.ClearReason
+	}
+	if reasons[1] != "blocked" || reasons[2] != "on hold" {
+		t.Errorf("expected reasons blocked and on hold, got %v", reasons)
+	}
+
+	// Without the mapping, Blocked is scheduled normally
+	for _, u := range PrepareUpdates(planner.GanttData{}, issues, nil) {
+		if u.IssueNum == 1 {
+			t.Errorf("expected Blocked not to be cleared by default, got %+v", u)
+		}
+	}
+}
+
+func TestPrepareUpdatesWithOptions_CustomOnHoldStatus(t *testing.T) {
+	projectInfo := &github.ProjectItemInfo{ProjectID: "proj-1", ItemID: "item-1"}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner: "owner", Repo: "repo", IssueNum: 1, Title: "Parked", State: "open", SchedulingStatus: "Parked",
+			Project: projectInfo, HasSchedulingDates: true, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner: "owner", Repo: "repo", IssueNum: 2, Title: "Old status", State: "open", SchedulingStatus: "On Hold",
+			Project: projectInfo, HasSchedulingDates: true, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+	}
+	opts := ConvertOptions{OnHoldStatuses: []string{"Parked"}}
+
+	updates := PrepareUpdatesWithOptions(planner.GanttData{}, issues, nil, opts)
+	if len(updates) != 1 || updates[0].IssueNum != 1 {
+		t.Fatalf("expected only the parked issue to be cleared, got %+v", updates)
+	}
+	if !updates[0].ClearDates || updates[0].ClearReason != "parked" {
+		t.Errorf("expected dates cleared with reason parked, got %+v", updates[0])
+	}
+
+	tasks, _, _ := IssuesToTasksWithOptions(issues, nil, opts)
+	for _, task := range tasks {
+		if onHold := task.ID == "owner/repo#1"; task.OnHold != onHold {
+			t.Errorf("expected %s OnHold=%v, got %v", task.ID, onHold, task.OnHold)
+		}
+	}
+
+	cleared := ClearAllUpdatesWithOptions(issues, opts)
+	for _, u := range cleared {
+		if u.IssueNum == 1 && u.ClearReason != "parked" {
+			t.Errorf("expected the parked issue to keep its estimates when clearing all, got reason %q", u.ClearReason)
+		}
+	}
+}
+
+func TestPrepareUpdatesWithOptions_FrozenStatusIsNeverWritten(t *testing.T) {
+	projectInfo := &github.ProjectItemInfo{ProjectID: "proj-1", ItemID: "item-1"}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner: "owner", Repo: "repo", IssueNum: 1, Title: "Frozen", State: "open", SchedulingStatus: "Frozen",
+			Project: projectInfo, HasSchedulingDates: true, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner: "owner", Repo: "repo", IssueNum: 2, Title: "Active", State: "open",
+			Project: projectInfo, LowEstimate: ptr(2), HighEstimate: ptr(4),
+		},
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			{ID: "owner/repo#1", Name: "Frozen", ExpStartDate: testStart, MeanDate: testMean, End98Date: testEnd98},
+			{ID: "owner/repo#2", Name: "Active", ExpStartDate: otherStart, MeanDate: otherMean, End98Date: otherEnd98},
+		},
+	}
+	opts := ConvertOptions{StatusBehaviors: map[string]StatusBehavior{"Frozen": StatusFreeze}}
+
+	updates := PrepareUpdatesWithOptions(ganttData, issues, nil, opts)
+	if len(updates) != 1 || updates[0].IssueNum != 2 {
+		t.Fatalf("expected only the active issue to be updated, got %+v", updates)
+	}
+
+	// Frozen issues are still scheduled so their dependents account for them
+	tasks, _, _ := IssuesToTasksWithOptions(issues, nil, opts)
+	for _, task := range tasks {
+		if task.ID == "owner/repo#1" && task.OnHold {
+			t.Error("expected the frozen issue to be scheduled, not held")
+		}
+	}
+
+	for _, u := range ClearAllUpdatesWithOptions(issues, opts) {
+		if u.IssueNum == 1 {
+			t.Errorf("expected the frozen issue not to be cleared when clearing all, got %+v", u)
+		}
+	}
+}
+
+func TestPrepareUpdatesWithOptions_KeepEstimatesOnCloseSkipsEstimateOnlyIssues(t *testing.T) {
+	projectInfo := &github.ProjectItemInfo{
+		ProjectID: "proj-1",
+		ItemID:    "item-1",
+		FieldIDs: map[string]string{
+			"Expected Start": "field-1",
+			"Low Estimate":   "field-4",
+			"High Estimate":  "field-5",
+		},
+	}
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			Title:        "Closed With Estimates",
+			State:        "closed",
+			Project:      projectInfo,
+			LowEstimate:  ptr(2),
+			HighEstimate: ptr(8),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:              "owner",
+			Repo:               "repo",
+			IssueNum:           2,
+			Title:              "Closed With Dates",
+			State:              "closed",
+			Project:            projectInfo,
+			LowEstimate:        ptr(2),
+			HighEstimate:       ptr(8),
+			HasSchedulingDates: true,
+		},
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{
+			{ID: "owner/repo#1", Name: "Closed With Estimates", Done: true},
+			{ID: "owner/repo#2", Name: "Closed With Dates", Done: true},
+		},
+	}
+
+	updates := PrepareUpdatesWithOptions(ganttData, issues, nil, ConvertOptions{KeepEstimatesOnClose: true})
+	if len(updates) != 1 || updates[0].IssueNum != 2 {
+		t.Fatalf("expected only the closed issue with dates to be updated, got %+v", updates)
+	}
+	if !updates[0].ClearDates || updates[0].ClearReason != "closed" {
+		t.Errorf("expected closed issue dates to be cleared, got %+v", updates[0])
+	}
+}
+
+func TestPrepareUpdatesWithOptions_RedactsPrivateReposInLogs(t *testing.T) {
+	var buf bytes.Buffer
+	level := logrus.GetLevel()
+	logrus.SetOutput(&buf)
+	logrus.SetLevel(logrus.DebugLevel)
+	defer func() {
+		logrus.SetOutput(os.Stderr)
+		logrus.SetLevel(level)
+	}()
+
+	issues := map[string]IssueWithProject{
+		"github.com/myorg/secret/issues/3": {
+			Owner:     "myorg",
+			Repo:      "secret",
+			IssueNum:  3,
+			State:     "open",
+			IsPrivate: true,
+		},
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{{ID: "myorg/secret#3", ExpStartDate: testStart, MeanDate: testMean, End98Date: testEnd98}},
+	}
+	PrepareUpdatesWithOptions(ganttData, issues, nil, ConvertOptions{Privacy: NewPrivacyFilter("myorg/public", issues)})
+
+	if out := buf.String(); !strings.Contains(out, "Issue is not in a project") || strings.Contains(out, "secret") {
+		t.Errorf("expected the private repo to be redacted from the log, got %q", out)
+	}
+}
+
+func TestDetectPackageOvercommit_UnchangedDatesStillReported(t *testing.T) {
+	milestoneDue := time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC)
+	start, mean, end98 := testStart, testMean, testEnd98
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner: "owner", Repo: "repo", IssueNum: 1, State: "open",
+			Milestone: "v1.0.0", MilestoneDueDate: &milestoneDue,
+			ExpectedStart: &start, ExpectedCompletion: &mean, Completion98: &end98,
+			Project: &github.ProjectItemInfo{},
+		},
+	}
+	ganttData := planner.GanttData{
+		Bars: []planner.GanttBar{{ID: "owner/repo#1", ExpStartDate: start, MeanDate: mean, End98Date: end98}},
+	}
+
+	if updates := PrepareUpdates(ganttData, issues, nil); len(updates) != 0 {
+		t.Fatalf("expected no date changes, got %+v", updates)
+	}
+	overcommitted := DetectPackageOvercommit(CurrentSchedule(ganttData, issues), issues)
+	if len(overcommitted) != 1 || overcommitted[0].Reason != "package_overcommit" {
+		t.Errorf("expected the milestone to be reported overcommitted, got %+v", overcommitted)
+	}
+}
@@ -0,0 +1,128 @@
+package p2
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/octoberswimmer/p2/planner"
+	"github.com/octoberswimmer/p2/planner/lseq"
+)
+
+// splitPartSeparator separates a task ID from its part number in split task IDs
+const splitPartSeparator = "/part"
+
+// splitPartName matches the suffix SplitLargeTasks adds to part names
+var splitPartName = regexp.MustCompile(` \(part \d+/\d+\)$`)
+
+// SplitLargeTasks splits each open, schedulable task whose high estimate
+// exceeds threshold into the fewest sequential parts with high estimates of at
+// most threshold, so the scheduler can interleave them with other work. The
+// first part inherits the task's dependencies, each later part depends on the
+// one before, and dependents of the task depend on its last part. The split
+// only affects scheduling; use MergeSplitBars to recombine the results.
+// A threshold of zero or less returns tasks unchanged.
+func SplitLargeTasks(tasks []planner.Task, threshold float64) []planner.Task {
+	if threshold <= 0 {
+		return tasks
+	}
+
+	lastPart := make(map[string]string)
+	var split []planner.Task
+	for _, task := range tasks {
+		if task.Done || task.OnHold || task.EstimateHigh <= threshold {
+			split = append(split, task)
+			continue
+		}
+
+		n := int(math.Ceil(task.EstimateHigh / threshold))
+		prev := ""
+		for k := 1; k <= n; k++ {
+			part := task
+			part.ID = fmt.Sprintf("%s%s%d", task.ID, splitPartSeparator, k)
+			part.Name = fmt.Sprintf("%s (part %d/%d)", task.Name, k, n)
+			part.EstimateLow = task.EstimateLow / float64(n)
+			part.EstimateHigh = task.EstimateHigh / float64(n)
+			if prev != "" {
+				part.DependsOn = []string{prev}
+			}
+			split = append(split, part)
+			prev = part.ID
+		}
+		lastPart[task.ID] = prev
+	}
+
+	if len(lastPart) == 0 {
+		return tasks
+	}
+
+	// Point dependents at the last part of each split task
+	for i := range split {
+		deps := make([]string, len(split[i].DependsOn))
+		for j, dep := range split[i].DependsOn {
+			if last, ok := lastPart[dep]; ok {
+				dep = last
+			}
+			deps[j] = dep
+		}
+		split[i].DependsOn = deps
+	}
+
+	gen := lseq.NewGenerator("scheduler")
+	for j := range split {
+		seq, _ := gen.After(split[max(0, j-1)].Sequence)
+		split[j].Sequence = seq
+	}
+	return split
+}
+
+// MergeSplitBars recombines the bars of tasks split by SplitLargeTasks into a
+// single bar per task, spanning the first part's Expected Start to the last
+// part's completion dates
+func MergeSplitBars(ganttData *planner.GanttData) {
+	merged := make(map[string]int)
+	var bars []planner.GanttBar
+	for _, bar := range ganttData.Bars {
+		id, ok := splitTaskID(bar.ID)
+		if bar.IsPackage || !ok {
+			bars = append(bars, bar)
+			continue
+		}
+
+		idx, seen := merged[id]
+		if !seen {
+			bar.ID = id
+			bar.Name = splitPartName.ReplaceAllString(bar.Name, "")
+			merged[id] = len(bars)
+			bars = append(bars, bar)
+			continue
+		}
+
+		m := &bars[idx]
+		if bar.ExpStartDate.Before(m.ExpStartDate) {
+			m.ExpStartDate = bar.ExpStartDate
+		}
+		if bar.MeanDate.After(m.MeanDate) {
+			m.MeanDate = bar.MeanDate
+		}
+		if bar.End98Date.After(m.End98Date) {
+			m.End98Date = bar.End98Date
+		}
+	}
+	ganttData.Bars = bars
+}
+
+// splitTaskID returns the original task ID of a split part ID
+func splitTaskID(id string) (string, bool) {
+	idx := strings.LastIndex(id, splitPartSeparator)
+	if idx < 0 {
+		return "", false
+	}
+	for _, c := range id[idx+len(splitPartSeparator):] {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	return id[:idx], idx+len(splitPartSeparator) < len(id)
+}
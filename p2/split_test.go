@@ -0,0 +1,141 @@
+package p2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/github"
+	"github.com/octoberswimmer/p2/planner"
+)
+
+func TestSplitLargeTasks_SpreadAcrossAvailableDays(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			Title:        "Big Task",
+			State:        "open",
+			Assignee:     "cwarden",
+			LowEstimate:  ptr(60),
+			HighEstimate: ptr(80),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  2,
+			Title:     "Follow-up",
+			State:     "open",
+			Assignee:  "cwarden",
+			BlockedBy: []IssueRef{{Owner: "owner", Repo: "repo", Number: 1}},
+		},
+	}
+	tasks, _, _ := IssuesToTasks(issues, nil)
+
+	split := SplitLargeTasks(tasks, 40)
+	if len(split) != 3 {
+		t.Fatalf("expected the 80h task to split into 2 parts plus the follow-up, got %d tasks", len(split))
+	}
+	parts := make(map[string]planner.Task)
+	for _, task := range split {
+		parts[task.ID] = task
+	}
+	first, second := parts["owner/repo#1/part1"], parts["owner/repo#1/part2"]
+	if first.EstimateHigh != 40 || first.EstimateLow != 30 || second.EstimateHigh != 40 {
+		t.Errorf("expected parts of 30-40, got %g-%g and %g-%g", first.EstimateLow, first.EstimateHigh, second.EstimateLow, second.EstimateHigh)
+	}
+	if len(second.DependsOn) != 1 || second.DependsOn[0] != "owner/repo#1/part1" {
+		t.Errorf("expected part 2 to follow part 1, got %v", second.DependsOn)
+	}
+	if deps := parts["owner/repo#2"].DependsOn; len(deps) != 1 || deps[0] != "owner/repo#1/part2" {
+		t.Errorf("expected follow-up to depend on the last part, got %v", deps)
+	}
+
+	base := time.Date(2026, time.February, 2, 0, 0, 0, 0, time.UTC)
+	entries := planner.ScheduleWithUsers(split, nil)
+	ganttData, err := planner.ComputeGanttData(entries, split, true, base, nil)
+	if err != nil {
+		t.Fatalf("ComputeGanttData: %v", err)
+	}
+	MergeSplitBars(&ganttData)
+
+	bars := make(map[string]planner.GanttBar)
+	for _, bar := range ganttData.Bars {
+		if !bar.IsPackage {
+			bars[bar.ID] = bar
+		}
+	}
+	if len(bars) != 2 {
+		t.Fatalf("expected parts to merge into one bar per issue, got %+v", ganttData.Bars)
+	}
+	merged, ok := bars["owner/repo#1"]
+	if !ok || merged.Name != "Big Task" {
+		t.Fatalf("expected merged bar for owner/repo#1 named %q, got %+v", "Big Task", ganttData.Bars)
+	}
+	if merged.ExpStartDate.Before(base) || !merged.MeanDate.After(merged.ExpStartDate) {
+		t.Errorf("expected merged bar to span from its first part to its last, got %s - %s",
+			merged.ExpStartDate.Format("2006-01-02"), merged.MeanDate.Format("2006-01-02"))
+	}
+	if followUp := bars["owner/repo#2"]; followUp.ExpStartDate.Before(merged.MeanDate) {
+		t.Errorf("expected follow-up to start after the last part (%s), got %s",
+			merged.MeanDate.Format("2006-01-02"), followUp.ExpStartDate.Format("2006-01-02"))
+	}
+
+	updates := PrepareUpdates(ganttData, map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {Owner: "owner", Repo: "repo", IssueNum: 1, State: "open", Project: &github.ProjectItemInfo{}},
+	}, nil)
+	if len(updates) != 1 || !updates[0].ExpectedStart.Equal(merged.ExpStartDate) || !updates[0].ExpectedCompletion.Equal(merged.MeanDate) {
+		t.Errorf("expected the update to span the whole issue, got %+v", updates)
+	}
+}
+
+func TestSplitLargeTasks_CycleReportedAgainstIssues(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			Title:        "Big Task",
+			State:        "open",
+			LowEstimate:  ptr(60),
+			HighEstimate: ptr(80),
+			BlockedBy:    []IssueRef{{Owner: "owner", Repo: "repo", Number: 2}},
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:     "owner",
+			Repo:      "repo",
+			IssueNum:  2,
+			Title:     "Follow-up",
+			State:     "open",
+			BlockedBy: []IssueRef{{Owner: "owner", Repo: "repo", Number: 1}},
+		},
+	}
+	tasks, _, _ := IssuesToTasks(issues, nil)
+
+	split := SplitLargeTasks(tasks, 40)
+	schedIssues := ExtractCycleIssues(planner.ScheduleWithUsers(split, nil), issues, nil)
+
+	reported := make(map[int]SchedulingIssue)
+	for _, si := range schedIssues {
+		reported[si.IssueNum] = si
+	}
+	for _, num := range []int{1, 2} {
+		si, ok := reported[num]
+		if !ok || si.Reason != "cycle" {
+			t.Errorf("expected #%d to get a cycle notice, got %+v", num, schedIssues)
+			continue
+		}
+		for _, id := range si.Details {
+			if _, isPart := splitTaskID(id); isPart {
+				t.Errorf("expected cycle path of #%d to name issues, not parts, got %v", num, si.Details)
+			}
+		}
+	}
+}
+
+func TestSplitLargeTasks_BelowThresholdUnchanged(t *testing.T) {
+	tasks := []planner.Task{{ID: "owner/repo#1", EstimateLow: 2, EstimateHigh: 4}}
+	if split := SplitLargeTasks(tasks, 40); len(split) != 1 || split[0].ID != "owner/repo#1" {
+		t.Errorf("expected task to be unchanged, got %+v", split)
+	}
+}
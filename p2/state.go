@@ -0,0 +1,183 @@
+package p2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RunState is persisted between runs to detect what changed
+type RunState struct {
+	// InputHash is the InputHash of the issues scheduled by the last run
+	InputHash string `json:"input_hash"`
+
+	// SchedulingIssues are the SchedulingIssueKeys reported by the last run
+	SchedulingIssues []string `json:"scheduling_issues,omitempty"`
+}
+
+// LoadRunState reads the state file at path. A missing file yields an empty state.
+func LoadRunState(path string) (*RunState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &RunState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	var state RunState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// SaveRunState writes state to path
+func SaveRunState(path string, state *RunState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// InputHash returns a hash of the scheduling-relevant inputs: each issue's
+// state, status, estimates, assignee, order, milestone, due dates,
+// dependencies, and currently stored dates, the scheduling base day (so dates
+// still advance once per day), and settings, the run's resolved options and
+// the contents of the files they name. settings must be JSON-encodable.
+func InputHash(issues map[string]IssueWithProject, base time.Time, settings interface{}) (string, error) {
+	encoded, err := json.Marshal(settings)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode settings for the input hash: %w", err)
+	}
+
+	refs := make([]string, 0, len(issues))
+	for ref := range issues {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "base=%s\n", base.Format("2006-01-02"))
+	fmt.Fprintf(h, "settings=%s\n", encoded)
+	for _, ref := range refs {
+		iwp := issues[ref]
+
+		blockers := make([]string, 0, len(iwp.BlockedBy))
+		for _, b := range iwp.BlockedBy {
+			blockers = append(blockers, fmt.Sprintf("%s/%s#%d:%s", b.Owner, b.Repo, b.Number, strings.ToLower(b.State)))
+		}
+		sort.Strings(blockers)
+
+		fmt.Fprintf(h, "%s|%s|%s|%s|%s|%s|%d|%s|%s|%s|%v|%d|%s|%s|%s|%s\n",
+			ref,
+			strings.ToLower(iwp.State),
+			iwp.SchedulingStatus,
+			hashFloat(iwp.LowEstimate),
+			hashFloat(iwp.HighEstimate),
+			iwp.Assignee,
+			iwp.Order,
+			iwp.Milestone,
+			hashDate(iwp.MilestoneDueDate),
+			hashDate(iwp.DueDate),
+			iwp.IsDraft,
+			iwp.InaccessibleBlockers,
+			strings.Join(blockers, ","),
+			hashDate(iwp.ExpectedStart),
+			hashDate(iwp.ExpectedCompletion),
+			hashDate(iwp.Completion98),
+		)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFloat(f *float64) string {
+	if f == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%g", *f)
+}
+
+func hashDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+// SchedulingIssueKeys returns a sorted, de-duplicated "ref reason" key per scheduling issue
+func SchedulingIssueKeys(schedIssues []SchedulingIssue) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, si := range schedIssues {
+		key := si.IssueRef + " " + si.Reason
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SchedulingIssueDelta summarizes how scheduling issues changed between two
+// sets of SchedulingIssueKeys, e.g. "+2 missing_estimate, -1 cycle".
+// Additions come before removals, each sorted by reason. Returns "" if nothing changed.
+func SchedulingIssueDelta(previous, current []string) string {
+	prevSet := make(map[string]bool, len(previous))
+	for _, k := range previous {
+		prevSet[k] = true
+	}
+	curSet := make(map[string]bool, len(current))
+	for _, k := range current {
+		curSet[k] = true
+	}
+
+	added := make(map[string]int)
+	removed := make(map[string]int)
+	for k := range curSet {
+		if !prevSet[k] {
+			added[keyReason(k)]++
+		}
+	}
+	for k := range prevSet {
+		if !curSet[k] {
+			removed[keyReason(k)]++
+		}
+	}
+
+	var parts []string
+	parts = append(parts, formatDeltaCounts("+", added)...)
+	parts = append(parts, formatDeltaCounts("-", removed)...)
+	return strings.Join(parts, ", ")
+}
+
+// keyReason returns the reason part of a SchedulingIssueKeys key
+func keyReason(key string) string {
+	if i := strings.LastIndex(key, " "); i >= 0 {
+		return key[i+1:]
+	}
+	return key
+}
+
+func formatDeltaCounts(sign string, counts map[string]int) []string {
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	parts := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		parts = append(parts, fmt.Sprintf("%s%d %s", sign, counts[reason], reason))
+	}
+	return parts
+}
@@ -0,0 +1,126 @@
+package p2
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testStateIssues() map[string]IssueWithProject {
+	return map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			Title:        "Task",
+			State:        "open",
+			Assignee:     "cwarden",
+			LowEstimate:  ptr(2),
+			HighEstimate: ptr(4),
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:    "owner",
+			Repo:     "repo",
+			IssueNum: 2,
+			Title:    "Blocked Task",
+			State:    "open",
+			Order:    1,
+			BlockedBy: []IssueRef{
+				{Owner: "owner", Repo: "repo", Number: 1},
+			},
+		},
+	}
+}
+
+func TestInputHash_IdenticalInputsSkipAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	base := time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC)
+
+	// First run: no state yet, so the hash differs and the run proceeds
+	state, err := LoadRunState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hash := mustInputHash(t, testStateIssues(), base, nil)
+	if state.InputHash == hash {
+		t.Fatal("expected first run to proceed with no prior state")
+	}
+	state.InputHash = hash
+	if err := SaveRunState(path, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Second run later the same day with identical inputs: skipped
+	state, err = LoadRunState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.InputHash != mustInputHash(t, testStateIssues(), base.Add(time.Hour), nil) {
+		t.Error("expected identical inputs to produce the same hash")
+	}
+
+	// Changed estimate forces a full run
+	changed := testStateIssues()
+	iwp := changed["github.com/owner/repo/issues/1"]
+	iwp.HighEstimate = ptr(8)
+	changed["github.com/owner/repo/issues/1"] = iwp
+	if state.InputHash == mustInputHash(t, changed, base, nil) {
+		t.Error("expected a changed estimate to change the hash")
+	}
+}
+
+func TestInputHash_NewDayChangesHash(t *testing.T) {
+	base := time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC)
+	if mustInputHash(t, testStateIssues(), base, nil) == mustInputHash(t, testStateIssues(), base.AddDate(0, 0, 1), nil) {
+		t.Error("expected a new scheduling day to change the hash")
+	}
+}
+
+func TestInputHash_ChangedOptionChangesHash(t *testing.T) {
+	base := time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC)
+	opts := ConvertOptions{EstimateMultiplier: 1}
+	before := mustInputHash(t, testStateIssues(), base, opts)
+	if again := mustInputHash(t, testStateIssues(), base, opts); again != before {
+		t.Error("expected identical options to produce the same hash")
+	}
+
+	opts.EstimateMultiplier = 1.5
+	if mustInputHash(t, testStateIssues(), base, opts) == before {
+		t.Error("expected a changed estimate multiplier to change the hash")
+	}
+
+	opts.EstimateMultiplier = 1
+	opts.Availability = &Availability{Holidays: []Holiday{{Date: base.AddDate(0, 0, 3)}}}
+	if mustInputHash(t, testStateIssues(), base, opts) == before {
+		t.Error("expected an added holiday to change the hash")
+	}
+}
+
+func mustInputHash(t *testing.T, issues map[string]IssueWithProject, base time.Time, settings interface{}) string {
+	t.Helper()
+	hash, err := InputHash(issues, base, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return hash
+}
+
+func TestSchedulingIssueDelta(t *testing.T) {
+	previous := SchedulingIssueKeys([]SchedulingIssue{
+		{IssueRef: "github.com/owner/repo/issues/1", Reason: "cycle"},
+		{IssueRef: "github.com/owner/repo/issues/2", Reason: "missing_estimate"},
+	})
+	current := SchedulingIssueKeys([]SchedulingIssue{
+		{IssueRef: "github.com/owner/repo/issues/2", Reason: "missing_estimate"},
+		{IssueRef: "github.com/owner/repo/issues/3", Reason: "missing_estimate"},
+		{IssueRef: "github.com/owner/repo/issues/4", Reason: "missing_estimate"},
+		{IssueRef: "github.com/owner/repo/issues/4", Reason: "missing_estimate"},
+	})
+
+	if got, want := SchedulingIssueDelta(previous, current), "+2 missing_estimate, -1 cycle"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := SchedulingIssueDelta(current, current); got != "" {
+		t.Errorf("expected no delta for identical sets, got %q", got)
+	}
+}
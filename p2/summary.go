@@ -0,0 +1,72 @@
+package p2
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteSummary writes a compact rollup of a run: how many issues were
+// scheduled, how many updates wrote dates or cleared them (by reason), and
+// how many scheduling issues (by reason) and at-risk warnings were found.
+// delayed is the number of issues whose start was pushed back by their
+// Earliest Start, reported only when non-zero. applied holds the updates written to GitHub; if dryRun is set it should
+// hold every planned update, which are reported as "would apply".
+func WriteSummary(w io.Writer, scheduled, delayed int, applied []DateUpdate, schedIssues []SchedulingIssue, dryRun bool) error {
+	writes := 0
+	cleared := make(map[string]int)
+	for _, u := range applied {
+		if u.ClearDates {
+			cleared[u.ClearReason]++
+		} else {
+			writes++
+		}
+	}
+	atRisk := 0
+	problems := make(map[string]int)
+	for _, si := range schedIssues {
+		if si.Reason == "at_risk" {
+			atRisk++
+		} else {
+			problems[si.Reason]++
+		}
+	}
+
+	verb := "applied"
+	if dryRun {
+		verb = "would apply"
+	}
+	lines := []string{
+		"Summary:",
+		fmt.Sprintf("  Scheduled: %d issues", scheduled),
+		fmt.Sprintf("  Date writes: %d %s", writes, verb),
+		fmt.Sprintf("  Cleared: %s", countsByKey(cleared)),
+		fmt.Sprintf("  Scheduling issues: %s", countsByKey(problems)),
+		fmt.Sprintf("  At risk: %d", atRisk),
+	}
+	if delayed > 0 {
+		lines = append(lines, fmt.Sprintf("  Delayed by %s: %d", EarliestStartField, delayed))
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// countsByKey formats a total followed by per-key counts, e.g. "3 (closed 2, on hold 1)"
+func countsByKey(counts map[string]int) string {
+	total := 0
+	keys := make([]string, 0, len(counts))
+	for k, n := range counts {
+		keys = append(keys, k)
+		total += n
+	}
+	if total == 0 {
+		return "0"
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s %d", k, counts[k])
+	}
+	return fmt.Sprintf("%d (%s)", total, strings.Join(parts, ", "))
+}
@@ -0,0 +1,61 @@
+package p2
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteSummary(t *testing.T) {
+	updates := []DateUpdate{
+		{IssueNum: 1},
+		{IssueNum: 2},
+		{IssueNum: 3, ClearDates: true, ClearReason: "closed"},
+		{IssueNum: 4, ClearDates: true, ClearReason: "on hold"},
+		{IssueNum: 5, ClearDates: true, ClearReason: "closed"},
+	}
+	schedIssues := []SchedulingIssue{
+		{IssueNum: 6, Reason: "missing_estimate"},
+		{IssueNum: 7, Reason: "cycle"},
+		{IssueNum: 8, Reason: "missing_estimate"},
+		{IssueNum: 1, Reason: "at_risk"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSummary(&buf, 4, 0, updates, schedIssues, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `Summary:
+  Scheduled: 4 issues
+  Date writes: 2 would apply
+  Cleared: 3 (closed 2, on hold 1)
+  Scheduling issues: 3 (cycle 1, missing_estimate 2)
+  At risk: 1
+`
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteSummary(&buf, 0, 0, nil, nil, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = `Summary:
+  Scheduled: 0 issues
+  Date writes: 0 applied
+  Cleared: 0
+  Scheduling issues: 0
+  At risk: 0
+`
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteSummary(&buf, 2, 1, nil, nil, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "  At risk: 0\n  Delayed by Earliest Start: 1\n") {
+		t.Errorf("expected the Earliest Start delay count last, got:\n%s", buf.String())
+	}
+}
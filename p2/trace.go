@@ -0,0 +1,67 @@
+package p2
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/octoberswimmer/p2/planner"
+)
+
+// TraceLines returns one compact line per task summarizing how its issue was
+// converted: resolved estimate, assignee, dependency count, package order, and
+// on-hold/closed/draft flags. Lines are sorted by issue ref so that output
+// from two runs diffs cleanly. If privacy is non-nil, private task IDs are redacted.
+func TraceLines(tasks []planner.Task, privacy *PrivacyFilter) []string {
+	sorted := make([]planner.Task, len(tasks))
+	copy(sorted, tasks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return traceRef(sorted[i]) < traceRef(sorted[j])
+	})
+
+	lines := make([]string, 0, len(sorted))
+	for _, task := range sorted {
+		id := task.ID
+		if privacy != nil {
+			id = privacy.RedactDepID(id)
+		}
+
+		var flags []string
+		if task.OnHold {
+			flags = append(flags, "on_hold")
+		}
+		if task.Done {
+			flags = append(flags, "closed")
+		}
+		if strings.HasPrefix(task.ID, "draft:") {
+			flags = append(flags, "draft")
+		}
+		flagStr := "-"
+		if len(flags) > 0 {
+			flagStr = strings.Join(flags, ",")
+		}
+
+		lines = append(lines, fmt.Sprintf("%s estimate=%g-%g assignee=%s deps=%d package_order=%d flags=%s",
+			id, task.EstimateLow, task.EstimateHigh, task.User, len(task.DependsOn), task.PackageOrder, flagStr))
+	}
+	return lines
+}
+
+// WriteTrace writes TraceLines to w, one per line
+func WriteTrace(w io.Writer, tasks []planner.Task, privacy *PrivacyFilter) error {
+	for _, line := range TraceLines(tasks, privacy) {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// traceRef returns the issue ref a task was created from, falling back to its ID
+func traceRef(task planner.Task) string {
+	if len(task.Ref) > 0 {
+		return task.Ref[0]
+	}
+	return task.ID
+}
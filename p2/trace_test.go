@@ -0,0 +1,50 @@
+package p2
+
+import (
+	"testing"
+)
+
+func TestTraceLines_KnownIssueFields(t *testing.T) {
+	issues := map[string]IssueWithProject{
+		"github.com/owner/repo/issues/1": {
+			Owner:        "owner",
+			Repo:         "repo",
+			IssueNum:     1,
+			Title:        "Blocker",
+			State:        "open",
+			Assignee:     "cwarden",
+			LowEstimate:  ptr(2),
+			HighEstimate: ptr(6),
+			Milestone:    "v1.0.0",
+			Order:        1,
+		},
+		"github.com/owner/repo/issues/2": {
+			Owner:            "owner",
+			Repo:             "repo",
+			IssueNum:         2,
+			Title:            "Blocked",
+			State:            "open",
+			SchedulingStatus: "On Hold",
+			Order:            0,
+			BlockedBy: []IssueRef{
+				{Owner: "owner", Repo: "repo", Number: 1},
+			},
+		},
+	}
+
+	tasks, _, _ := IssuesToTasks(issues, nil)
+	lines := TraceLines(tasks, nil)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 trace lines, got %d: %v", len(lines), lines)
+	}
+
+	// Sorted by ref, not by project order
+	want := "owner/repo#1 estimate=2-6 assignee=cwarden deps=0 package_order=0 flags=-"
+	if lines[0] != want {
+		t.Errorf("unexpected trace line:\n got: %q\nwant: %q", lines[0], want)
+	}
+	want = "owner/repo#2 estimate=1-4 assignee=unassigned deps=1 package_order=1 flags=on_hold"
+	if lines[1] != want {
+		t.Errorf("unexpected trace line:\n got: %q\nwant: %q", lines[1], want)
+	}
+}
@@ -8,3 +8,4 @@ import (
 type IssueWithProject = github.IssueWithProject
 type DateUpdate = github.DateUpdate
 type SchedulingIssue = github.SchedulingIssue
+type IssueRef = github.IssueRef
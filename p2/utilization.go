@@ -0,0 +1,140 @@
+package p2
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+	"github.com/octoberswimmer/p2/recfile"
+)
+
+// UtilizationRow is one user's scheduled hours against their available hours for a week
+type UtilizationRow struct {
+	User string
+	// Week is the Monday the week starts on
+	Week      time.Time
+	Scheduled float64
+	Available float64
+}
+
+// Percent returns scheduled hours as a percentage of available hours.
+// Over 100 means the user is over-allocated that week.
+func (r UtilizationRow) Percent() float64 {
+	if r.Available == 0 {
+		return 0
+	}
+	return r.Scheduled / r.Available * 100
+}
+
+// Utilization spreads each open task's expected hours (the midpoint of its
+// estimate) across the working days from its Expected Start to its Expected
+// Completion, weighted by the assignee's hours on each day, and totals them
+// per user per week. Rows are sorted by user, then week.
+func Utilization(ganttData planner.GanttData, tasks []planner.Task, users []recfile.User) []UtilizationRow {
+	taskByID := make(map[string]planner.Task, len(tasks))
+	for _, t := range tasks {
+		taskByID[t.ID] = t
+	}
+	userByID := make(map[string]recfile.User, len(users))
+	for _, u := range users {
+		userByID[u.ID] = u
+	}
+
+	type key struct {
+		user string
+		week time.Time
+	}
+	scheduled := make(map[key]float64)
+
+	for _, bar := range ganttData.Bars {
+		if bar.IsPackage || bar.Done || bar.OnHold || bar.ExpStartDate.IsZero() {
+			continue
+		}
+		task, ok := taskByID[bar.ID]
+		if !ok {
+			continue
+		}
+		hours := (task.EstimateLow + task.EstimateHigh) / 2
+		if hours <= 0 {
+			continue
+		}
+		user := userByID[task.User]
+
+		var days []time.Time
+		var capacity float64
+		for d := truncateDay(bar.ExpStartDate); !d.After(truncateDay(bar.MeanDate)); d = d.AddDate(0, 0, 1) {
+			if h := dayHours(user, d.Weekday()); h > 0 {
+				days = append(days, d)
+				capacity += h
+			}
+		}
+		if len(days) == 0 {
+			scheduled[key{task.User, weekStart(bar.ExpStartDate)}] += hours
+			continue
+		}
+		for _, d := range days {
+			scheduled[key{task.User, weekStart(d)}] += hours * dayHours(user, d.Weekday()) / capacity
+		}
+	}
+
+	rows := make([]UtilizationRow, 0, len(scheduled))
+	for k, h := range scheduled {
+		user := userByID[k.user]
+		available := user.MondayHours + user.TuesdayHours + user.WednesdayHours + user.ThursdayHours + user.FridayHours
+		rows = append(rows, UtilizationRow{User: k.user, Week: k.week, Scheduled: h, Available: available})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].User != rows[j].User {
+			return rows[i].User < rows[j].User
+		}
+		return rows[i].Week.Before(rows[j].Week)
+	})
+	return rows
+}
+
+// dayHours returns the hours user is available on weekday
+func dayHours(user recfile.User, weekday time.Weekday) float64 {
+	switch weekday {
+	case time.Monday:
+		return user.MondayHours
+	case time.Tuesday:
+		return user.TuesdayHours
+	case time.Wednesday:
+		return user.WednesdayHours
+	case time.Thursday:
+		return user.ThursdayHours
+	case time.Friday:
+		return user.FridayHours
+	}
+	return 0
+}
+
+// truncateDay returns midnight UTC on t's date
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// WriteUtilizationCSV writes rows as CSV with a header line
+func WriteUtilizationCSV(w io.Writer, rows []UtilizationRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"User", "Week", "Scheduled Hours", "Available Hours", "Utilization"}); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		record := []string{
+			r.User,
+			formatDate(r.Week),
+			strconv.FormatFloat(r.Scheduled, 'f', 1, 64),
+			strconv.FormatFloat(r.Available, 'f', 1, 64),
+			strconv.FormatFloat(r.Percent(), 'f', 0, 64) + "%",
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,65 @@
+package p2
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/octoberswimmer/p2/planner"
+	"github.com/octoberswimmer/p2/recfile"
+)
+
+func TestUtilization_OverAllocatedWeek(t *testing.T) {
+	monday := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	tasks := []planner.Task{
+		{ID: "owner/repo#1", EstimateLow: 20, EstimateHigh: 20, User: "alice"},
+		{ID: "owner/repo#2", EstimateLow: 30, EstimateHigh: 30, User: "alice"},
+	}
+	ganttData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: monday, MeanDate: monday.AddDate(0, 0, 2)},
+		{ID: "owner/repo#2", ExpStartDate: monday.AddDate(0, 0, 2), MeanDate: monday.AddDate(0, 0, 4)},
+	}}
+	users := []recfile.User{{ID: "alice", MondayHours: 8, TuesdayHours: 8, WednesdayHours: 8, ThursdayHours: 8, FridayHours: 8}}
+
+	rows := Utilization(ganttData, tasks, users)
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d: %+v", len(rows), rows)
+	}
+	r := rows[0]
+	if r.User != "alice" || !r.Week.Equal(monday) {
+		t.Errorf("expected alice for week of %s, got %s for %s", formatDate(monday), r.User, formatDate(r.Week))
+	}
+	if r.Scheduled != 50 || r.Available != 40 {
+		t.Errorf("expected 50 of 40 hours, got %v of %v", r.Scheduled, r.Available)
+	}
+	if r.Percent() != 125 {
+		t.Errorf("expected 125%% utilization, got %v", r.Percent())
+	}
+
+	var buf bytes.Buffer
+	if err := WriteUtilizationCSV(&buf, rows); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "alice,2025-03-03,50.0,40.0,125%") {
+		t.Errorf("unexpected CSV output:\n%s", buf.String())
+	}
+}
+
+func TestUtilization_SpansWeeks(t *testing.T) {
+	thursday := time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC)
+	tasks := []planner.Task{{ID: "owner/repo#1", EstimateLow: 16, EstimateHigh: 32, User: "bob"}}
+	ganttData := planner.GanttData{Bars: []planner.GanttBar{
+		{ID: "owner/repo#1", ExpStartDate: thursday, MeanDate: thursday.AddDate(0, 0, 5)},
+	}}
+	users := []recfile.User{{ID: "bob", MondayHours: 8, TuesdayHours: 8, WednesdayHours: 8, ThursdayHours: 8, FridayHours: 8}}
+
+	rows := Utilization(ganttData, tasks, users)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d: %+v", len(rows), rows)
+	}
+	// Thu, Fri in the first week; Mon, Tue in the second
+	if rows[0].Scheduled != 12 || rows[1].Scheduled != 12 {
+		t.Errorf("expected 12 hours each week, got %v and %v", rows[0].Scheduled, rows[1].Scheduled)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/octoberswimmer/p2-github-scheduler/ghscheduler"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// maxWebhookBody is the largest delivery GitHub sends
+const maxWebhookBody = 25 << 20
+
+var (
+	serveAddr     string
+	webhookSecret string
+	debounceDelay time.Duration
+
+	serveCmd = &cobra.Command{
+		Use:   "serve <github-url>...",
+		Short: "Reschedule projects when GitHub webhook deliveries report changes",
+		Long: `Listens for GitHub webhook deliveries (projects_v2_item and issues
+events) and reschedules each given URL affected by them. Deliveries must be
+signed with the secret from --webhook-secret or P2_WEBHOOK_SECRET.
+
+Bursts of deliveries are debounced: a URL is rescheduled once no delivery
+has affected it for --debounce. Runs are serialized, and deliveries that
+arrive while a URL is waiting to run are folded into that run.
+
+Scheduling flags (--dry-run, --users-file, ...) apply to every run.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runServe,
+	}
+)
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8080", "Address to listen for webhook deliveries on")
+	serveCmd.Flags().StringVar(&webhookSecret, "webhook-secret", "", "Secret the webhook signs deliveries with (default $P2_WEBHOOK_SECRET)")
+	serveCmd.Flags().DurationVar(&debounceDelay, "debounce", 30*time.Second, "Wait this long after the last delivery before rescheduling")
+	serveCmd.Flags().AddFlagSet(rootCmd.Flags())
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if err := applyConfigFile(cmd); err != nil {
+		return err
+	}
+	secret := webhookSecret
+	if secret == "" {
+		secret = os.Getenv("P2_WEBHOOK_SECRET")
+	}
+	if secret == "" {
+		return fmt.Errorf("--webhook-secret or P2_WEBHOOK_SECRET is required to verify deliveries")
+	}
+
+	urls := make([]string, len(args))
+	for i, arg := range args {
+		urls[i] = ghscheduler.NormalizeURL(arg)
+	}
+	r := newRescheduler(debounceDelay, func(url string) {
+		fmt.Printf("Rescheduling %s\n", url)
+		if err := run(cmd, []string{url}); err != nil && exitCode(err) != emptyExitCode {
+			logrus.Errorf("Rescheduling %s failed: %v", url, err)
+		}
+	})
+
+	fmt.Printf("Listening for webhook deliveries on %s\n", serveAddr)
+	server := &http.Server{
+		Addr:              serveAddr,
+		Handler:           webhookHandler(secret, urls, r.trigger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// webhookHandler verifies deliveries and triggers the URLs they affect
+func webhookHandler(secret string, urls []string, trigger func(url string)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxWebhookBody))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if !ghscheduler.VerifyWebhookSignature(secret, body, req.Header.Get("X-Hub-Signature-256")) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		event, ok, err := ghscheduler.ParseWebhookEvent(req.Header.Get("X-GitHub-Event"), body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		for _, url := range urls {
+			if event.Affects(url) {
+				trigger(url)
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// rescheduler coalesces triggers into runs. A URL runs once it hasn't been
+// triggered for delay. Runs are serialized because run works on the
+// package-level flag values; a URL already waiting for its turn isn't queued
+// again.
+type rescheduler struct {
+	delay time.Duration
+	run   func(url string)
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+	queued map[string]bool
+
+	runMu sync.Mutex
+}
+
+func newRescheduler(delay time.Duration, run func(url string)) *rescheduler {
+	return &rescheduler{
+		delay:  delay,
+		run:    run,
+		timers: make(map[string]*time.Timer),
+		queued: make(map[string]bool),
+	}
+}
+
+// trigger (re)starts url's debounce timer
+func (r *rescheduler) trigger(url string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.timers[url]; ok && t.Stop() {
+		t.Reset(r.delay)
+		return
+	}
+	var t *time.Timer
+	t = time.AfterFunc(r.delay, func() {
+		r.mu.Lock()
+		if r.timers[url] == t {
+			delete(r.timers, url)
+		}
+		r.mu.Unlock()
+		r.fire(url)
+	})
+	r.timers[url] = t
+}
+
+func (r *rescheduler) fire(url string) {
+	r.mu.Lock()
+	if r.queued[url] {
+		r.mu.Unlock()
+		return
+	}
+	r.queued[url] = true
+	r.mu.Unlock()
+
+	r.runMu.Lock()
+	defer r.runMu.Unlock()
+	// Triggers from here on need a fresh run, since this one may already
+	// have fetched the project
+	r.mu.Lock()
+	delete(r.queued, url)
+	r.mu.Unlock()
+	r.run(url)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/octoberswimmer/p2-github-scheduler/ghscheduler"
+	"github.com/octoberswimmer/p2-github-scheduler/p2"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <project-url>",
+	Short: "Check that a project has the fields p2 reads and writes",
+	Long: `Reads the fields defined on a project and reports any that p2 needs
+but that are missing or have the wrong type, using the names from --config
+and --field-name. No items are fetched and nothing is written.
+
+Exits non-zero if a required field (the estimates or the calculated dates)
+is missing or unusable. A missing Due Date or Scheduling Status field, or a
+Scheduling Status without an option for each --on-hold-status, is only
+reported.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().StringSliceVar(&onHoldStatuses, "on-hold-status", p2.DefaultOnHoldStatuses, "Scheduling Status values that mean on hold (e.g. Parked,Paused)")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	if err := applyConfigFile(cmd); err != nil {
+		return err
+	}
+	if err := configureLogging(); err != nil {
+		return err
+	}
+
+	project, ok := ghscheduler.ParseProjectURL(ghscheduler.NormalizeURL(args[0]))
+	if !ok {
+		return fmt.Errorf("invalid project URL %q: expected https://github.com/orgs/<org>/projects/<n>", args[0])
+	}
+	fieldNames, err := loadFieldNames()
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := authenticate()
+	if err != nil {
+		return err
+	}
+
+	fields, err := fetchProjectFields(accessToken, project)
+	if err != nil {
+		return err
+	}
+
+	problems := ghscheduler.CheckProjectFields(fields, fieldNames, onHoldStatuses)
+	if len(problems) == 0 {
+		fmt.Println("Project fields OK")
+		return nil
+	}
+	missing := 0
+	for _, p := range problems {
+		kind := "warning"
+		if p.Required {
+			kind = "error"
+			missing++
+		}
+		fmt.Printf("  %s: %s %s\n", kind, p.Field, p.Problem)
+	}
+	if missing > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d required project fields are missing or unusable", missing)
+	}
+	return nil
+}